@@ -0,0 +1,266 @@
+package frontend
+
+import (
+	"container/heap"
+	"encoding/json"
+	"hash/maphash"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxLoggedQueries is the maximum number of distinct queries tracked by
+	// a queryLog.
+	maxLoggedQueries = 10000
+
+	// queryScoreHalfLife is the time after which the popularity score of a
+	// logged query is halved so that queries that are no longer searched
+	// for make room for new ones.
+	queryScoreHalfLife = 24 * time.Hour
+)
+
+// queryLog keeps track of how many times each search query has returned
+// results and of the distinct clients that searched for it so that popular
+// past queries can be offered as completions. Clients are identified by a
+// hash of their address that is seeded randomly when the log is created; the
+// addresses themselves are never stored.
+//
+// Queries are ranked by a popularity score that decays over time. Once the
+// log is full, the query with the lowest score is evicted; ties are broken by
+// evicting the query that was searched for least recently. The queries are
+// kept in a min-heap ordered by score so that evictions are cheap.
+type queryLog struct {
+	// minClients is the number of distinct clients that must search for a
+	// query before it is offered as a completion.
+	minClients int
+	// maxQueries is the maximum number of distinct queries in the log.
+	maxQueries int
+	seed       maphash.Seed
+
+	mu      sync.Mutex
+	queries map[string]*loggedQuery
+	heap    queryHeap
+	// seq is incremented on each recorded query and orders the queries by
+	// the time they were last searched for.
+	seq uint64
+	// nextDecayAt is the time the scores of all queries are halved next.
+	nextDecayAt time.Time
+}
+
+type loggedQuery struct {
+	key  string
+	text string
+	// clients holds the hashed keys of the distinct clients that searched
+	// for the query. It is released once the query is offered.
+	clients map[uint64]struct{}
+	// offered is set once minClients distinct clients searched for the
+	// query.
+	offered bool
+	// score is the number of times the query has been recorded, halved
+	// every queryScoreHalfLife.
+	score    float64
+	lastSeen uint64
+	heapIdx  int
+}
+
+func newQueryLog(minClients int) *queryLog {
+	return &queryLog{
+		minClients:  minClients,
+		maxQueries:  maxLoggedQueries,
+		seed:        maphash.MakeSeed(),
+		queries:     make(map[string]*loggedQuery),
+		nextDecayAt: time.Now().Add(queryScoreHalfLife),
+	}
+}
+
+// record increments the popularity of the specified query that was searched
+// for by client. Queries that only differ in case or whitespace are tracked
+// as a single query.
+func (l *queryLog) record(query, client string) {
+	text := strings.Join(strings.Fields(query), " ")
+	if text == "" {
+		return
+	}
+	key := strings.ToLower(text)
+	var h maphash.Hash
+	h.SetSeed(l.seed)
+	_, _ = h.WriteString(client)
+	clientKey := h.Sum64()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.decayScores(time.Now())
+	l.seq++
+	q, found := l.queries[key]
+	if found {
+		q.score++
+		q.lastSeen = l.seq
+		heap.Fix(&l.heap, q.heapIdx)
+	} else {
+		if len(l.queries) >= l.maxQueries {
+			evicted := heap.Pop(&l.heap).(*loggedQuery)
+			delete(l.queries, evicted.key)
+		}
+		q = &loggedQuery{key: key, text: text, clients: make(map[uint64]struct{}), score: 1, lastSeen: l.seq}
+		l.queries[key] = q
+		heap.Push(&l.heap, q)
+	}
+
+	if !q.offered {
+		q.clients[clientKey] = struct{}{}
+		if len(q.clients) >= l.minClients {
+			q.offered, q.clients = true, nil
+		}
+	}
+}
+
+// decayScores halves the scores of all queries for each queryScoreHalfLife
+// that has elapsed since the last decay. Scaling all scores by the same
+// factor keeps the heap ordered. The caller must hold l.mu.
+func (l *queryLog) decayScores(now time.Time) {
+	if now.Before(l.nextDecayAt) {
+		return
+	}
+	factor := 1.0
+	for !now.Before(l.nextDecayAt) {
+		factor /= 2
+		l.nextDecayAt = l.nextDecayAt.Add(queryScoreHalfLife)
+	}
+	for _, q := range l.queries {
+		q.score *= factor
+	}
+}
+
+// complete returns up to n logged queries that start with prefix, ignoring
+// case. Only queries that have been searched for by at least l.minClients
+// distinct clients are returned. The most popular queries are returned first.
+func (l *queryLog) complete(prefix string, n int) []string {
+	prefix = strings.ToLower(strings.TrimLeft(prefix, " \t"))
+	if prefix == "" || n <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	var matches []*loggedQuery
+	for key, q := range l.queries {
+		if q.offered && strings.HasPrefix(key, prefix) {
+			matches = append(matches, &loggedQuery{text: q.text, score: q.score})
+		}
+	}
+	l.mu.Unlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].text < matches[j].text
+	})
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+
+	completions := make([]string, len(matches))
+	for i, q := range matches {
+		completions[i] = q.text
+	}
+	return completions
+}
+
+// queryHeap implements heap.Interface for logged queries. The query with the
+// lowest score and, among equal scores, the one searched for least recently
+// is at the top of the heap.
+type queryHeap []*loggedQuery
+
+func (h queryHeap) Len() int { return len(h) }
+
+func (h queryHeap) Less(i, j int) bool {
+	if h[i].score != h[j].score {
+		return h[i].score < h[j].score
+	}
+	return h[i].lastSeen < h[j].lastSeen
+}
+
+func (h queryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIdx = i
+	h[j].heapIdx = j
+}
+
+func (h *queryHeap) Push(x interface{}) {
+	q := x.(*loggedQuery)
+	q.heapIdx = len(*h)
+	*h = append(*h, q)
+}
+
+func (h *queryHeap) Pop() interface{} {
+	old := *h
+	q := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return q
+}
+
+// clientAddr returns the address that identifies the client that sent r in
+// the query log.
+func clientAddr(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// completionsResponse is the JSON response of the completion endpoint.
+type completionsResponse struct {
+	Query       string   `json:"query"`
+	Completions []string `json:"completions"`
+}
+
+// completeQuery responds with the completions for the partial search query
+// in the q parameter. Popular past queries are listed first, followed by the
+// titles of indexed documents.
+func (svc Service) completeQuery(w http.ResponseWriter, r *http.Request) {
+	prefix := r.URL.Query().Get("q")
+	completions := svc.queryLog.complete(prefix, svc.cfg.MaxCompletions)
+
+	if len(completions) < svc.cfg.MaxCompletions {
+		titles, err := svc.cfg.IndexAPI.Complete(r.Context(), prefix, svc.cfg.MaxCompletions)
+		if err != nil {
+			svc.cfg.Logger.WithField("err", err).Warn("could not look up title completions")
+		}
+		completions = mergeCompletions(completions, titles, svc.cfg.MaxCompletions)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(completionsResponse{
+		Query:       prefix,
+		Completions: completions,
+	})
+}
+
+// mergeCompletions appends the entries of extra that are not already present
+// in completions, ignoring case, until n completions have been collected.
+func mergeCompletions(completions, extra []string, n int) []string {
+	seen := make(map[string]bool, len(completions))
+	for _, c := range completions {
+		seen[strings.ToLower(c)] = true
+	}
+	for _, c := range extra {
+		if len(completions) >= n {
+			break
+		}
+		if key := strings.ToLower(c); !seen[key] {
+			seen[key] = true
+			completions = append(completions, c)
+		}
+	}
+	if completions == nil {
+		// Always respond with a JSON array.
+		completions = []string{}
+	}
+	return completions
+}
@@ -0,0 +1,184 @@
+package frontend
+
+import (
+	gc "gopkg.in/check.v1"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(CompletionsTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type CompletionsTestSuite struct{}
+
+type loggedSearch struct {
+	query, client string
+}
+
+func (s *CompletionsTestSuite) TestRecordAndComplete(c *gc.C) {
+	specs := []struct {
+		descr      string
+		minClients int
+		searches   []loggedSearch
+		prefix     string
+		n          int
+		exp        []string
+	}{
+		{
+			descr:      "offered once enough distinct clients searched for it",
+			minClients: 2,
+			searches:   []loggedSearch{{"golang", "10.0.0.1"}, {"golang", "10.0.0.2"}},
+			prefix:     "go",
+			n:          8,
+			exp:        []string{"golang"},
+		},
+		{
+			descr:      "repeated searches by a single client",
+			minClients: 2,
+			searches:   []loggedSearch{{"golang", "10.0.0.1"}, {"golang", "10.0.0.1"}, {"golang", "10.0.0.1"}},
+			prefix:     "go",
+			n:          8,
+			exp:        []string{},
+		},
+		{
+			descr:      "queries differing in case and whitespace",
+			minClients: 2,
+			searches:   []loggedSearch{{" Go  Lang ", "10.0.0.1"}, {"go lang", "10.0.0.2"}},
+			prefix:     "  GO l",
+			n:          8,
+			exp:        []string{"Go Lang"},
+		},
+		{
+			descr:      "ranked by popularity and then alphabetically",
+			minClients: 1,
+			searches: []loggedSearch{
+				{"go maps", "10.0.0.1"},
+				{"go tour", "10.0.0.1"}, {"go tour", "10.0.0.2"}, {"go tour", "10.0.0.3"},
+				{"go chan", "10.0.0.1"},
+				{"rust", "10.0.0.1"},
+			},
+			prefix: "go",
+			n:      2,
+			exp:    []string{"go tour", "go chan"},
+		},
+		{
+			descr:      "empty prefix",
+			minClients: 1,
+			searches:   []loggedSearch{{"golang", "10.0.0.1"}},
+			prefix:     " ",
+			n:          8,
+		},
+		{
+			descr:      "empty query",
+			minClients: 1,
+			searches:   []loggedSearch{{"  ", "10.0.0.1"}},
+			prefix:     "g",
+			n:          8,
+			exp:        []string{},
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		l := newQueryLog(spec.minClients)
+		for _, search := range spec.searches {
+			l.record(search.query, search.client)
+		}
+		c.Assert(l.complete(spec.prefix, spec.n), gc.DeepEquals, spec.exp)
+	}
+}
+
+func (s *CompletionsTestSuite) TestEviction(c *gc.C) {
+	l := newQueryLog(1)
+	l.maxQueries = 3
+	for _, query := range []string{"a", "a", "b", "c", "d"} {
+		l.record(query, "10.0.0.1")
+	}
+
+	// b and c share the lowest score but b was searched for less recently.
+	assertLoggedScores(c, l, map[string]float64{"a": 2, "c": 1, "d": 1})
+
+	// Scores are halved once the half-life elapses; the new query evicts c
+	// whose decayed score ties with d.
+	l.nextDecayAt = time.Now().Add(-time.Minute)
+	l.record("e", "10.0.0.1")
+	assertLoggedScores(c, l, map[string]float64{"a": 1, "d": 0.5, "e": 1})
+	c.Assert(l.nextDecayAt.After(time.Now()), gc.Equals, true)
+
+	// Scores are halved once for each elapsed half-life.
+	l.nextDecayAt = time.Now().Add(-2*queryScoreHalfLife - time.Minute)
+	l.record("e", "10.0.0.1")
+	assertLoggedScores(c, l, map[string]float64{"a": 0.125, "d": 0.0625, "e": 1.125})
+
+	// Searching for a query again protects it from eviction.
+	l.record("d", "10.0.0.1")
+	l.record("f", "10.0.0.1")
+	assertLoggedScores(c, l, map[string]float64{"d": 1.0625, "e": 1.125, "f": 1})
+}
+
+func (s *CompletionsTestSuite) TestMergeCompletions(c *gc.C) {
+	specs := []struct {
+		descr       string
+		completions []string
+		extra       []string
+		n           int
+		exp         []string
+	}{
+		{
+			descr:       "duplicates are skipped ignoring case",
+			completions: []string{"Go Tour"},
+			extra:       []string{"go tour", "Golang", "GOLANG"},
+			n:           8,
+			exp:         []string{"Go Tour", "Golang"},
+		},
+		{
+			descr:       "at most n completions",
+			completions: []string{"a"},
+			extra:       []string{"b", "c"},
+			n:           2,
+			exp:         []string{"a", "b"},
+		},
+		{
+			descr:       "completions are never truncated",
+			completions: []string{"a", "b"},
+			extra:       []string{"c"},
+			n:           1,
+			exp:         []string{"a", "b"},
+		},
+		{
+			descr: "no completions",
+			n:     8,
+			exp:   []string{},
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		c.Assert(mergeCompletions(spec.completions, spec.extra, spec.n), gc.DeepEquals, spec.exp)
+	}
+}
+
+func (s *CompletionsTestSuite) TestClientAddr(c *gc.C) {
+	r := httptest.NewRequest("GET", "/search?q=golang", nil)
+	r.RemoteAddr = "192.0.2.1:4711"
+	c.Assert(clientAddr(r), gc.Equals, "192.0.2.1")
+	r.RemoteAddr = "192.0.2.1"
+	c.Assert(clientAddr(r), gc.Equals, "192.0.2.1")
+}
+
+// assertLoggedScores checks that l holds exactly the queries in exp with the
+// specified scores and that the heap indices of the queries are consistent.
+func assertLoggedScores(c *gc.C, l *queryLog, exp map[string]float64) {
+	scores := make(map[string]float64, len(l.queries))
+	for key, q := range l.queries {
+		scores[key] = q.score
+	}
+	c.Assert(scores, gc.DeepEquals, exp)
+	c.Assert(l.heap, gc.HasLen, len(l.queries))
+	for i, q := range l.heap {
+		c.Assert(q.heapIdx, gc.Equals, i)
+		c.Assert(l.queries[q.key], gc.Equals, q)
+	}
+}
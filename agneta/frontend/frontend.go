@@ -17,25 +17,37 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	indexEndpoint      = "/"
 	searchEndpoint     = "/search"
 	submitLinkEndpoint = "/submit/site"
+	completeEndpoint   = "/complete"
 
-	defaultResultsPerPage   = 10
-	defaultMaxSummaryLength = 256
+	defaultResultsPerPage      = 10
+	defaultMaxSummaryLength    = 256
+	defaultSuggestionThreshold = 3
+	defaultMaxCompletions      = 8
+	defaultMinQueryCount       = 5
 )
 
-// GraphAPI defines a set of API methods for adding links to the graph.
+// GraphAPI defines a set of API methods for adding links to the graph and
+// looking up their crawl status.
 type GraphAPI interface {
-	UpsertLink(link *graph.Link) error
+	UpsertLink(ctx context.Context, link *graph.Link) error
+	FindLinkByURL(ctx context.Context, url string) (*graph.Link, error)
+	UpsertHost(ctx context.Context, host *graph.Host) error
+	FindHost(ctx context.Context, name string) (*graph.Host, error)
 }
 
-// IndexAPI defines a set of API methods for searching crawled documents.
+// IndexAPI defines a set of API methods for searching crawled documents,
+// suggesting spelling corrections for search terms and completing queries.
 type IndexAPI interface {
-	Search(query index.Query) (index.Iterator, error)
+	Search(ctx context.Context, query index.Query) (index.Iterator, error)
+	Suggest(ctx context.Context, term string, n int) ([]string, error)
+	Complete(ctx context.Context, prefix string, n int) ([]string, error)
 }
 
 // Config encapsulates the settings for configuring the front-end service.
@@ -58,6 +70,24 @@ type Config struct {
 	// instead.
 	MaxSummaryLength int
 
+	// Spelling corrections are suggested for queries that match fewer than
+	// this many documents. If not specified, a default value of 3 will be
+	// used instead.
+	SuggestionThreshold int
+
+	// The maximum number of completions to offer while the user is typing a
+	// query. If not specified, a default value of 8 will be used instead.
+	MaxCompletions int
+
+	// Past queries are only offered as completions once this many distinct
+	// clients have searched for them so that rare queries, which may
+	// contain private information, are not revealed to other users.
+	// Clients are told apart by their remote address; behind a reverse
+	// proxy, all searches appear to come from a single client and past
+	// queries are never offered. If not specified, a default value of 5
+	// will be used instead.
+	MinCompletionQueryCount int
+
 	// The logger to use. If not defined an output-discarding logger will
 	// be used instead.
 	Logger *logrus.Entry
@@ -74,6 +104,15 @@ func (cfg *Config) validate() error {
 	if cfg.MaxSummaryLength <= 0 {
 		cfg.MaxSummaryLength = defaultMaxSummaryLength
 	}
+	if cfg.SuggestionThreshold <= 0 {
+		cfg.SuggestionThreshold = defaultSuggestionThreshold
+	}
+	if cfg.MaxCompletions <= 0 {
+		cfg.MaxCompletions = defaultMaxCompletions
+	}
+	if cfg.MinCompletionQueryCount <= 0 {
+		cfg.MinCompletionQueryCount = defaultMinQueryCount
+	}
 	if cfg.IndexAPI == nil {
 		err = multierror.Append(err, xerrors.Errorf("index API has not been provided"))
 	}
@@ -90,6 +129,8 @@ func (cfg *Config) validate() error {
 type Service struct {
 	cfg    Config
 	router *mux.Router
+	// The popular past queries that are offered as completions.
+	queryLog *queryLog
 	// A template executor hook
 	tplExecutor func(tpl *template.Template, w io.Writer, data map[string]interface{}) error
 }
@@ -99,8 +140,9 @@ func NewService(cfg Config) (*Service, error) {
 		return nil, xerrors.Errorf("front-end service: config validation failed: %w", err)
 	}
 	svc := &Service{
-		router: mux.NewRouter(),
-		cfg:    cfg,
+		router:   mux.NewRouter(),
+		cfg:      cfg,
+		queryLog: newQueryLog(cfg.MinCompletionQueryCount),
 		tplExecutor: func(tpl *template.Template, w io.Writer, data map[string]interface{}) error {
 			return tpl.Execute(w, data)
 		},
@@ -108,6 +150,7 @@ func NewService(cfg Config) (*Service, error) {
 	svc.router.HandleFunc(indexEndpoint, svc.renderIndexPage).Methods("GET")
 	svc.router.HandleFunc(searchEndpoint, svc.renderSearchResults).Methods("GET")
 	svc.router.HandleFunc(submitLinkEndpoint, svc.submitLink).Methods("GET", "POST")
+	svc.router.HandleFunc(completeEndpoint, svc.completeQuery).Methods("GET")
 	svc.router.NotFoundHandler = http.HandlerFunc(svc.render404Page)
 	return svc, nil
 }
@@ -144,6 +187,7 @@ func (svc Service) renderIndexPage(writer http.ResponseWriter, request *http.Req
 	_ = svc.tplExecutor(indexPageTemplate, writer, map[string]interface{}{
 		"searchEndpoint":     searchEndpoint,
 		"submitLinkEndpoint": submitLinkEndpoint,
+		"completeEndpoint":   completeEndpoint,
 	})
 }
 
@@ -151,19 +195,37 @@ func (svc Service) renderSearchResults(w http.ResponseWriter, r *http.Request) {
 	searchTerms := r.URL.Query().Get("q")
 	offset, _ := strconv.ParseUint(r.URL.Query().Get("offset"), 10, 64)
 
-	matchedDocs, pagination, err := svc.runQuery(searchTerms, offset)
+	refine := refinementsFromQuery(r.URL.Query())
+
+	matchedDocs, pagination, facets, err := svc.runQuery(r.Context(), searchTerms, refine, offset)
 	if err != nil {
 		svc.cfg.Logger.WithField("err", err).Errorf("search query execution failed")
 		svc.renderSearchErrorPage(w, searchTerms)
 		return
 	}
 
+	// Only unrefined queries that returned results are offered as
+	// completions to other users.
+	if offset == 0 && refine == (refinements{}) && pagination.Total > 0 {
+		svc.queryLog.record(searchTerms, clientAddr(r))
+	}
+
+	// Offer a spelling correction for queries with few or no results.
+	var suggestion *suggestionDetails
+	if offset == 0 && pagination.Total < svc.cfg.SuggestionThreshold {
+		if suggestion, err = svc.suggestQuery(r.Context(), searchTerms, refine); err != nil {
+			svc.cfg.Logger.WithField("err", err).Warn("could not look up spelling suggestions")
+		}
+	}
+
 	// Render results page
 	if err := svc.tplExecutor(resultsPageTemplate, w, map[string]interface{}{
 		"indexEndpoint":  indexEndpoint,
 		"searchEndpoint": searchEndpoint,
 		"searchTerms":    searchTerms,
 		"pagination":     pagination,
+		"facets":         facets,
+		"suggestion":     suggestion,
 		"results":        matchedDocs,
 	}); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -193,18 +255,79 @@ func (svc Service) submitLink(writer http.ResponseWriter, request *http.Request)
 			return
 		}
 		link.Fragment = ""
-		if err = svc.cfg.GraphAPI.UpsertLink(&graph.Link{URL: link.String()}); err != nil {
+
+		// Report the crawl status for sites that are already known.
+		existing, err := svc.cfg.GraphAPI.FindLinkByURL(request.Context(), link.String())
+		if err == nil {
+			msg = crawlStatusMessage(existing)
+			return
+		} else if !xerrors.Is(err, graph.ErrNotFound) {
+			svc.cfg.Logger.WithField("err", err).Errorf("could not look up link in link graph")
+			writer.WriteHeader(http.StatusInternalServerError)
+			msg = "An error occurred while adding web site to our index; please try again later."
+			return
+		}
+
+		// Refuse submissions for blocked hosts.
+		hostName := strings.ToLower(link.Host)
+		host, err := svc.cfg.GraphAPI.FindHost(request.Context(), hostName)
+		if err != nil && !xerrors.Is(err, graph.ErrNotFound) {
+			svc.cfg.Logger.WithField("err", err).Errorf("could not look up host in link graph")
+			writer.WriteHeader(http.StatusInternalServerError)
+			msg = "An error occurred while adding web site to our index; please try again later."
+			return
+		} else if host != nil && host.Blocked {
+			writer.WriteHeader(http.StatusForbidden)
+			msg = "Web sites hosted on " + hostName + " cannot be submitted."
+			return
+		}
+
+		// Setting NextCrawlAt marks the link as submitted so that the crawler
+		// picks it up right away instead of waiting for its next pass.
+		if err = svc.cfg.GraphAPI.UpsertLink(request.Context(), &graph.Link{URL: link.String(), NextCrawlAt: time.Now()}); err != nil {
+			if xerrors.Is(err, graph.ErrLinkDeleted) {
+				writer.WriteHeader(http.StatusConflict)
+				msg = "This web site has been removed from our index and cannot be submitted right now."
+				return
+			}
 			svc.cfg.Logger.WithField("err", err).Errorf("could not upsert link into link graph")
 			writer.WriteHeader(http.StatusInternalServerError)
 			msg = "An error occurred while adding web site to our index; please try again later."
 			return
 		}
+
+		// Register the host of the submitted link so its crawl state can be
+		// tracked.
+		if host == nil {
+			if err = svc.cfg.GraphAPI.UpsertHost(request.Context(), &graph.Host{Name: hostName}); err != nil {
+				svc.cfg.Logger.WithField("err", err).Warn("could not register host in link graph")
+			}
+		}
 		msg = "Web sie was successfully submitted"
 	} else {
 		writer.WriteHeader(http.StatusBadRequest)
 	}
 }
 
+// crawlStatusMessage returns a message describing the crawl status of a link
+// that already exists in the link graph.
+func crawlStatusMessage(link *graph.Link) string {
+	switch {
+	case link.RetrievedAt.IsZero() && link.FailureCount == 0:
+		return "Web site has already been submitted and is waiting to be crawled."
+	case link.FailureCount != 0:
+		return fmt.Sprintf(
+			"Web site could not be crawled after %d attempt(s); the next attempt is scheduled for %s.",
+			link.FailureCount, link.NextCrawlAt.Format("2006-01-02 15:04 MST"),
+		)
+	default:
+		return fmt.Sprintf(
+			"Web site was last crawled on %s (HTTP status %d).",
+			link.RetrievedAt.Format("2006-01-02 15:04 MST"), link.StatusCode,
+		)
+	}
+}
+
 func (svc Service) render404Page(writer http.ResponseWriter, request *http.Request) {
 	_ = svc.tplExecutor(msgPageTemplate, writer, map[string]interface{}{
 		"indexEndpoint":  indexEndpoint,
@@ -225,21 +348,31 @@ func (svc *Service) renderSearchErrorPage(w http.ResponseWriter, searchTerms str
 	})
 }
 
-func (svc *Service) runQuery(searchTerms string, offset uint64) ([]matchedDoc, *paginationDetails, error) {
-	var query = index.Query{Type: index.QueryTypeMatch, Expression: searchTerms, Offset: '"'}
-	if strings.HasPrefix(searchTerms, `"`) && strings.HasPrefix(searchTerms, `"`) {
-		query.Type = index.QueryTypePhrase
-		searchTerms = strings.Trim(searchTerms, `"`)
+func (svc *Service) runQuery(ctx context.Context, searchTerms string, refine refinements, offset uint64) ([]matchedDoc, *paginationDetails, *facetDetails, error) {
+	query := index.Query{
+		Offset: offset,
+		Filter: refine.filter(time.Now()),
+		Facets: true,
 	}
-	resultIt, err := svc.cfg.IndexAPI.Search(query)
+	highlightTerms := searchTerms
+	if root, err := index.ParseQuery(searchTerms); err == nil {
+		query.Root = root
+		highlightTerms = strings.Join(index.QueryTerms(root), " ")
+	} else {
+		// Fall back to a plain keyword search for queries that cannot be
+		// parsed (e.g. because of an unbalanced parenthesis).
+		query.Type = index.QueryTypeMatch
+		query.Expression = searchTerms
+	}
+	resultIt, err := svc.cfg.IndexAPI.Search(ctx, query)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	defer func() { _ = resultIt.Close() }()
 	// wrap each result in a matchedDoc shim and generate a short summary which
 	// highlights the matching search terms.
-	summarizer := newMatchSummarizer(searchTerms, svc.cfg.MaxSummaryLength)
-	highlighter := newMatchHighlighter(searchTerms)
+	summarizer := newMatchSummarizer(highlightTerms, svc.cfg.MaxSummaryLength)
+	highlighter := newMatchHighlighter(highlightTerms)
 	matchedDocs := make([]matchedDoc, 0, svc.cfg.ResultsPerPage)
 	for resCount := 0; resultIt.Next() && resCount < svc.cfg.ResultsPerPage; resCount++ {
 		doc := resultIt.Document()
@@ -253,7 +386,7 @@ func (svc *Service) runQuery(searchTerms string, offset uint64) ([]matchedDoc, *
 		})
 	}
 	if err = resultIt.Error(); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	// Setup paginator and generate prev/next links.
 	pagination := &paginationDetails{
@@ -263,16 +396,45 @@ func (svc *Service) runQuery(searchTerms string, offset uint64) ([]matchedDoc, *
 	}
 
 	if offset > 0 {
-		pagination.PrevLink = fmt.Sprintf("%s?q=%s", searchEndpoint, searchTerms)
-		if prevOffset := int(offset) - svc.cfg.ResultsPerPage; prevOffset > 0 {
-			pagination.PrevLink += fmt.Sprintf("&offset=%d", prevOffset)
-		}
+		pagination.PrevLink = refine.searchLink(searchTerms, int(offset)-svc.cfg.ResultsPerPage)
 	}
 	if nextPageOffset := int(offset) + len(matchedDocs); nextPageOffset < pagination.Total {
-		pagination.NextLink = fmt.Sprintf("%s?q=%s&offset=%d", searchEndpoint, searchTerms, nextPageOffset)
+		pagination.NextLink = refine.searchLink(searchTerms, nextPageOffset)
 	}
 
-	return matchedDocs, pagination, nil
+	return matchedDocs, pagination, newFacetDetails(resultIt.Facets(), searchTerms, refine), nil
+}
+
+// suggestQuery returns a link to a version of searchTerms in which each
+// misspelled term has been replaced by the best suggestion returned by the
+// index API. It returns nil if none of the terms can be corrected.
+func (svc *Service) suggestQuery(ctx context.Context, searchTerms string, refine refinements) (*suggestionDetails, error) {
+	var err error
+	suggested := index.ReplaceQueryTerms(searchTerms, func(term string) string {
+		if err != nil {
+			return term
+		}
+		var suggestions []string
+		if suggestions, err = svc.cfg.IndexAPI.Suggest(ctx, term, 1); err != nil || len(suggestions) == 0 {
+			return term
+		}
+		return suggestions[0]
+	})
+	if err != nil {
+		return nil, err
+	} else if suggested == searchTerms {
+		return nil, nil
+	}
+	return &suggestionDetails{
+		SearchTerms: suggested,
+		Link:        refine.searchLink(suggested, 0),
+	}, nil
+}
+
+// suggestionDetails describes a spelling-corrected version of a search query.
+type suggestionDetails struct {
+	SearchTerms string
+	Link        string
 }
 
 // matchedDoc wraps an index.Document and provides convenience methods for
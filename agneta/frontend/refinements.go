@@ -0,0 +1,132 @@
+package frontend
+
+import (
+	"Search_Engine/textindexer/index"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// refinements encapsulates the search result refinements that are selected via
+// the query string of the search endpoint.
+type refinements struct {
+	// The site to restrict results to.
+	site string
+
+	// The name of the index.DateFacetRanges entry to restrict results to.
+	indexed string
+
+	// The minimum PageRank score of the results.
+	minPageRank float64
+}
+
+// refinementsFromQuery extracts the search result refinements from the query
+// string of a search request. Invalid values are ignored.
+func refinementsFromQuery(v url.Values) refinements {
+	r := refinements{
+		site:    v.Get("site"),
+		indexed: v.Get("indexed"),
+	}
+	if minPageRank, err := strconv.ParseFloat(v.Get("min_rank"), 64); err == nil {
+		r.minPageRank = minPageRank
+	}
+	return r
+}
+
+// filter returns the index filter for the selected refinements.
+func (r refinements) filter(now time.Time) index.Filter {
+	f := index.Filter{MinPageRank: r.minPageRank}
+	if r.site != "" {
+		f.Sites = []string{r.site}
+	}
+	for _, dateRange := range index.DateFacetRanges(now) {
+		if dateRange.Name == r.indexed {
+			f.IndexedFrom, f.IndexedTo = dateRange.From, dateRange.To
+		}
+	}
+	return f
+}
+
+// searchLink returns a link to the search endpoint for the specified search
+// terms, refinements and result offset.
+func (r refinements) searchLink(searchTerms string, offset int) string {
+	v := url.Values{"q": {searchTerms}}
+	if r.site != "" {
+		v.Set("site", r.site)
+	}
+	if r.indexed != "" {
+		v.Set("indexed", r.indexed)
+	}
+	if r.minPageRank != 0 {
+		v.Set("min_rank", strconv.FormatFloat(r.minPageRank, 'g', -1, 64))
+	}
+	if offset > 0 {
+		v.Set("offset", strconv.Itoa(offset))
+	}
+	return fmt.Sprintf("%s?%s", searchEndpoint, v.Encode())
+}
+
+// facetLink is a link for refining the search results by a facet value.
+type facetLink struct {
+	Label  string
+	Count  uint64
+	Link   string
+	Active bool
+}
+
+// facetDetails contains the refinement links for the facets of a search.
+type facetDetails struct {
+	Hosts     []facetLink
+	IndexedAt []facetLink
+
+	// Links for removing the site and indexing date refinements when they
+	// are active.
+	AnySiteLink string
+	AnyTimeLink string
+}
+
+// newFacetDetails generates the refinement links for the facet counts
+// returned by a search.
+func newFacetDetails(facets *index.Facets, searchTerms string, r refinements) *facetDetails {
+	if facets == nil {
+		return nil
+	}
+
+	details := new(facetDetails)
+	for _, h := range facets.Hosts {
+		refined := r
+		refined.site = h.Host
+		details.Hosts = append(details.Hosts, facetLink{
+			Label:  h.Host,
+			Count:  h.Count,
+			Link:   refined.searchLink(searchTerms, 0),
+			Active: h.Host == r.site,
+		})
+	}
+	for _, d := range facets.IndexedAt {
+		if d.Count == 0 {
+			continue
+		}
+		refined := r
+		refined.indexed = d.Name
+		details.IndexedAt = append(details.IndexedAt, facetLink{
+			Label:  "Past " + d.Name,
+			Count:  d.Count,
+			Link:   refined.searchLink(searchTerms, 0),
+			Active: d.Name == r.indexed,
+		})
+	}
+
+	if r.site != "" {
+		refined := r
+		refined.site = ""
+		details.AnySiteLink = refined.searchLink(searchTerms, 0)
+	}
+	if r.indexed != "" {
+		refined := r
+		refined.indexed = ""
+		details.AnyTimeLink = refined.searchLink(searchTerms, 0)
+	}
+	return details
+}
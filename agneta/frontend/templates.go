@@ -32,13 +32,47 @@ var (
     </header>
     <section class="tc">
       <form action="{{.searchEndpoint}}">
-      <input class="t" type="text" name="q" placeholder="Enter search term"/>
+      <input class="t" type="text" name="q" placeholder="Enter search term" list="completions" autocomplete="off"/>
+      <datalist id="completions"></datalist>
       <br>
       <input class="sb" type="submit" value="Search"/>
       </form>
 			<br/><br/>
       <a rel="nofollow" href="{{.submitLinkEndpoint}}">Submit Web Site</a>
     </section>
+    <script>
+      (function() {
+        var input = document.querySelector("input[name=q]"),
+            list = document.getElementById("completions"),
+            endpoint = {{.completeEndpoint}},
+            timer = null;
+        input.addEventListener("input", function() {
+          clearTimeout(timer);
+          timer = setTimeout(function() {
+            var prefix = input.value;
+            if (prefix.trim() === "") {
+              list.innerHTML = "";
+              return;
+            }
+            fetch(endpoint + "?q=" + encodeURIComponent(prefix))
+              .then(function(res) { return res.json(); })
+              .then(function(res) {
+                // Ignore stale responses.
+                if (input.value !== prefix) {
+                  return;
+                }
+                list.innerHTML = "";
+                res.completions.forEach(function(completion) {
+                  var opt = document.createElement("option");
+                  opt.value = completion;
+                  list.appendChild(opt);
+                });
+              })
+              .catch(function() {});
+          }, 150);
+        });
+      })();
+    </script>
   </body>
 </html>
 `))
@@ -120,6 +154,9 @@ var (
 			.nb{padding:15px 20px;border-top:1px solid gray;}
 			.nb a{padding-right:15px;text-decoration:none;color:blue;}
 			.nb a:visited{color:blue;}
+			.fc a{padding-left:10px;text-decoration:none;color:blue;font-size:0.9em;}
+			.fc a.fa{font-weight:bold;}
+			.rc a.sg{color:blue;font-style:italic;font-weight:bold;}
       input:focus{outline: none;}
     </style>
   </head>
@@ -141,10 +178,27 @@ var (
       </section>
     </header>
     <hr/>
+		{{with .suggestion}}
+    <section class="rc">
+      <span class="rt">Did you mean</span> <a class="sg" rel="nofollow" href="{{.Link}}">{{.SearchTerms}}</a><span class="rt">?</span>
+    </section>
+		{{end}}
 		{{if .results}}
     <section class="rc">
       <span class="rt">Displaying results {{.pagination.From}} to {{.pagination.To}} from {{.pagination.Total}}.</span>
     </section>
+		{{with .facets}}
+    <section class="rc fc">
+      <span class="rt">Site:</span>
+		  {{range .Hosts}}<a rel="nofollow" {{if .Active}}class="fa" {{end}}href="{{.Link}}">{{.Label}} ({{.Count}})</a>{{end}}
+		  {{if .AnySiteLink}}<a rel="nofollow" href="{{.AnySiteLink}}">Any site</a>{{end}}
+    </section>
+    <section class="rc fc">
+      <span class="rt">Indexed:</span>
+		  {{range .IndexedAt}}<a rel="nofollow" {{if .Active}}class="fa" {{end}}href="{{.Link}}">{{.Label}} ({{.Count}})</a>{{end}}
+		  {{if .AnyTimeLink}}<a rel="nofollow" href="{{.AnyTimeLink}}">Any time</a>{{end}}
+    </section>
+		{{end}}
 		{{range .results}}
     <section class="rc">
       <a class="ml" rel="nofollow" href="{{.URL}}">{{.Title}}</a>
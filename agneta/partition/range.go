@@ -5,6 +5,7 @@ import (
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"math/big"
+	"sort"
 )
 
 // Range represents a contiguous UUID region which is split into a number
@@ -43,8 +44,9 @@ func NewRange(start, end uuid.UUID, numPartitions int) (Range, error) {
 		if partition == numPartitions-1 {
 			to = end
 		} else {
-			tokenRange.Mul(partSize, big.NewInt(int64(partition-1)))
-			if to, err = uuid.FromBytes(tokenRange.Bytes()); err != nil {
+			tokenRange.Mul(partSize, big.NewInt(int64(partition+1)))
+			tokenRange.Add(tokenRange, big.NewInt(0).SetBytes(start[:]))
+			if to, err = uuid.FromBytes(tokenRange.FillBytes(make([]byte, len(to)))); err != nil {
 				return Range{}, xerrors.Errorf("partition range: %w", err)
 			}
 		}
@@ -63,3 +65,14 @@ func (r Range) PartitionExtents(partition int) (uuid.UUID, uuid.UUID, error) {
 	}
 	return r.rangeSplits[partition-1], r.rangeSplits[partition], nil
 }
+
+// PartitionForID returns the index of the partition that contains id.
+func (r Range) PartitionForID(id uuid.UUID) (int, error) {
+	partition := sort.Search(len(r.rangeSplits), func(i int) bool {
+		return bytes.Compare(id[:], r.rangeSplits[i][:]) < 0
+	})
+	if bytes.Compare(id[:], r.start[:]) < 0 || partition == len(r.rangeSplits) {
+		return -1, xerrors.Errorf("unable to detect partition for ID %q", id)
+	}
+	return partition, nil
+}
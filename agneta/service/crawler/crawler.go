@@ -6,6 +6,7 @@ import (
 	"Search_Engine/crawler/privnet"
 	"Search_Engine/linkgraph/graph"
 	"Search_Engine/textindexer/index"
+	"bytes"
 	"context"
 	"errors"
 	"github.com/google/uuid"
@@ -20,23 +21,44 @@ import (
 
 // GraphAPI defines a set of aPI methods for accessing the link graph.
 type GraphAPI interface {
-	UpsertLink(link *graph.Link) error
-	UpsertEdge(edge *graph.Edge) error
-	RemoveStaleEdges(from uuid.UUID, updatedBefore time.Time) error
-	Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
+	UpsertLink(ctx context.Context, link *graph.Link) error
+	UpsertLinks(ctx context.Context, links []*graph.Link) error
+	UpsertEdges(ctx context.Context, edges []*graph.Edge) error
+	RemoveStaleEdges(ctx context.Context, from uuid.UUID, updatedBefore time.Time) error
+	Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
+	WatchLinks(ctx context.Context) (graph.LinkWatcher, error)
+	UpsertHost(ctx context.Context, host *graph.Host) error
+	FindHost(ctx context.Context, name string) (*graph.Host, error)
+	RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error
 }
 
-// IndexAPI defines a set of API methods for indexing crawled documents.
+// IndexAPI defines a set of API methods for indexing crawled documents and
+// removing the documents of pages that no longer exist.
 type IndexAPI interface {
-	Index(doc *index.Document) error
+	IndexBatch(ctx context.Context, docs []*index.Document) error
+	Delete(ctx context.Context, linkID uuid.UUID) error
 }
 
+// PageRankAPI defines a set of API methods for looking up the PageRank score
+// of indexed documents.
+type PageRankAPI interface {
+	FindByIDs(ctx context.Context, linkIDs []uuid.UUID) ([]*index.Document, error)
+}
+
+// pageRankBatchSize is the number of eligible links whose PageRank scores
+// are looked up with a single PageRankAPI request.
+const pageRankBatchSize = 500
+
 // Config encapsulates the settings for configuring the web-crawler service.
 type Config struct {
 	// An API for managing and interacting with links and edges in the link graph.
 	GraphAPI GraphAPI
 	// An API indexing documents
 	IndexAPI IndexAPI
+	// An API for looking up the PageRank scores of crawled links. If not
+	// specified, PageRank scores are not taken into account when
+	// prioritizing links.
+	PageRankAPI PageRankAPI
 	// An API for detecting private network addresses.
 	PrivateNetworkDetector crawlerpipeline.PrivateNetworkDetector
 	// An API for performing http requests. If not specified, the default
@@ -48,10 +70,22 @@ type Config struct {
 	Clock clock.Clock
 	// The number of concurrent workers used for retrieving links.
 	FetchWorkers int
+	// The maximum number of crawled documents to send to the index API as
+	// a single batch.
+	IndexBatchSize int
+	// The maximum amount of time to keep accumulating crawled documents
+	// before sending a partial batch to the index API.
+	IndexFlushInterval time.Duration
 	// The time between subsequent crawler passes
 	UpdateInterval time.Duration
 	// The minimum amount of time before re-indexing an already-crawled link.
 	ReIndexThreshold time.Duration
+	// The settings for prioritizing the links crawled by each pass.
+	Frontier FrontierConfig
+	// URLDerivedLinkIDs causes the IDs of discovered links to be derived
+	// from their URLs by the crawler. It should be enabled when the link
+	// graph is configured to use URL-derived IDs.
+	URLDerivedLinkIDs bool
 	// The logger to use
 	Logger *logrus.Entry
 }
@@ -79,12 +113,21 @@ func (cfg *Config) Validate() error {
 	if cfg.FetchWorkers <= 0 {
 		err = multierror.Append(err, xerrors.Errorf("invalid value for fetch workers"))
 	}
+	if cfg.IndexBatchSize <= 0 {
+		err = multierror.Append(err, xerrors.Errorf("invalid value for index batch size"))
+	}
+	if cfg.IndexFlushInterval < 0 {
+		err = multierror.Append(err, xerrors.Errorf("invalid value for index flush interval"))
+	}
 	if cfg.UpdateInterval == 0 {
 		err = multierror.Append(err, xerrors.Errorf("invalid value for update interval"))
 	}
 	if cfg.ReIndexThreshold == 0 {
 		err = multierror.Append(err, xerrors.Errorf("invalid value for re-index threshold"))
 	}
+	if cfg.Frontier.MaxLinksPerPass < 0 {
+		err = multierror.Append(err, xerrors.Errorf("invalid value for max links per pass"))
+	}
 	if cfg.Logger == nil {
 		cfg.Logger = logrus.NewEntry(&logrus.Logger{Out: ioutil.Discard})
 	}
@@ -108,8 +151,13 @@ func NewService(cfg Config) (*Service, error) {
 			PrivateNetworkDetector: cfg.PrivateNetworkDetector,
 			URLGetter:              cfg.UrlGetter,
 			Graph:                  cfg.GraphAPI,
+			Hosts:                  cfg.GraphAPI,
 			Indexer:                cfg.IndexAPI,
 			FetchWorkers:           cfg.FetchWorkers,
+			IndexBatchSize:         cfg.IndexBatchSize,
+			IndexFlushInterval:     cfg.IndexFlushInterval,
+			URLDerivedLinkIDs:      cfg.URLDerivedLinkIDs,
+			Logger:                 cfg.Logger,
 		}),
 	}, nil
 }
@@ -122,6 +170,10 @@ func (svc *Service) Run(ctx context.Context) error {
 	svc.cfg.Logger.WithField("update_interval", svc.cfg.UpdateInterval.String()).Info("starting service")
 	defer svc.cfg.Logger.Info("stopped service")
 
+	watchCtx, cancelWatch := context.WithCancel(ctx)
+	defer cancelWatch()
+	go svc.watchNewLinks(watchCtx)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -158,20 +210,235 @@ func (svc *Service) crawlGraph(ctx context.Context, curPartition int, numPartiti
 	}).Info("starting new crawl pass")
 
 	startAt := svc.cfg.Clock.Now()
-	linkIt, err := svc.cfg.GraphAPI.Links(fromID, toID, svc.cfg.Clock.Now().Add(-svc.cfg.ReIndexThreshold))
+	links, candidates, err := svc.selectLinks(ctx, fromID, toID, startAt)
 	if err != nil {
-		return xerrors.Errorf("crawler: unable to retrieve links iterator: %w", err)
+		return err
 	}
-	processed, err := svc.crawler.Crawl(ctx, linkIt)
+	processed, err := svc.crawler.Crawl(ctx, &linkSliceIterator{links: links})
 	if err != nil {
 		return xerrors.Errorf("crawler: unable o complete crawling the link graph: %w", err)
-	} else if err = linkIt.Close(); err != nil {
-		return xerrors.Errorf("crawler: unable to complete crawling the link graph: %w", err)
 	}
 
 	svc.cfg.Logger.WithFields(logrus.Fields{
+		"candidate_link_count": candidates,
 		"processed_link_count": processed,
 		"elapsed_time":         svc.cfg.Clock.Now().Sub(startAt).String(),
 	}).Info("completed crawl pass")
 	return nil
 }
+
+// selectLinks scans the links in the [fromID, toID) range and returns the
+// highest-priority links that are due for crawling, ordered by descending
+// priority, together with the number of links that were eligible.
+func (svc *Service) selectLinks(ctx context.Context, fromID, toID uuid.UUID, now time.Time) ([]*graph.Link, int, error) {
+	linkIt, err := svc.cfg.GraphAPI.Links(ctx, fromID, toID, now.Add(-svc.cfg.ReIndexThreshold))
+	if err != nil {
+		return nil, 0, xerrors.Errorf("crawler: unable to retrieve links iterator: %w", err)
+	}
+
+	f := newFrontier(svc.cfg.Frontier, svc.cfg.ReIndexThreshold, now)
+	var (
+		candidates int
+		pending    = make([]*graph.Link, 0, pageRankBatchSize)
+	)
+	for linkIt.Next() {
+		link := linkIt.Link()
+		if !f.eligible(link) {
+			continue
+		}
+		candidates++
+
+		if pending = append(pending, link); len(pending) < pageRankBatchSize {
+			continue
+		}
+		if err = svc.addToFrontier(ctx, f, pending); err != nil {
+			_ = linkIt.Close()
+			return nil, 0, err
+		}
+		pending = pending[:0]
+	}
+	if err = linkIt.Error(); err != nil {
+		_ = linkIt.Close()
+		return nil, 0, xerrors.Errorf("crawler: unable to iterate links: %w", err)
+	} else if err = linkIt.Close(); err != nil {
+		return nil, 0, xerrors.Errorf("crawler: unable to iterate links: %w", err)
+	}
+	if err = svc.addToFrontier(ctx, f, pending); err != nil {
+		return nil, 0, err
+	}
+	return f.Links(), candidates, nil
+}
+
+// addToFrontier looks up the PageRank scores of links with a single request
+// and adds the links to f.
+func (svc *Service) addToFrontier(ctx context.Context, f *frontier, links []*graph.Link) error {
+	pageRanks, err := svc.pageRanks(ctx, links)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		f.Add(link, pageRanks[link.ID])
+	}
+	return nil
+}
+
+// pageRanks returns the PageRank scores of links keyed by link ID. Links
+// whose score is not available are omitted.
+func (svc *Service) pageRanks(ctx context.Context, links []*graph.Link) (map[uuid.UUID]float64, error) {
+	if svc.cfg.PageRankAPI == nil {
+		return nil, nil
+	}
+
+	// Links that have never been retrieved have not been indexed yet.
+	linkIDs := make([]uuid.UUID, 0, len(links))
+	for _, link := range links {
+		if !link.RetrievedAt.IsZero() {
+			linkIDs = append(linkIDs, link.ID)
+		}
+	}
+	if len(linkIDs) == 0 {
+		return nil, nil
+	}
+
+	docs, err := svc.cfg.PageRankAPI.FindByIDs(ctx, linkIDs)
+	if err != nil {
+		return nil, xerrors.Errorf("crawler: unable to look up PageRank scores: %w", err)
+	}
+	pageRanks := make(map[uuid.UUID]float64, len(docs))
+	for _, doc := range docs {
+		pageRanks[doc.LinkID] = doc.PageRank
+	}
+	return pageRanks, nil
+}
+
+// watchNewLinks subscribes to the link graph change feed and crawls newly
+// created links without waiting for the next crawl pass. If the feed breaks,
+// it re-subscribes after UpdateInterval.
+func (svc *Service) watchNewLinks(ctx context.Context) {
+	for {
+		if err := svc.crawlNewLinks(ctx); err != nil && ctx.Err() == nil {
+			svc.cfg.Logger.WithField("err", err).Warn("link watch interrupted")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-svc.cfg.Clock.After(svc.cfg.UpdateInterval):
+		}
+	}
+}
+
+// crawlNewLinks sends each batch of newly submitted links reported by the
+// link graph that belong to this service's partition through the crawler.
+// Links discovered while crawling are left to the regular crawl passes so
+// that they are subject to the frontier limits.
+// While a batch is being crawled, new events accumulate in the watcher's
+// buffer; events that do not fit are dropped and the corresponding links are
+// picked up by the next crawl pass.
+func (svc *Service) crawlNewLinks(ctx context.Context) error {
+	watcher, err := svc.cfg.GraphAPI.WatchLinks(ctx)
+	if err != nil {
+		return xerrors.Errorf("crawler: unable to watch link graph: %w", err)
+	}
+	defer func() { _ = watcher.Close() }()
+
+	for {
+		var batch []*graph.Link
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-watcher.Events():
+			if !ok {
+				return watcher.Error()
+			}
+			batch = appendNewLink(batch, ev)
+		}
+
+		// Include any other events that are already buffered.
+	drain:
+		for {
+			select {
+			case ev, ok := <-watcher.Events():
+				if !ok {
+					break drain
+				}
+				batch = appendNewLink(batch, ev)
+			default:
+				break drain
+			}
+		}
+
+		if batch, err = svc.filterPartitionLinks(batch); err != nil {
+			return err
+		} else if len(batch) == 0 {
+			continue
+		}
+
+		processed, err := svc.crawler.Crawl(ctx, &linkSliceIterator{links: batch})
+		if err != nil {
+			return xerrors.Errorf("crawler: unable to crawl new links: %w", err)
+		}
+		svc.cfg.Logger.WithField("processed_link_count", processed).Info("crawled new links")
+	}
+}
+
+// appendNewLink appends the link of ev to batch if ev reports a link that has
+// been submitted for crawling but not retrieved yet. Submitted links are
+// created with a NextCrawlAt value while discovered links leave it unset.
+func appendNewLink(batch []*graph.Link, ev graph.LinkEvent) []*graph.Link {
+	if ev.Type != graph.LinkCreated || !ev.Link.RetrievedAt.IsZero() || ev.Link.NextCrawlAt.IsZero() {
+		return batch
+	}
+	return append(batch, ev.Link)
+}
+
+// filterPartitionLinks removes the links that do not belong to the partition
+// assigned to this service.
+func (svc *Service) filterPartitionLinks(links []*graph.Link) ([]*graph.Link, error) {
+	if len(links) == 0 {
+		return links, nil
+	}
+
+	curPartition, numPartitions, err := svc.cfg.PartitionDetector.PartitionInfo()
+	if err != nil {
+		if errors.Is(err, partition.ErrPartitionDataAvailableYet) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	partRange, err := partition.NewFullRange(numPartitions)
+	if err != nil {
+		return nil, xerrors.Errorf("crawler: unable to compute ID ranges for partition: %w", err)
+	}
+	fromID, toID, err := partRange.PartitionExtents(curPartition)
+	if err != nil {
+		return nil, xerrors.Errorf("crawler: unable to compute ID ranges for partition: %w", err)
+	}
+
+	filtered := links[:0]
+	for _, link := range links {
+		if bytes.Compare(link.ID[:], fromID[:]) >= 0 && bytes.Compare(link.ID[:], toID[:]) < 0 {
+			filtered = append(filtered, link)
+		}
+	}
+	return filtered, nil
+}
+
+// linkSliceIterator is a graph.LinkIterator implementation for a slice of
+// links.
+type linkSliceIterator struct {
+	links    []*graph.Link
+	curIndex int
+}
+
+func (it *linkSliceIterator) Next() bool {
+	if it.curIndex >= len(it.links) {
+		return false
+	}
+	it.curIndex++
+	return true
+}
+
+func (it *linkSliceIterator) Link() *graph.Link { return it.links[it.curIndex-1] }
+func (it *linkSliceIterator) Error() error      { return nil }
+func (it *linkSliceIterator) Close() error      { return nil }
@@ -0,0 +1,140 @@
+package crawler
+
+import (
+	"Search_Engine/agneta/partition"
+	crawlerpipeline "Search_Engine/crawler"
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/store/memory"
+	"Search_Engine/textindexer/index"
+	"Search_Engine/textindexer/store/memindex"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	gc "gopkg.in/check.v1"
+	"net/http"
+	"time"
+)
+
+var _ = gc.Suite(new(CrawlerTestSuite))
+
+type CrawlerTestSuite struct{}
+
+func (s *CrawlerTestSuite) TestFailedLinkRetriedAfterBackoff(c *gc.C) {
+	g := memory.NewInMemoryGraph()
+	link := &graph.Link{URL: "http://example.com"}
+	c.Assert(g.UpsertLink(context.TODO(), link), gc.IsNil)
+
+	svc := s.newService(c, g, failingGetter{})
+	c.Assert(svc.crawlGraph(context.TODO(), 0, 1), gc.IsNil)
+
+	failed, err := g.FindLink(context.TODO(), link.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(failed.FailureCount, gc.Equals, 1)
+	c.Assert(failed.RetrievedAt.IsZero(), gc.Equals, true, gc.Commentf("failed fetches should not update the retrieval time"))
+
+	now := time.Now()
+	fromID, toID := s.fullRange(c)
+	links, _, err := svc.selectLinks(context.TODO(), fromID, toID, now.Add(30*time.Minute))
+	c.Assert(err, gc.IsNil)
+	c.Assert(links, gc.HasLen, 0, gc.Commentf("link should not be retried before its backoff expires"))
+
+	links, _, err = svc.selectLinks(context.TODO(), fromID, toID, now.Add(2*time.Hour))
+	c.Assert(err, gc.IsNil)
+	c.Assert(links, gc.HasLen, 1, gc.Commentf("link should be retried after its backoff expires"))
+	c.Assert(links[0].ID, gc.Equals, link.ID)
+}
+
+func (s *CrawlerTestSuite) TestAppendNewLink(c *gc.C) {
+	now := time.Now()
+	submitted := &graph.Link{URL: "http://example.com/submitted", NextCrawlAt: now}
+	discovered := &graph.Link{URL: "http://example.com/discovered"}
+	retrieved := &graph.Link{URL: "http://example.com/retrieved", RetrievedAt: now, NextCrawlAt: now}
+
+	var batch []*graph.Link
+	for _, ev := range []graph.LinkEvent{
+		{Type: graph.LinkCreated, Link: submitted},
+		{Type: graph.LinkCreated, Link: discovered},
+		{Type: graph.LinkCreated, Link: retrieved},
+		{Type: graph.LinkUpdated, Link: submitted},
+	} {
+		batch = appendNewLink(batch, ev)
+	}
+	c.Assert(batch, gc.DeepEquals, []*graph.Link{submitted}, gc.Commentf("only newly submitted links should be crawled right away"))
+}
+
+func (s *CrawlerTestSuite) TestSelectLinksLooksUpPageRanksInBatches(c *gc.C) {
+	g := memory.NewInMemoryGraph()
+	retrievedAt := time.Now().Add(-30 * 24 * time.Hour)
+	pageRanks := stubPageRanks{calls: new(int), scores: make(map[uuid.UUID]float64)}
+	var popular *graph.Link
+	for i := 0; i < 3; i++ {
+		link := &graph.Link{URL: fmt.Sprintf("http://example.com/%d", i), RetrievedAt: retrievedAt}
+		c.Assert(g.UpsertLink(context.TODO(), link), gc.IsNil)
+		popular = link
+	}
+	pageRanks.scores[popular.ID] = 0.5
+
+	svc := s.newService(c, g, failingGetter{})
+	svc.cfg.PageRankAPI = pageRanks
+	fromID, toID := s.fullRange(c)
+	links, candidates, err := svc.selectLinks(context.TODO(), fromID, toID, time.Now())
+	c.Assert(err, gc.IsNil)
+	c.Assert(candidates, gc.Equals, 3)
+	c.Assert(links, gc.HasLen, 3)
+	c.Assert(links[0].ID, gc.Equals, popular.ID)
+	c.Assert(*pageRanks.calls, gc.Equals, 1)
+}
+
+func (s *CrawlerTestSuite) newService(c *gc.C, g GraphAPI, getter crawlerpipeline.URLGetter) *Service {
+	idx, err := memindex.NewInMemoryBleveIndexer()
+	c.Assert(err, gc.IsNil)
+
+	svc, err := NewService(Config{
+		GraphAPI:               g,
+		IndexAPI:               idx,
+		PrivateNetworkDetector: publicNetDetector{},
+		UrlGetter:              getter,
+		PartitionDetector:      partition.Fixed{Partition: 0, NumPartitions: 1},
+		FetchWorkers:           1,
+		IndexBatchSize:         1,
+		UpdateInterval:         time.Minute,
+		ReIndexThreshold:       7 * 24 * time.Hour,
+	})
+	c.Assert(err, gc.IsNil)
+	return svc
+}
+
+func (s *CrawlerTestSuite) fullRange(c *gc.C) (fromID, toID uuid.UUID) {
+	partRange, err := partition.NewFullRange(1)
+	c.Assert(err, gc.IsNil)
+	fromID, toID, err = partRange.PartitionExtents(0)
+	c.Assert(err, gc.IsNil)
+	return fromID, toID
+}
+
+type failingGetter struct{}
+
+func (failingGetter) Get(string) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+type stubPageRanks struct {
+	calls  *int
+	scores map[uuid.UUID]float64
+}
+
+func (p stubPageRanks) FindByIDs(_ context.Context, linkIDs []uuid.UUID) ([]*index.Document, error) {
+	*p.calls++
+	var docs []*index.Document
+	for _, linkID := range linkIDs {
+		if score, found := p.scores[linkID]; found {
+			docs = append(docs, &index.Document{LinkID: linkID, PageRank: score})
+		}
+	}
+	return docs, nil
+}
+
+type publicNetDetector struct{}
+
+func (publicNetDetector) IsPrivate(string) (bool, error) { return false, nil }
@@ -0,0 +1,136 @@
+package crawler
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"container/heap"
+	"math"
+	"sort"
+	"time"
+)
+
+// FrontierConfig encapsulates the settings for prioritizing the links that
+// are crawled during a crawl pass.
+type FrontierConfig struct {
+	// The maximum number of links to crawl in a single pass. If zero, all
+	// eligible links are crawled.
+	MaxLinksPerPass int
+
+	// The score assigned to links that have never been fetched. Links
+	// that have been fetched before score log2(1 + age/ReIndexThreshold)
+	// which evaluates to 1 for links that just became eligible for
+	// re-crawling. If not specified, a default value of 2 is used.
+	NeverFetchedScore float64
+
+	// The multiplier for the PageRank score of a link. As PageRank scores
+	// add up to 1 across the entire graph, this value should be in the
+	// order of the number of links in the graph. The score of each link
+	// is multiplied by (1 + PageRankWeight * PageRank). If not specified,
+	// a default value of 1e4 is used.
+	PageRankWeight float64
+
+	// The factor by which the score of a link is multiplied for each
+	// consecutive failed crawl attempt. If not specified, a default value of
+	// 0.5 is used.
+	FailurePenalty float64
+}
+
+func (cfg *FrontierConfig) setDefaults() {
+	if cfg.NeverFetchedScore <= 0 {
+		cfg.NeverFetchedScore = 2
+	}
+	if cfg.PageRankWeight <= 0 {
+		cfg.PageRankWeight = 1e4
+	}
+	if cfg.FailurePenalty <= 0 || cfg.FailurePenalty > 1 {
+		cfg.FailurePenalty = 0.5
+	}
+}
+
+// frontier collects the links that are eligible for crawling and keeps the
+// MaxLinksPerPass links with the highest priority score.
+type frontier struct {
+	cfg              FrontierConfig
+	reIndexThreshold time.Duration
+	now              time.Time
+	h                candidateHeap
+}
+
+func newFrontier(cfg FrontierConfig, reIndexThreshold time.Duration, now time.Time) *frontier {
+	cfg.setDefaults()
+	return &frontier{
+		cfg:              cfg,
+		reIndexThreshold: reIndexThreshold,
+		now:              now,
+	}
+}
+
+// eligible returns true if link is due for crawling.
+func (f *frontier) eligible(link *graph.Link) bool {
+	if f.now.Before(link.NextCrawlAt) {
+		return false
+	}
+	return link.RetrievedAt.IsZero() || f.now.Sub(link.RetrievedAt) >= f.reIndexThreshold
+}
+
+// score returns the priority score for an eligible link with the specified
+// PageRank score.
+func (f *frontier) score(link *graph.Link, pageRank float64) float64 {
+	var score float64
+	if link.RetrievedAt.IsZero() {
+		score = f.cfg.NeverFetchedScore
+	} else {
+		age := f.now.Sub(link.RetrievedAt)
+		score = math.Log2(1 + float64(age)/float64(f.reIndexThreshold))
+	}
+
+	score *= 1 + f.cfg.PageRankWeight*pageRank
+	return score * math.Pow(f.cfg.FailurePenalty, float64(link.FailureCount))
+}
+
+// Add adds an eligible link with the specified PageRank score to the
+// frontier. If the frontier is full, the link with the lowest score is
+// evicted.
+func (f *frontier) Add(link *graph.Link, pageRank float64) {
+	c := candidate{link: link, score: f.score(link, pageRank)}
+	if f.cfg.MaxLinksPerPass <= 0 || f.h.Len() < f.cfg.MaxLinksPerPass {
+		heap.Push(&f.h, c)
+		return
+	}
+
+	// Replace the lowest-scoring link if the new link scores higher.
+	if c.score > f.h[0].score {
+		f.h[0] = c
+		heap.Fix(&f.h, 0)
+	}
+}
+
+// Links returns the links in the frontier sorted by descending score.
+func (f *frontier) Links() []*graph.Link {
+	candidates := append(candidateHeap(nil), f.h...)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+
+	links := make([]*graph.Link, len(candidates))
+	for i, c := range candidates {
+		links[i] = c.link
+	}
+	return links
+}
+
+type candidate struct {
+	link  *graph.Link
+	score float64
+}
+
+// candidateHeap is a min-heap of candidates ordered by score.
+type candidateHeap []candidate
+
+func (h candidateHeap) Len() int            { return len(h) }
+func (h candidateHeap) Less(i, j int) bool  { return h[i].score < h[j].score }
+func (h candidateHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *candidateHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *candidateHeap) Pop() interface{} {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
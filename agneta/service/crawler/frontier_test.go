@@ -0,0 +1,61 @@
+package crawler
+
+import (
+	"Search_Engine/linkgraph/graph"
+	gc "gopkg.in/check.v1"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(FrontierTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type FrontierTestSuite struct{}
+
+func (s *FrontierTestSuite) TestEligible(c *gc.C) {
+	now := time.Now()
+	f := newFrontier(FrontierConfig{}, time.Hour, now)
+
+	c.Assert(f.eligible(&graph.Link{}), gc.Equals, true)
+	c.Assert(f.eligible(&graph.Link{RetrievedAt: now.Add(-2 * time.Hour)}), gc.Equals, true)
+	c.Assert(f.eligible(&graph.Link{RetrievedAt: now.Add(-time.Minute)}), gc.Equals, false)
+	c.Assert(f.eligible(&graph.Link{
+		RetrievedAt:  now.Add(-2 * time.Hour),
+		FailureCount: 1,
+		NextCrawlAt:  now.Add(time.Minute),
+	}), gc.Equals, false)
+}
+
+func (s *FrontierTestSuite) TestPriorityOrder(c *gc.C) {
+	now := time.Now()
+	f := newFrontier(FrontierConfig{}, time.Hour, now)
+
+	stale := &graph.Link{URL: "stale", RetrievedAt: now.Add(-time.Hour)}
+	veryStale := &graph.Link{URL: "very-stale", RetrievedAt: now.Add(-7 * time.Hour)}
+	neverFetched := &graph.Link{URL: "never-fetched"}
+	popular := &graph.Link{URL: "popular", RetrievedAt: now.Add(-time.Hour)}
+	failing := &graph.Link{URL: "failing", RetrievedAt: now.Add(-7 * time.Hour), FailureCount: 3}
+
+	f.Add(stale, 0)
+	f.Add(failing, 0)
+	f.Add(neverFetched, 0)
+	f.Add(popular, 1e-3)
+	f.Add(veryStale, 0)
+
+	c.Assert(f.Links(), gc.DeepEquals, []*graph.Link{popular, veryStale, neverFetched, stale, failing})
+}
+
+func (s *FrontierTestSuite) TestMaxLinksPerPass(c *gc.C) {
+	now := time.Now()
+	f := newFrontier(FrontierConfig{MaxLinksPerPass: 2}, time.Hour, now)
+
+	var links []*graph.Link
+	for i := 1; i <= 5; i++ {
+		link := &graph.Link{RetrievedAt: now.Add(-time.Duration(i) * time.Hour)}
+		links = append(links, link)
+		f.Add(link, 0)
+	}
+
+	c.Assert(f.Links(), gc.DeepEquals, []*graph.Link{links[4], links[3]})
+}
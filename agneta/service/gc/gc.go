@@ -0,0 +1,332 @@
+package gc
+
+import (
+	"Search_Engine/agneta/partition"
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/textindexer/index"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/juju/clock"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"io/ioutil"
+	"time"
+)
+
+// GraphAPI defines a set of API methods for selecting and deleting links
+// from the link graph.
+type GraphAPI interface {
+	Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
+	InboundDegree(ctx context.Context, dstID uuid.UUID) (int, error)
+	DeleteLink(ctx context.Context, id uuid.UUID) error
+}
+
+// IndexAPI defines a set of API methods for removing documents from the
+// text index.
+type IndexAPI interface {
+	Delete(ctx context.Context, linkID uuid.UUID) error
+	DeleteStale(ctx context.Context, indexedBefore time.Time) (int, error)
+}
+
+// Rules specifies which links are garbage-collected. A link is collected if
+// it matches any of the enabled rules.
+type Rules struct {
+	// Links whose last MinFailureCount or more crawl attempts failed in a
+	// row are collected. A zero value disables the rule.
+	MinFailureCount int
+	// Links that have been retrieved at least once but not within MaxAge
+	// are collected. A zero value disables the rule.
+	MaxAge time.Duration
+	// Links that have never been retrieved and that no other link points
+	// to are collected once they have been observed in that state for at
+	// least OrphanGracePeriod. As the observations are not persisted, the
+	// grace period restarts whenever the service is restarted. A zero
+	// value disables the rule.
+	OrphanGracePeriod time.Duration
+}
+
+// Config encapsulates the settings for configuring the garbage collector
+// service.
+type Config struct {
+	// An API for selecting and deleting links from the link graph.
+	GraphAPI GraphAPI
+	// An API for deleting the documents of collected links from the index.
+	IndexAPI IndexAPI
+	// An API for detecting the partition assignments for this service
+	PartitionDetector partition.Detector
+	// A clock instance for generating time-related events. Default wall-clock will be used
+	Clock clock.Clock
+	// The time between subsequent garbage collection passes.
+	UpdateInterval time.Duration
+	// The rules for selecting the links to collect.
+	Rules Rules
+	// Documents that have not been re-indexed within DocumentTTL are
+	// deleted from the text index. As the index is shared by all
+	// partitions, documents are only expired by the instance assigned to
+	// the first partition. A zero value disables document expiry.
+	DocumentTTL time.Duration
+	// If set, the links that would be collected are only reported and
+	// nothing is deleted.
+	DryRun bool
+	// The logger to use
+	Logger *logrus.Entry
+}
+
+func (cfg *Config) Validate() error {
+	var err error
+	if cfg.GraphAPI == nil {
+		err = multierror.Append(err, xerrors.Errorf("graph API has not been provided"))
+	}
+	if cfg.IndexAPI == nil {
+		err = multierror.Append(err, xerrors.Errorf("index API has not been provided"))
+	}
+	if cfg.PartitionDetector == nil {
+		err = multierror.Append(err, xerrors.Errorf("partition detector has not been provided"))
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = clock.WallClock
+	}
+	if cfg.UpdateInterval <= 0 {
+		err = multierror.Append(err, xerrors.Errorf("invalid value for update interval"))
+	}
+	if cfg.Rules.MinFailureCount < 0 || cfg.Rules.MaxAge < 0 || cfg.Rules.OrphanGracePeriod < 0 {
+		err = multierror.Append(err, xerrors.Errorf("invalid value for collection rules"))
+	} else if cfg.DocumentTTL < 0 {
+		err = multierror.Append(err, xerrors.Errorf("invalid value for document TTL"))
+	} else if cfg.Rules == (Rules{}) && cfg.DocumentTTL == 0 {
+		err = multierror.Append(err, xerrors.Errorf("at least one collection rule must be enabled"))
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = logrus.NewEntry(&logrus.Logger{Out: ioutil.Discard})
+	}
+	return err
+}
+
+// Service implements the link garbage collector component for the Agneta
+// Search engine.
+type Service struct {
+	cfg Config
+
+	// orphanSince tracks when each orphaned link in the partition was first
+	// observed.
+	orphanSince map[uuid.UUID]time.Time
+}
+
+// NewService creates a new garbage collector service instance with the
+// specified config.
+func NewService(cfg Config) (*Service, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, xerrors.Errorf("gc service: config validation failed: %w", err)
+	}
+	return &Service{
+		cfg:         cfg,
+		orphanSince: make(map[uuid.UUID]time.Time),
+	}, nil
+}
+
+// Name implements service.Service
+func (svc *Service) Name() string { return "gc" }
+
+// Run implements service.Service
+func (svc *Service) Run(ctx context.Context) error {
+	svc.cfg.Logger.WithFields(logrus.Fields{
+		"update_interval": svc.cfg.UpdateInterval.String(),
+		"dry_run":         svc.cfg.DryRun,
+	}).Info("starting service")
+	defer svc.cfg.Logger.Info("stopped service")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-svc.cfg.Clock.After(svc.cfg.UpdateInterval):
+			curPartition, numPartitions, err := svc.cfg.PartitionDetector.PartitionInfo()
+			if err != nil {
+				if errors.Is(err, partition.ErrPartitionDataAvailableYet) {
+					svc.cfg.Logger.Warn("deferring gc pass: partition data not yet available")
+					continue
+				}
+				return err
+			}
+			if err := svc.collectGarbage(ctx, curPartition, numPartitions); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (svc *Service) collectGarbage(ctx context.Context, curPartition, numPartitions int) error {
+	partRange, err := partition.NewFullRange(numPartitions)
+	if err != nil {
+		return xerrors.Errorf("gc: unable to compute ID ranges for partition: %w", err)
+	}
+	fromID, toID, err := partRange.PartitionExtents(curPartition)
+	if err != nil {
+		return xerrors.Errorf("gc: unable to compute ID ranges for partition: %w", err)
+	}
+	svc.cfg.Logger.WithFields(logrus.Fields{
+		"partition":      curPartition,
+		"num_partitions": numPartitions,
+	}).Info("starting gc pass")
+
+	startAt := svc.cfg.Clock.Now()
+	rep, err := svc.collectPartition(ctx, fromID, toID, startAt)
+	if err != nil {
+		return err
+	}
+
+	var expired int
+	if curPartition == 0 {
+		if expired, err = svc.expireDocuments(ctx, startAt); err != nil {
+			return err
+		}
+	}
+
+	svc.cfg.Logger.WithFields(logrus.Fields{
+		"scanned_link_count":     rep.scanned,
+		"collected_link_count":   len(rep.collected),
+		"deleted_link_count":     rep.deleted,
+		"expired_document_count": expired,
+		"dry_run":                svc.cfg.DryRun,
+		"elapsed_time":           svc.cfg.Clock.Now().Sub(startAt).String(),
+	}).Info("completed gc pass")
+	return nil
+}
+
+// expireDocuments deletes the documents that have not been re-indexed within
+// the configured TTL and returns the number of deleted documents. In dry-run
+// mode, the documents are kept.
+func (svc *Service) expireDocuments(ctx context.Context, now time.Time) (int, error) {
+	if svc.cfg.DocumentTTL == 0 {
+		return 0, nil
+	}
+
+	indexedBefore := now.Add(-svc.cfg.DocumentTTL)
+	if svc.cfg.DryRun {
+		svc.cfg.Logger.WithField("indexed_before", indexedBefore).Info("skipping document expiry in dry-run mode")
+		return 0, nil
+	}
+
+	expired, err := svc.cfg.IndexAPI.DeleteStale(ctx, indexedBefore)
+	if err != nil {
+		return 0, xerrors.Errorf("gc: unable to expire index documents: %w", err)
+	}
+	return expired, nil
+}
+
+// collectedLink describes a link that matched a collection rule.
+type collectedLink struct {
+	link   *graph.Link
+	reason string
+}
+
+// passReport summarizes the outcome of a garbage collection pass.
+type passReport struct {
+	scanned   int
+	collected []collectedLink
+	deleted   int
+}
+
+// collectPartition selects the links in the [fromID, toID) range that match
+// the collection rules and, unless running in dry-run mode, deletes them
+// together with their index documents.
+func (svc *Service) collectPartition(ctx context.Context, fromID, toID uuid.UUID, now time.Time) (*passReport, error) {
+	rep, err := svc.selectLinks(ctx, fromID, toID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range rep.collected {
+		svc.cfg.Logger.WithFields(logrus.Fields{
+			"link_id": c.link.ID.String(),
+			"url":     c.link.URL,
+			"reason":  c.reason,
+			"dry_run": svc.cfg.DryRun,
+		}).Info("collecting link")
+		if svc.cfg.DryRun {
+			continue
+		}
+
+		// Remove the index document first so a failure does not leave a
+		// searchable document behind for a link that no longer exists.
+		if err := svc.cfg.IndexAPI.Delete(ctx, c.link.ID); err != nil && !xerrors.Is(err, index.ErrNotFound) {
+			return nil, xerrors.Errorf("gc: unable to delete index document: %w", err)
+		}
+		if err := svc.cfg.GraphAPI.DeleteLink(ctx, c.link.ID); err != nil && !xerrors.Is(err, graph.ErrNotFound) {
+			return nil, xerrors.Errorf("gc: unable to delete link: %w", err)
+		}
+		delete(svc.orphanSince, c.link.ID)
+		rep.deleted++
+	}
+	return rep, nil
+}
+
+// selectLinks scans the links in the [fromID, toID) range and returns the
+// ones that match the collection rules. The links are collected before any of
+// them is deleted so that the store is not modified while being iterated.
+func (svc *Service) selectLinks(ctx context.Context, fromID, toID uuid.UUID, now time.Time) (*passReport, error) {
+	linkIt, err := svc.cfg.GraphAPI.Links(ctx, fromID, toID, now)
+	if err != nil {
+		return nil, xerrors.Errorf("gc: unable to retrieve links iterator: %w", err)
+	}
+
+	rep := new(passReport)
+	orphans := make(map[uuid.UUID]time.Time)
+	for linkIt.Next() {
+		link := linkIt.Link()
+		rep.scanned++
+
+		reason, err := svc.match(ctx, link, now, orphans)
+		if err != nil {
+			_ = linkIt.Close()
+			return nil, err
+		} else if reason != "" {
+			rep.collected = append(rep.collected, collectedLink{link: link, reason: reason})
+		}
+	}
+	if err = linkIt.Error(); err != nil {
+		_ = linkIt.Close()
+		return nil, xerrors.Errorf("gc: unable to iterate links: %w", err)
+	} else if err = linkIt.Close(); err != nil {
+		return nil, xerrors.Errorf("gc: unable to iterate links: %w", err)
+	}
+
+	// Forget about links that are no longer orphaned.
+	svc.orphanSince = orphans
+	return rep, nil
+}
+
+// match returns the name of the first collection rule that link matches or an
+// empty string if the link should be kept. Links that are currently orphaned
+// are recorded in orphans together with the time they were first observed.
+func (svc *Service) match(ctx context.Context, link *graph.Link, now time.Time, orphans map[uuid.UUID]time.Time) (string, error) {
+	rules := svc.cfg.Rules
+	if rules.MinFailureCount > 0 && link.FailureCount >= rules.MinFailureCount {
+		return "failure_count", nil
+	}
+	if rules.MaxAge > 0 && !link.RetrievedAt.IsZero() && now.Sub(link.RetrievedAt) > rules.MaxAge {
+		return "max_age", nil
+	}
+	if rules.OrphanGracePeriod == 0 || !link.RetrievedAt.IsZero() {
+		return "", nil
+	}
+
+	inbound, err := svc.cfg.GraphAPI.InboundDegree(ctx, link.ID)
+	if err != nil {
+		return "", xerrors.Errorf("gc: unable to count inbound edges: %w", err)
+	} else if inbound != 0 {
+		return "", nil
+	}
+
+	since, found := svc.orphanSince[link.ID]
+	if !found {
+		since = now
+	}
+	orphans[link.ID] = since
+	if now.Sub(since) < rules.OrphanGracePeriod {
+		return "", nil
+	}
+	return "orphan", nil
+}
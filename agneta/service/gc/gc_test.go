@@ -0,0 +1,178 @@
+package gc
+
+import (
+	"Search_Engine/agneta/partition"
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/store/memory"
+	"Search_Engine/textindexer/index"
+	"Search_Engine/textindexer/store/memindex"
+	"context"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(GCTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type GCTestSuite struct {
+	g   *memory.InMemoryGraph
+	idx *memindex.InMemoryBleveIndexer
+
+	now                                  time.Time
+	failing, stale, orphan, linked, live *graph.Link
+}
+
+func (s *GCTestSuite) SetUpTest(c *gc.C) {
+	var err error
+	s.g = memory.NewInMemoryGraph()
+	s.idx, err = memindex.NewInMemoryBleveIndexer()
+	c.Assert(err, gc.IsNil)
+
+	ctx := context.Background()
+	s.now = time.Now()
+	s.failing = &graph.Link{URL: "https://example.com/failing", RetrievedAt: s.now.Add(-time.Hour), FailureCount: 5}
+	s.stale = &graph.Link{URL: "https://example.com/stale", RetrievedAt: s.now.Add(-60 * 24 * time.Hour)}
+	s.orphan = &graph.Link{URL: "https://example.com/orphan"}
+	s.linked = &graph.Link{URL: "https://example.com/linked"}
+	s.live = &graph.Link{URL: "https://example.com", RetrievedAt: s.now.Add(-time.Hour)}
+	for _, link := range []*graph.Link{s.failing, s.stale, s.orphan, s.linked, s.live} {
+		c.Assert(s.g.UpsertLink(ctx, link), gc.IsNil)
+		c.Assert(s.idx.Index(ctx, &index.Document{LinkID: link.ID, URL: link.URL}), gc.IsNil)
+	}
+	c.Assert(s.g.UpsertEdge(ctx, &graph.Edge{Src: s.live.ID, Dst: s.linked.ID}), gc.IsNil)
+}
+
+func (s *GCTestSuite) TestCollectGarbage(c *gc.C) {
+	svc := s.newService(c, false)
+
+	rep, err := svc.collectPartition(context.Background(), uuid.Nil, maxUUID, s.now)
+	c.Assert(err, gc.IsNil)
+	c.Assert(rep.scanned, gc.Equals, 5)
+	c.Assert(collectedReasons(rep), gc.DeepEquals, map[string]string{
+		s.failing.URL: "failure_count",
+		s.stale.URL:   "max_age",
+	})
+	c.Assert(rep.deleted, gc.Equals, 2)
+	s.assertDeleted(c, s.failing, true)
+	s.assertDeleted(c, s.stale, true)
+
+	// The orphan is only collected once its grace period expires.
+	rep, err = svc.collectPartition(context.Background(), uuid.Nil, maxUUID, s.now.Add(2*time.Hour))
+	c.Assert(err, gc.IsNil)
+	c.Assert(collectedReasons(rep), gc.DeepEquals, map[string]string{s.orphan.URL: "orphan"})
+	s.assertDeleted(c, s.orphan, true)
+	s.assertDeleted(c, s.linked, false)
+	s.assertDeleted(c, s.live, false)
+}
+
+func (s *GCTestSuite) TestCollectRediscoveredFailingLink(c *gc.C) {
+	svc := s.newService(c, false)
+	ctx := context.Background()
+
+	// A link that has never been fetched successfully and is still below
+	// the failure threshold.
+	unreachable := &graph.Link{URL: "https://example.com/unreachable", FailureCount: 2, NextCrawlAt: s.now.Add(time.Hour)}
+	c.Assert(s.g.UpsertLink(ctx, unreachable), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(ctx, &graph.Edge{Src: s.live.ID, Dst: unreachable.ID}), gc.IsNil)
+
+	rep, err := svc.collectPartition(ctx, uuid.Nil, maxUUID, s.now)
+	c.Assert(err, gc.IsNil)
+	_, collected := collectedReasons(rep)[unreachable.URL]
+	c.Assert(collected, gc.Equals, false)
+
+	// The link fails once more and is rediscovered by the crawler both
+	// before and after the failure is recorded.
+	c.Assert(s.g.UpsertLink(ctx, &graph.Link{URL: unreachable.URL}), gc.IsNil)
+	c.Assert(s.g.UpsertLink(ctx, &graph.Link{URL: unreachable.URL, FailureCount: 3, NextCrawlAt: s.now.Add(2 * time.Hour)}), gc.IsNil)
+	c.Assert(s.g.UpsertLink(ctx, &graph.Link{URL: unreachable.URL}), gc.IsNil)
+
+	rep, err = svc.collectPartition(ctx, uuid.Nil, maxUUID, s.now)
+	c.Assert(err, gc.IsNil)
+	c.Assert(collectedReasons(rep), gc.DeepEquals, map[string]string{unreachable.URL: "failure_count"})
+	s.assertDeleted(c, unreachable, true)
+}
+
+func (s *GCTestSuite) TestDryRun(c *gc.C) {
+	svc := s.newService(c, true)
+
+	for _, now := range []time.Time{s.now, s.now.Add(2 * time.Hour)} {
+		_, err := svc.collectPartition(context.Background(), uuid.Nil, maxUUID, now)
+		c.Assert(err, gc.IsNil)
+	}
+
+	rep, err := svc.collectPartition(context.Background(), uuid.Nil, maxUUID, s.now.Add(2*time.Hour))
+	c.Assert(err, gc.IsNil)
+	c.Assert(collectedReasons(rep), gc.DeepEquals, map[string]string{
+		s.failing.URL: "failure_count",
+		s.stale.URL:   "max_age",
+		s.orphan.URL:  "orphan",
+	})
+	c.Assert(rep.deleted, gc.Equals, 0)
+	for _, link := range []*graph.Link{s.failing, s.stale, s.orphan} {
+		s.assertDeleted(c, link, false)
+	}
+}
+
+func (s *GCTestSuite) TestExpireDocuments(c *gc.C) {
+	svc := s.newService(c, true)
+	svc.cfg.DocumentTTL = time.Hour
+
+	// Documents are kept in dry-run mode.
+	expired, err := svc.expireDocuments(context.Background(), s.now.Add(2*time.Hour))
+	c.Assert(err, gc.IsNil)
+	c.Assert(expired, gc.Equals, 0)
+	s.assertDeleted(c, s.live, false)
+
+	svc.cfg.DryRun = false
+	expired, err = svc.expireDocuments(context.Background(), s.now.Add(30*time.Minute))
+	c.Assert(err, gc.IsNil)
+	c.Assert(expired, gc.Equals, 0)
+
+	expired, err = svc.expireDocuments(context.Background(), s.now.Add(2*time.Hour))
+	c.Assert(err, gc.IsNil)
+	c.Assert(expired, gc.Equals, 5)
+
+	// Expiry only removes documents; the links are kept.
+	_, err = s.idx.FindByID(context.Background(), s.live.ID)
+	c.Assert(xerrors.Is(err, index.ErrNotFound), gc.Equals, true)
+	_, err = s.g.FindLink(context.Background(), s.live.ID)
+	c.Assert(err, gc.IsNil)
+}
+
+func (s *GCTestSuite) newService(c *gc.C, dryRun bool) *Service {
+	svc, err := NewService(Config{
+		GraphAPI:          s.g,
+		IndexAPI:          s.idx,
+		PartitionDetector: partition.Fixed{Partition: 0, NumPartitions: 1},
+		UpdateInterval:    time.Hour,
+		Rules: Rules{
+			MinFailureCount:   3,
+			MaxAge:            30 * 24 * time.Hour,
+			OrphanGracePeriod: time.Hour,
+		},
+		DryRun: dryRun,
+	})
+	c.Assert(err, gc.IsNil)
+	return svc
+}
+
+func (s *GCTestSuite) assertDeleted(c *gc.C, link *graph.Link, deleted bool) {
+	_, err := s.g.FindLink(context.Background(), link.ID)
+	c.Assert(xerrors.Is(err, graph.ErrNotFound), gc.Equals, deleted, gc.Commentf("link %s", link.URL))
+	_, err = s.idx.FindByID(context.Background(), link.ID)
+	c.Assert(xerrors.Is(err, index.ErrNotFound), gc.Equals, deleted, gc.Commentf("document %s", link.URL))
+}
+
+func collectedReasons(rep *passReport) map[string]string {
+	reasons := make(map[string]string)
+	for _, c := range rep.collected {
+		reasons[c.link.URL] = c.reason
+	}
+	return reasons
+}
+
+var maxUUID = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
@@ -0,0 +1,86 @@
+package graphapi
+
+import (
+	"Search_Engine/agnetaapis/linkgraphapi"
+	"Search_Engine/agnetaapis/linkgraphapi/proto/generated"
+	"Search_Engine/linkgraph/graph"
+	"context"
+	"github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"io/ioutil"
+	"net"
+)
+
+// Config encapsulates the settings for configuring the link graph API
+// service.
+type Config struct {
+	// The link graph to expose.
+	Graph graph.Graph
+
+	// The address to listen for incoming gRPC requests.
+	ListenAddr string
+
+	// The logger to use. If not defined an output-discarding logger will
+	// be used instead.
+	Logger *logrus.Entry
+}
+
+func (cfg *Config) validate() error {
+	var err error
+	if cfg.Graph == nil {
+		err = multierror.Append(err, xerrors.Errorf("graph has not been provided"))
+	}
+	if cfg.ListenAddr == "" {
+		err = multierror.Append(err, xerrors.Errorf("listen address has not been specified"))
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = logrus.NewEntry(&logrus.Logger{Out: ioutil.Discard})
+	}
+	return err
+}
+
+// Service exposes the link graph used by the Agneta Search engine over gRPC
+// so that external tools such as linkgraph-snapshot can access it. This is
+// the only way to reach an in-memory link graph from outside the process.
+type Service struct {
+	cfg Config
+}
+
+// NewService creates a new link graph API service instance with the
+// specified config.
+func NewService(cfg Config) (*Service, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, xerrors.Errorf("link graph API service: config validation failed: %w", err)
+	}
+	return &Service{cfg: cfg}, nil
+}
+
+// Name implements service.Service
+func (svc *Service) Name() string { return "link graph API" }
+
+// Run implements service.Service
+func (svc *Service) Run(ctx context.Context) error {
+	l, err := net.Listen("tcp", svc.cfg.ListenAddr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = l.Close() }()
+
+	srv := grpc.NewServer()
+	generated.RegisterLinkGraphServer(srv, linkgraphapi.NewLinkGraphServer(svc.cfg.Graph))
+	go func() {
+		<-ctx.Done()
+		// Watch streams stay open until their clients go away so the
+		// server is stopped without waiting for them.
+		srv.Stop()
+	}()
+
+	svc.cfg.Logger.WithField("addr", svc.cfg.ListenAddr).Info("starting link graph API server")
+	if err = srv.Serve(l); err == grpc.ErrServerStopped {
+		// Ignore error when the server shuts down.
+		err = nil
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package pagerank
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// The maximum length (in bytes) of the aggregated anchor text for a document.
+const maxAggregatedAnchorTextLength = 4096
+
+// anchorTextAggregator collects the distinct anchor texts of the edges that
+// point to each link. Self-links are ignored and the aggregated text of each
+// link is capped to maxAggregatedAnchorTextLength bytes.
+type anchorTextAggregator struct {
+	texts map[uuid.UUID]*anchorText
+}
+
+// anchorText holds the anchor texts of the edges that point to a link
+// separated by newlines.
+type anchorText struct {
+	b    strings.Builder
+	seen map[string]struct{}
+}
+
+func newAnchorTextAggregator() *anchorTextAggregator {
+	return &anchorTextAggregator{texts: make(map[uuid.UUID]*anchorText)}
+}
+
+// add includes the anchor text of edge in the aggregated text for the link
+// that edge points to.
+func (a *anchorTextAggregator) add(edge *graph.Edge) {
+	if edge.Src == edge.Dst || edge.AnchorText == "" {
+		return
+	}
+
+	text := a.texts[edge.Dst]
+	if text == nil {
+		text = &anchorText{seen: make(map[string]struct{})}
+		a.texts[edge.Dst] = text
+	}
+
+	key := strings.ToLower(edge.AnchorText)
+	if _, dup := text.seen[key]; dup {
+		return
+	}
+	text.seen[key] = struct{}{}
+
+	if text.b.Len()+len(edge.AnchorText)+1 > maxAggregatedAnchorTextLength {
+		return
+	}
+	if text.b.Len() != 0 {
+		text.b.WriteByte('\n')
+	}
+	text.b.WriteString(edge.AnchorText)
+}
+
+// text returns the aggregated anchor text for the specified link.
+func (a *anchorTextAggregator) text(linkID uuid.UUID) string {
+	if text := a.texts[linkID]; text != nil {
+		return text.b.String()
+	}
+	return ""
+}
@@ -5,6 +5,7 @@ import (
 	"Search_Engine/graphprocessing/bspgraph"
 	pr "Search_Engine/graphprocessing/pagerank"
 	"Search_Engine/linkgraph/graph"
+	"Search_Engine/textindexer/index"
 	"context"
 	"errors"
 	"github.com/google/uuid"
@@ -19,13 +20,15 @@ import (
 // GraphAPI defines a set of API methods for fetching the links and edges from
 // the link graph.
 type GraphAPI interface {
-	Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
-	Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error)
+	Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
+	Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error)
 }
 
-// IndexAPI defines a set of methods for updating PageRank scores for indexed documents.
+// IndexAPI defines a set of methods for updating the PageRank scores and the
+// anchor text of indexed documents.
 type IndexAPI interface {
-	UpdateScore(linkID uuid.UUID, score float64) error
+	UpdateScore(ctx context.Context, linkID uuid.UUID, score float64) error
+	UpdateAnchorText(ctx context.Context, linkID uuid.UUID, anchorText string) error
 }
 
 // Config encapsulates the settings for configuring the PageRank calculator service.
@@ -80,10 +83,13 @@ func (cfg *Config) Validate() error {
 	return err
 }
 
-// Service implements the PAgeRank calculator component for the Agneta Search engine
+// Service implements the PAgeRank calculator component for the Agneta Search engine.
+// Each pass also refreshes the aggregated inbound anchor text of the indexed
+// documents from the edges that are loaded into the calculator.
 type Service struct {
-	cfg        Config
-	calculator *pr.Calculator
+	cfg         Config
+	calculator  *pr.Calculator
+	anchorTexts *anchorTextAggregator
 }
 
 // NewService creates a new PageRank calculator service instance with the specified
@@ -140,11 +146,13 @@ func (svc *Service) updateGraphScores(ctx context.Context) error {
 	startAt := svc.cfg.Clock.Now()
 	maxUUID := uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
 	tick := startAt
+	svc.anchorTexts = newAnchorTextAggregator()
+	defer func() { svc.anchorTexts = nil }()
 	if err := svc.calculator.Graph().Reset(); err != nil {
 		return err
-	} else if err := svc.loadLinks(uuid.Nil, maxUUID, startAt); err != nil {
+	} else if err := svc.loadLinks(ctx, uuid.Nil, maxUUID, startAt); err != nil {
 		return err
-	} else if err := svc.loadEdges(uuid.Nil, maxUUID, startAt); err != nil {
+	} else if err := svc.loadEdges(ctx, uuid.Nil, maxUUID, startAt); err != nil {
 		return err
 	}
 	graphPopulateTime := svc.cfg.Clock.Now().Sub(tick)
@@ -156,7 +164,10 @@ func (svc *Service) updateGraphScores(ctx context.Context) error {
 	scoreCalculationTime := svc.cfg.Clock.Now().Sub(tick)
 
 	tick = svc.cfg.Clock.Now()
-	if err := svc.calculator.Scores(svc.persistScore); err != nil {
+	persistScore := func(vertexID string, score float64) error {
+		return svc.persistScore(ctx, vertexID, score)
+	}
+	if err := svc.calculator.Scores(persistScore); err != nil {
 		return err
 	}
 	scorePersistTime := svc.cfg.Clock.Now().Sub(tick)
@@ -171,17 +182,27 @@ func (svc *Service) updateGraphScores(ctx context.Context) error {
 	return nil
 }
 
-func (svc *Service) persistScore(vertexID string, score float64) error {
+func (svc *Service) persistScore(ctx context.Context, vertexID string, score float64) error {
 	linkID, err := uuid.Parse(vertexID)
 	if err != nil {
 		return err
 	}
 
-	return svc.cfg.IndexAPI.UpdateScore(linkID, score)
+	if err = svc.cfg.IndexAPI.UpdateScore(ctx, linkID, score); err != nil {
+		return err
+	}
+
+	// Links that have not been indexed yet get their anchor text once they
+	// are indexed and the next pass runs.
+	err = svc.cfg.IndexAPI.UpdateAnchorText(ctx, linkID, svc.anchorTexts.text(linkID))
+	if err != nil && !xerrors.Is(err, index.ErrNotFound) {
+		return err
+	}
+	return nil
 }
 
-func (svc *Service) loadLinks(fromID, toID uuid.UUID, filter time.Time) error {
-	linkIt, err := svc.cfg.GraphAPI.Links(fromID, toID, filter)
+func (svc *Service) loadLinks(ctx context.Context, fromID, toID uuid.UUID, filter time.Time) error {
+	linkIt, err := svc.cfg.GraphAPI.Links(ctx, fromID, toID, filter)
 	if err != nil {
 		return err
 	}
@@ -198,14 +219,15 @@ func (svc *Service) loadLinks(fromID, toID uuid.UUID, filter time.Time) error {
 	return linkIt.Close()
 }
 
-func (svc *Service) loadEdges(fromID, toID uuid.UUID, filter time.Time) error {
-	edgeIt, err := svc.cfg.GraphAPI.Edges(fromID, toID, filter)
+func (svc *Service) loadEdges(ctx context.Context, fromID, toID uuid.UUID, filter time.Time) error {
+	edgeIt, err := svc.cfg.GraphAPI.Edges(ctx, fromID, toID, filter)
 	if err != nil {
 		return err
 	}
 
 	for edgeIt.Next() {
 		edge := edgeIt.Edge()
+		svc.anchorTexts.add(edge)
 		// As new edges may have been created since the links were loaded be
 		// tolerant to UnknownEdgeSource errors.
 		if err = svc.calculator.AddEdge(edge.Src.String(), edge.Dst.String()); err != nil && !xerrors.Is(err, bspgraph.ErrUnknownEdgeSource) {
@@ -5,57 +5,122 @@ import (
 	"Search_Engine/linkgraph/graph"
 	"context"
 	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 	"io"
 	"time"
 )
 
+// Compile-time check for ensuring LinkGraphClient implements Restorer.
+var _ graph.Restorer = (*LinkGraphClient)(nil)
+
 // LinkGraphClient provides an API compatible with the graph.Graph interface
 // for accessing graph instances exposed by a remote gRPC server.
 type LinkGraphClient struct {
-	ctx context.Context
 	cli generated.LinkGraphClient
 }
 
 // NewLinkGraphClient returns a new client instance that implements a subset
 // of the graph.Graph interface by delegating methods to a graph instance
 // exposed by a remote gRPC sever.
-func NewLinkGraphClient(ctx context.Context, rpcClient generated.LinkGraphClient) *LinkGraphClient {
-	return &LinkGraphClient{ctx: ctx, cli: rpcClient}
+func NewLinkGraphClient(rpcClient generated.LinkGraphClient) *LinkGraphClient {
+	return &LinkGraphClient{cli: rpcClient}
 }
 
 // UpsertLink creates a new link or updates an existing link.
-func (c *LinkGraphClient) UpsertLink(link *graph.Link) error {
-	req := &generated.Link{
-		Uuid:        link.ID[:],
-		Url:         link.URL,
-		RetrievedAt: timeToProto(link.RetrievedAt),
+func (c *LinkGraphClient) UpsertLink(ctx context.Context, link *graph.Link) error {
+	res, err := c.cli.UpsertLink(ctx, linkToProto(link))
+	if err != nil {
+		return fromRPCError("upsert link", err)
 	}
-	res, err := c.cli.UpsertLink(c.ctx, req)
+
+	stored, err := linkFromProto(res)
 	if err != nil {
 		return err
 	}
+	*link = *stored
+	return nil
+}
 
-	link.ID = uuidFromBytes(res.Uuid)
-	link.URL = res.Url
-	if link.RetrievedAt, err = ptypes.Timestamp(res.RetrievedAt); err != nil {
+// UpsertLinks creates or updates a batch of links. Links that have been
+// deleted from the graph are skipped and have their ID set to uuid.Nil.
+func (c *LinkGraphClient) UpsertLinks(ctx context.Context, links []*graph.Link) error {
+	stream, err := c.cli.UpsertLinks(ctx)
+	if err != nil {
 		return err
 	}
 
+	for _, link := range links {
+		if err = stream.Send(linkToProto(link)); err != nil {
+			break
+		}
+	}
+
+	// Send returns io.EOF if the server aborted the stream; the actual
+	// error is returned by CloseAndRecv.
+	res, recvErr := stream.CloseAndRecv()
+	if recvErr != nil {
+		return fromRPCError("upsert links", recvErr)
+	} else if err != nil && err != io.EOF {
+		return err
+	} else if len(res.Links) != len(links) {
+		return xerrors.Errorf("upsert links: expected %d links in response; got %d", len(links), len(res.Links))
+	}
+
+	for i, msg := range res.Links {
+		stored, err := linkFromProto(msg)
+		if err != nil {
+			return err
+		}
+		*links[i] = *stored
+	}
+	return nil
+}
+
+// FindLink looks up a link by its ID.
+func (c *LinkGraphClient) FindLink(ctx context.Context, id uuid.UUID) (*graph.Link, error) {
+	res, err := c.cli.FindLink(ctx, &generated.LinkID{Uuid: id[:]})
+	if err != nil {
+		return nil, fromRPCError("find link", err)
+	}
+	return linkFromProto(res)
+}
+
+// FindLinkByURL looks up a link by its URL.
+func (c *LinkGraphClient) FindLinkByURL(ctx context.Context, url string) (*graph.Link, error) {
+	res, err := c.cli.FindLinkByURL(ctx, &generated.LinkURL{Url: url})
+	if err != nil {
+		return nil, fromRPCError("find link by URL", err)
+	}
+	return linkFromProto(res)
+}
+
+// DeleteLink removes a link together with all edges that originate from or
+// point to it.
+func (c *LinkGraphClient) DeleteLink(ctx context.Context, id uuid.UUID) error {
+	_, err := c.cli.DeleteLink(ctx, &generated.LinkID{Uuid: id[:]})
+	if err != nil {
+		return fromRPCError("delete link", err)
+	}
 	return nil
 }
 
 // UpsertEdge creates a new edge or updates an existing edge.
-func (c *LinkGraphClient) UpsertEdge(edge *graph.Edge) error {
+func (c *LinkGraphClient) UpsertEdge(ctx context.Context, edge *graph.Edge) error {
 	req := &generated.Edge{
-		Uuid:    edge.ID[:],
-		SrcUuid: edge.Src[:],
-		DstUuid: edge.Dst[:],
+		Uuid:       edge.ID[:],
+		SrcUuid:    edge.Src[:],
+		DstUuid:    edge.Dst[:],
+		AnchorText: edge.AnchorText,
+		Rel:        edge.Rel,
 	}
-	res, err := c.cli.UpsertEdge(c.ctx, req)
+	res, err := c.cli.UpsertEdge(ctx, req)
 	if err != nil {
-		return err
+		return fromRPCError("upsert edge", err)
 	}
 
 	edge.ID = uuidFromBytes(res.Uuid)
@@ -66,9 +131,50 @@ func (c *LinkGraphClient) UpsertEdge(edge *graph.Edge) error {
 	return nil
 }
 
+// UpsertEdges creates or updates a batch of edges.
+func (c *LinkGraphClient) UpsertEdges(ctx context.Context, edges []*graph.Edge) error {
+	stream, err := c.cli.UpsertEdges(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, edge := range edges {
+		req := &generated.Edge{
+			Uuid:       edge.ID[:],
+			SrcUuid:    edge.Src[:],
+			DstUuid:    edge.Dst[:],
+			AnchorText: edge.AnchorText,
+			Rel:        edge.Rel,
+		}
+		if err = stream.Send(req); err != nil {
+			break
+		}
+	}
+
+	// Send returns io.EOF if the server aborted the stream; the actual
+	// error is returned by CloseAndRecv.
+	res, recvErr := stream.CloseAndRecv()
+	if recvErr != nil {
+		return fromRPCError("upsert edges", recvErr)
+	} else if err != nil && err != io.EOF {
+		return err
+	} else if len(res.Edges) != len(edges) {
+		return xerrors.Errorf("upsert edges: expected %d edges in response; got %d", len(edges), len(res.Edges))
+	}
+
+	for i, msg := range res.Edges {
+		stored, err := edgeFromProto(msg)
+		if err != nil {
+			return err
+		}
+		*edges[i] = *stored
+	}
+	return nil
+}
+
 // Links returns an iterator for the set of links whose IDs belong to the
 // [fromID, toID) range and were last accessed before the provided value.
-func (c *LinkGraphClient) Links(fromID, toID uuid.UUID, accessedBefore time.Time) (graph.LinkIterator, error) {
+func (c *LinkGraphClient) Links(ctx context.Context, fromID, toID uuid.UUID, accessedBefore time.Time) (graph.LinkIterator, error) {
 	//filter, err := ptypes.TimestampProto(accessedBefore)
 	filter := timestamppb.New(accessedBefore)
 	//if err != nil {
@@ -81,7 +187,7 @@ func (c *LinkGraphClient) Links(fromID, toID uuid.UUID, accessedBefore time.Time
 		Filter:   filter,
 	}
 
-	ctx, cancelFn := context.WithCancel(c.ctx)
+	ctx, cancelFn := context.WithCancel(ctx)
 	stream, err := c.cli.Links(ctx, req)
 	if err != nil {
 		cancelFn()
@@ -94,7 +200,7 @@ func (c *LinkGraphClient) Links(fromID, toID uuid.UUID, accessedBefore time.Time
 // Edges returns an iterator for the set of edges whose source vertex IDs
 // belong to the [fromID, toID) range and were last updated before the provided
 // value.
-func (c *LinkGraphClient) Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
+func (c *LinkGraphClient) Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
 	//filter, err := ptypes.TimestampProto(updatedBefore)
 	filter := timestamppb.New(updatedBefore)
 	//if err != nil {
@@ -106,7 +212,7 @@ func (c *LinkGraphClient) Edges(fromID, toID uuid.UUID, updatedBefore time.Time)
 		Filter:   filter,
 	}
 
-	ctx, cancelFn := context.WithCancel(c.ctx)
+	ctx, cancelFn := context.WithCancel(ctx)
 	stream, err := c.cli.Edges(ctx, req)
 	if err != nil {
 		cancelFn()
@@ -116,18 +222,139 @@ func (c *LinkGraphClient) Edges(fromID, toID uuid.UUID, updatedBefore time.Time)
 	return &edgeIterator{stream: stream, cancelFn: cancelFn}, nil
 }
 
+// EdgesTo returns an iterator for the set of edges whose destination vertex is
+// the specified link ID.
+func (c *LinkGraphClient) EdgesTo(ctx context.Context, dstID uuid.UUID) (graph.EdgeIterator, error) {
+	ctx, cancelFn := context.WithCancel(ctx)
+	stream, err := c.cli.EdgesTo(ctx, &generated.LinkID{Uuid: dstID[:]})
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+
+	return &edgeIterator{stream: stream, cancelFn: cancelFn}, nil
+}
+
+// InboundDegree returns the number of edges whose destination vertex is the
+// specified link ID.
+func (c *LinkGraphClient) InboundDegree(ctx context.Context, dstID uuid.UUID) (int, error) {
+	res, err := c.cli.InboundDegree(ctx, &generated.LinkID{Uuid: dstID[:]})
+	if err != nil {
+		return 0, err
+	}
+	return int(res.Count), nil
+}
+
+// WatchLinks returns a watcher that receives an event for each link that is
+// created, updated or deleted after the call returns.
+func (c *LinkGraphClient) WatchLinks(ctx context.Context) (graph.LinkWatcher, error) {
+	ctx, cancelFn := context.WithCancel(ctx)
+	stream, err := c.cli.WatchLinks(ctx, new(empty.Empty))
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+
+	w := &linkWatcher{
+		events:   make(chan graph.LinkEvent),
+		cancelFn: cancelFn,
+	}
+	go w.recvEvents(ctx, stream)
+	return w, nil
+}
+
 // RemoveStaleEdges removes any edge that originates from the specified link ID
 // and was updated before the specified timestamp.
-func (c *LinkGraphClient) RemoveStaleEdges(from uuid.UUID, updatedBefore time.Time) error {
+func (c *LinkGraphClient) RemoveStaleEdges(ctx context.Context, from uuid.UUID, updatedBefore time.Time) error {
 	req := &generated.RemoveStaleEdgesQuery{
 		FromUuid:      from[:],
 		UpdatedBefore: timeToProto(updatedBefore),
 	}
 
-	_, err := c.cli.RemoveStaleEdges(c.ctx, req)
+	_, err := c.cli.RemoveStaleEdges(ctx, req)
 	return err
 }
 
+// UpsertHost creates a new host or updates the crawl settings of an existing
+// host.
+func (c *LinkGraphClient) UpsertHost(ctx context.Context, host *graph.Host) error {
+	res, err := c.cli.UpsertHost(ctx, hostToProto(host))
+	if err != nil {
+		return fromRPCError("upsert host", err)
+	}
+
+	stored, err := hostFromProto(res)
+	if err != nil {
+		return err
+	}
+	*host = *stored
+	return nil
+}
+
+// FindHost looks up a host by its name.
+func (c *LinkGraphClient) FindHost(ctx context.Context, name string) (*graph.Host, error) {
+	res, err := c.cli.FindHost(ctx, &generated.HostName{Name: name})
+	if err != nil {
+		return nil, fromRPCError("find host", err)
+	}
+	return hostFromProto(res)
+}
+
+// RecordHostFetch records a fetch attempt for the specified host.
+func (c *LinkGraphClient) RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error {
+	req := &generated.HostFetch{
+		Name:      name,
+		FetchedAt: timeToProto(fetchedAt),
+		Failed:    failed,
+	}
+	if _, err := c.cli.RecordHostFetch(ctx, req); err != nil {
+		return fromRPCError("record host fetch", err)
+	}
+	return nil
+}
+
+// RestoreLinks inserts or replaces a batch of links keeping their IDs.
+func (c *LinkGraphClient) RestoreLinks(ctx context.Context, links []*graph.Link) error {
+	req := &generated.LinkBatch{Links: make([]*generated.Link, len(links))}
+	for i, link := range links {
+		req.Links[i] = linkToProto(link)
+	}
+	if _, err := c.cli.RestoreLinks(ctx, req); err != nil {
+		return fromRPCError("restore links", err)
+	}
+	return nil
+}
+
+// RestoreEdges inserts or replaces a batch of edges keeping their IDs and
+// update timestamps.
+func (c *LinkGraphClient) RestoreEdges(ctx context.Context, edges []*graph.Edge) error {
+	req := &generated.EdgeBatch{Edges: make([]*generated.Edge, len(edges))}
+	for i, edge := range edges {
+		req.Edges[i] = edgeToProto(edge)
+	}
+	if _, err := c.cli.RestoreEdges(ctx, req); err != nil {
+		return fromRPCError("restore edges", err)
+	}
+	return nil
+}
+
+// fromRPCError converts the gRPC status errors returned by the server back
+// into the graph errors they were mapped from.
+func fromRPCError(op string, err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return xerrors.Errorf("%s: %w", op, graph.ErrNotFound)
+	case codes.FailedPrecondition:
+		return xerrors.Errorf("%s: %w", op, graph.ErrLinkDeleted)
+	case codes.InvalidArgument:
+		return xerrors.Errorf("%s: %w", op, graph.ErrUnknownEdgeLinks)
+	case codes.AlreadyExists:
+		return xerrors.Errorf("%s: %w", op, graph.ErrIDConflict)
+	default:
+		return err
+	}
+}
+
 type linkIterator struct {
 	stream  generated.LinkGraph_LinksClient
 	next    *graph.Link
@@ -150,18 +377,14 @@ func (it *linkIterator) Next() bool {
 		return false
 	}
 
-	lastAccessed, err := ptypes.Timestamp(res.RetrievedAt)
+	link, err := linkFromProto(res)
 	if err != nil {
 		it.lastErr = err
 		it.cancelFn()
 		return false
 	}
 
-	it.next = &graph.Link{
-		ID:          uuidFromBytes(res.Uuid),
-		URL:         res.Url,
-		RetrievedAt: lastAccessed,
-	}
+	it.next = link
 	return true
 }
 
@@ -177,8 +400,65 @@ func (it *linkIterator) Close() error {
 	return nil
 }
 
+// linkWatcher is a graph.LinkWatcher implementation that relays the events
+// received by a WatchLinks stream.
+type linkWatcher struct {
+	events  chan graph.LinkEvent
+	lastErr error
+
+	// A function to cancel the context used to perform the streaming RPC.
+	cancelFn func()
+}
+
+func (w *linkWatcher) recvEvents(ctx context.Context, stream generated.LinkGraph_WatchLinksClient) {
+	defer close(w.events)
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			// Errors caused by the watcher being closed are not reported.
+			if err != io.EOF && ctx.Err() == nil {
+				w.lastErr = err
+			}
+			return
+		}
+
+		link, err := linkFromProto(res.Link)
+		if err != nil {
+			w.lastErr = err
+			w.cancelFn()
+			return
+		}
+
+		// The graph event types share the same values as the proto enum.
+		select {
+		case w.events <- graph.LinkEvent{Type: graph.LinkEventType(res.Type), Link: link}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Events returns a channel that emits link change events.
+func (w *linkWatcher) Events() <-chan graph.LinkEvent { return w.events }
+
+// Error returns the error that caused the event channel to be closed. It
+// should only be called after the event channel has been closed.
+func (w *linkWatcher) Error() error { return w.lastErr }
+
+// Close stops the watcher.
+func (w *linkWatcher) Close() error {
+	w.cancelFn()
+	return nil
+}
+
+// edgeStream is implemented by the client side of the server-streaming RPCs
+// that return edges.
+type edgeStream interface {
+	Recv() (*generated.Edge, error)
+}
+
 type edgeIterator struct {
-	stream  generated.LinkGraph_EdgesClient
+	stream  edgeStream
 	next    *graph.Edge
 	lastErr error
 
@@ -199,19 +479,14 @@ func (it *edgeIterator) Next() bool {
 		return false
 	}
 
-	updatedAt, err := ptypes.Timestamp(res.UpdatedAt)
+	edge, err := edgeFromProto(res)
 	if err != nil {
 		it.lastErr = err
 		it.cancelFn()
 		return false
 	}
 
-	it.next = &graph.Edge{
-		ID:        uuidFromBytes(res.Uuid),
-		Src:       uuidFromBytes(res.SrcUuid),
-		Dst:       uuidFromBytes(res.DstUuid),
-		UpdatedAt: updatedAt,
-	}
+	it.next = edge
 	return true
 }
 
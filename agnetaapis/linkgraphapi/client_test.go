@@ -0,0 +1,74 @@
+package linkgraphapi
+
+import (
+	"Search_Engine/agnetaapis/linkgraphapi/proto/generated"
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/store/memory"
+	"context"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	gc "gopkg.in/check.v1"
+	"net"
+	"time"
+)
+
+var _ = gc.Suite(new(LinkGraphClientTestSuite))
+
+type LinkGraphClientTestSuite struct {
+	g    *memory.InMemoryGraph
+	srv  *grpc.Server
+	conn *grpc.ClientConn
+	cli  *LinkGraphClient
+}
+
+func (s *LinkGraphClientTestSuite) SetUpTest(c *gc.C) {
+	s.g = memory.NewInMemoryGraph()
+	l := bufconn.Listen(1024 * 1024)
+	s.srv = grpc.NewServer()
+	generated.RegisterLinkGraphServer(s.srv, NewLinkGraphServer(s.g))
+	go func() { _ = s.srv.Serve(l) }()
+
+	dialer := func(context.Context, string) (net.Conn, error) { return l.Dial() }
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(dialer), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	c.Assert(err, gc.IsNil)
+	s.conn = conn
+	s.cli = NewLinkGraphClient(generated.NewLinkGraphClient(conn))
+}
+
+func (s *LinkGraphClientTestSuite) TearDownTest(c *gc.C) {
+	_ = s.conn.Close()
+	s.srv.Stop()
+}
+
+func (s *LinkGraphClientTestSuite) TestRestore(c *gc.C) {
+	retrievedAt := time.Now().Truncate(time.Second).UTC()
+	links := []*graph.Link{
+		{ID: uuid.New(), URL: "https://example.com/a", RetrievedAt: retrievedAt, StatusCode: 200},
+		{ID: uuid.New(), URL: "https://example.com/b"},
+	}
+	c.Assert(s.cli.RestoreLinks(context.Background(), links), gc.IsNil)
+
+	updatedAt := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	edge := &graph.Edge{ID: uuid.New(), Src: links[0].ID, Dst: links[1].ID, UpdatedAt: updatedAt, AnchorText: "b"}
+	c.Assert(s.cli.RestoreEdges(context.Background(), []*graph.Edge{edge}), gc.IsNil)
+
+	for _, link := range links {
+		stored, err := s.g.FindLink(context.Background(), link.ID)
+		c.Assert(err, gc.IsNil)
+		c.Assert(stored, gc.DeepEquals, link)
+	}
+	it, err := s.g.EdgesTo(context.Background(), links[1].ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Edge(), gc.DeepEquals, edge)
+	c.Assert(it.Close(), gc.IsNil)
+
+	// Graph errors are reconstructed by the client.
+	err = s.cli.RestoreEdges(context.Background(), []*graph.Edge{{ID: edge.ID, Src: links[1].ID, Dst: links[0].ID, UpdatedAt: updatedAt}})
+	c.Assert(xerrors.Is(err, graph.ErrIDConflict), gc.Equals, true)
+	err = s.cli.RestoreEdges(context.Background(), []*graph.Edge{{ID: uuid.New(), Src: links[0].ID, Dst: uuid.New(), UpdatedAt: updatedAt}})
+	c.Assert(xerrors.Is(err, graph.ErrUnknownEdgeLinks), gc.Equals, true)
+}
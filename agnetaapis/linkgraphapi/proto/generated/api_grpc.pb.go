@@ -25,13 +25,42 @@ const _ = grpc.SupportPackageIsVersion7
 type LinkGraphClient interface {
 	// Upserts inserts or updates a link.
 	UpsertLink(ctx context.Context, in *Link, opts ...grpc.CallOption) (*Link, error)
+	// UpsertLinks inserts or updates a stream of links. Links that have been
+	// deleted are skipped and returned without a uuid.
+	UpsertLinks(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertLinksClient, error)
+	// FindLink looks up a link by its ID.
+	FindLink(ctx context.Context, in *LinkID, opts ...grpc.CallOption) (*Link, error)
+	// FindLinkByURL looks up a link by its URL.
+	FindLinkByURL(ctx context.Context, in *LinkURL, opts ...grpc.CallOption) (*Link, error)
+	// DeleteLink removes a link and all edges that originate from or point to it.
+	DeleteLink(ctx context.Context, in *LinkID, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	// UpsertEdge inserts or updates an edge.
 	UpsertEdge(ctx context.Context, in *Edge, opts ...grpc.CallOption) (*Edge, error)
+	// UpsertEdges inserts or updates a stream of edges.
+	UpsertEdges(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertEdgesClient, error)
 	// Links streams the set of Links in the specifies ID range.
 	Links(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_LinksClient, error)
 	// Edges streams the set of edges in the specified ID range.
 	Edges(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_EdgesClient, error)
 	RemoveStaleEdges(ctx context.Context, in *RemoveStaleEdgesQuery, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// EdgesTo streams the set of edges that point to the specified link.
+	EdgesTo(ctx context.Context, in *LinkID, opts ...grpc.CallOption) (LinkGraph_EdgesToClient, error)
+	// InboundDegree returns the number of edges that point to the specified link.
+	InboundDegree(ctx context.Context, in *LinkID, opts ...grpc.CallOption) (*InboundDegreeResponse, error)
+	// WatchLinks streams an event for each link that is created, updated or
+	// deleted while the stream is open.
+	WatchLinks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (LinkGraph_WatchLinksClient, error)
+	// UpsertHost inserts a host or updates the crawl settings of a host.
+	UpsertHost(ctx context.Context, in *Host, opts ...grpc.CallOption) (*Host, error)
+	// FindHost looks up a host by its name.
+	FindHost(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*Host, error)
+	// RecordHostFetch records a fetch attempt for a host.
+	RecordHostFetch(ctx context.Context, in *HostFetch, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// RestoreLinks inserts or replaces a batch of links keeping their IDs.
+	RestoreLinks(ctx context.Context, in *LinkBatch, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// RestoreEdges inserts or replaces a batch of edges keeping their IDs and
+	// update timestamps.
+	RestoreEdges(ctx context.Context, in *EdgeBatch, opts ...grpc.CallOption) (*emptypb.Empty, error)
 }
 
 type linkGraphClient struct {
@@ -51,6 +80,67 @@ func (c *linkGraphClient) UpsertLink(ctx context.Context, in *Link, opts ...grpc
 	return out, nil
 }
 
+func (c *linkGraphClient) UpsertLinks(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertLinksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[0], "/proto.LinkGraph/UpsertLinks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphUpsertLinksClient{stream}
+	return x, nil
+}
+
+type LinkGraph_UpsertLinksClient interface {
+	Send(*Link) error
+	CloseAndRecv() (*LinkBatch, error)
+	grpc.ClientStream
+}
+
+type linkGraphUpsertLinksClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphUpsertLinksClient) Send(m *Link) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertLinksClient) CloseAndRecv() (*LinkBatch, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(LinkBatch)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) FindLink(ctx context.Context, in *LinkID, opts ...grpc.CallOption) (*Link, error) {
+	out := new(Link)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/FindLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) FindLinkByURL(ctx context.Context, in *LinkURL, opts ...grpc.CallOption) (*Link, error) {
+	out := new(Link)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/FindLinkByURL", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) DeleteLink(ctx context.Context, in *LinkID, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/DeleteLink", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *linkGraphClient) UpsertEdge(ctx context.Context, in *Edge, opts ...grpc.CallOption) (*Edge, error) {
 	out := new(Edge)
 	err := c.cc.Invoke(ctx, "/proto.LinkGraph/UpsertEdge", in, out, opts...)
@@ -60,8 +150,42 @@ func (c *linkGraphClient) UpsertEdge(ctx context.Context, in *Edge, opts ...grpc
 	return out, nil
 }
 
+func (c *linkGraphClient) UpsertEdges(ctx context.Context, opts ...grpc.CallOption) (LinkGraph_UpsertEdgesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[1], "/proto.LinkGraph/UpsertEdges", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphUpsertEdgesClient{stream}
+	return x, nil
+}
+
+type LinkGraph_UpsertEdgesClient interface {
+	Send(*Edge) error
+	CloseAndRecv() (*EdgeBatch, error)
+	grpc.ClientStream
+}
+
+type linkGraphUpsertEdgesClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphUpsertEdgesClient) Send(m *Edge) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertEdgesClient) CloseAndRecv() (*EdgeBatch, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(EdgeBatch)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *linkGraphClient) Links(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_LinksClient, error) {
-	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[0], "/proto.LinkGraph/Links", opts...)
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[2], "/proto.LinkGraph/Links", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +217,7 @@ func (x *linkGraphLinksClient) Recv() (*Link, error) {
 }
 
 func (c *linkGraphClient) Edges(ctx context.Context, in *Range, opts ...grpc.CallOption) (LinkGraph_EdgesClient, error) {
-	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[1], "/proto.LinkGraph/Edges", opts...)
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[3], "/proto.LinkGraph/Edges", opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -133,19 +257,166 @@ func (c *linkGraphClient) RemoveStaleEdges(ctx context.Context, in *RemoveStaleE
 	return out, nil
 }
 
+func (c *linkGraphClient) EdgesTo(ctx context.Context, in *LinkID, opts ...grpc.CallOption) (LinkGraph_EdgesToClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[4], "/proto.LinkGraph/EdgesTo", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphEdgesToClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LinkGraph_EdgesToClient interface {
+	Recv() (*Edge, error)
+	grpc.ClientStream
+}
+
+type linkGraphEdgesToClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphEdgesToClient) Recv() (*Edge, error) {
+	m := new(Edge)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) InboundDegree(ctx context.Context, in *LinkID, opts ...grpc.CallOption) (*InboundDegreeResponse, error) {
+	out := new(InboundDegreeResponse)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/InboundDegree", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) WatchLinks(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (LinkGraph_WatchLinksClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LinkGraph_ServiceDesc.Streams[5], "/proto.LinkGraph/WatchLinks", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &linkGraphWatchLinksClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LinkGraph_WatchLinksClient interface {
+	Recv() (*LinkEvent, error)
+	grpc.ClientStream
+}
+
+type linkGraphWatchLinksClient struct {
+	grpc.ClientStream
+}
+
+func (x *linkGraphWatchLinksClient) Recv() (*LinkEvent, error) {
+	m := new(LinkEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *linkGraphClient) UpsertHost(ctx context.Context, in *Host, opts ...grpc.CallOption) (*Host, error) {
+	out := new(Host)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/UpsertHost", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) FindHost(ctx context.Context, in *HostName, opts ...grpc.CallOption) (*Host, error) {
+	out := new(Host)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/FindHost", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) RecordHostFetch(ctx context.Context, in *HostFetch, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RecordHostFetch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) RestoreLinks(ctx context.Context, in *LinkBatch, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RestoreLinks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *linkGraphClient) RestoreEdges(ctx context.Context, in *EdgeBatch, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.LinkGraph/RestoreEdges", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LinkGraphServer is the server API for LinkGraph service.
 // All implementations must embed UnimplementedLinkGraphServer
 // for forward compatibility
 type LinkGraphServer interface {
 	// Upserts inserts or updates a link.
 	UpsertLink(context.Context, *Link) (*Link, error)
+	// UpsertLinks inserts or updates a stream of links. Links that have been
+	// deleted are skipped and returned without a uuid.
+	UpsertLinks(LinkGraph_UpsertLinksServer) error
+	// FindLink looks up a link by its ID.
+	FindLink(context.Context, *LinkID) (*Link, error)
+	// FindLinkByURL looks up a link by its URL.
+	FindLinkByURL(context.Context, *LinkURL) (*Link, error)
+	// DeleteLink removes a link and all edges that originate from or point to it.
+	DeleteLink(context.Context, *LinkID) (*emptypb.Empty, error)
 	// UpsertEdge inserts or updates an edge.
 	UpsertEdge(context.Context, *Edge) (*Edge, error)
+	// UpsertEdges inserts or updates a stream of edges.
+	UpsertEdges(LinkGraph_UpsertEdgesServer) error
 	// Links streams the set of Links in the specifies ID range.
 	Links(*Range, LinkGraph_LinksServer) error
 	// Edges streams the set of edges in the specified ID range.
 	Edges(*Range, LinkGraph_EdgesServer) error
 	RemoveStaleEdges(context.Context, *RemoveStaleEdgesQuery) (*emptypb.Empty, error)
+	// EdgesTo streams the set of edges that point to the specified link.
+	EdgesTo(*LinkID, LinkGraph_EdgesToServer) error
+	// InboundDegree returns the number of edges that point to the specified link.
+	InboundDegree(context.Context, *LinkID) (*InboundDegreeResponse, error)
+	// WatchLinks streams an event for each link that is created, updated or
+	// deleted while the stream is open.
+	WatchLinks(*emptypb.Empty, LinkGraph_WatchLinksServer) error
+	// UpsertHost inserts a host or updates the crawl settings of a host.
+	UpsertHost(context.Context, *Host) (*Host, error)
+	// FindHost looks up a host by its name.
+	FindHost(context.Context, *HostName) (*Host, error)
+	// RecordHostFetch records a fetch attempt for a host.
+	RecordHostFetch(context.Context, *HostFetch) (*emptypb.Empty, error)
+	// RestoreLinks inserts or replaces a batch of links keeping their IDs.
+	RestoreLinks(context.Context, *LinkBatch) (*emptypb.Empty, error)
+	// RestoreEdges inserts or replaces a batch of edges keeping their IDs and
+	// update timestamps.
+	RestoreEdges(context.Context, *EdgeBatch) (*emptypb.Empty, error)
 	//mustEmbedUnimplementedLinkGraphServer()
 }
 
@@ -156,9 +427,24 @@ type UnimplementedLinkGraphServer struct {
 func (UnimplementedLinkGraphServer) UpsertLink(context.Context, *Link) (*Link, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpsertLink not implemented")
 }
+func (UnimplementedLinkGraphServer) UpsertLinks(LinkGraph_UpsertLinksServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpsertLinks not implemented")
+}
+func (UnimplementedLinkGraphServer) FindLink(context.Context, *LinkID) (*Link, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindLink not implemented")
+}
+func (UnimplementedLinkGraphServer) FindLinkByURL(context.Context, *LinkURL) (*Link, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindLinkByURL not implemented")
+}
+func (UnimplementedLinkGraphServer) DeleteLink(context.Context, *LinkID) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLink not implemented")
+}
 func (UnimplementedLinkGraphServer) UpsertEdge(context.Context, *Edge) (*Edge, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpsertEdge not implemented")
 }
+func (UnimplementedLinkGraphServer) UpsertEdges(LinkGraph_UpsertEdgesServer) error {
+	return status.Errorf(codes.Unimplemented, "method UpsertEdges not implemented")
+}
 func (UnimplementedLinkGraphServer) Links(*Range, LinkGraph_LinksServer) error {
 	return status.Errorf(codes.Unimplemented, "method Links not implemented")
 }
@@ -168,6 +454,30 @@ func (UnimplementedLinkGraphServer) Edges(*Range, LinkGraph_EdgesServer) error {
 func (UnimplementedLinkGraphServer) RemoveStaleEdges(context.Context, *RemoveStaleEdgesQuery) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveStaleEdges not implemented")
 }
+func (UnimplementedLinkGraphServer) EdgesTo(*LinkID, LinkGraph_EdgesToServer) error {
+	return status.Errorf(codes.Unimplemented, "method EdgesTo not implemented")
+}
+func (UnimplementedLinkGraphServer) InboundDegree(context.Context, *LinkID) (*InboundDegreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InboundDegree not implemented")
+}
+func (UnimplementedLinkGraphServer) WatchLinks(*emptypb.Empty, LinkGraph_WatchLinksServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLinks not implemented")
+}
+func (UnimplementedLinkGraphServer) UpsertHost(context.Context, *Host) (*Host, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertHost not implemented")
+}
+func (UnimplementedLinkGraphServer) FindHost(context.Context, *HostName) (*Host, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindHost not implemented")
+}
+func (UnimplementedLinkGraphServer) RecordHostFetch(context.Context, *HostFetch) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordHostFetch not implemented")
+}
+func (UnimplementedLinkGraphServer) RestoreLinks(context.Context, *LinkBatch) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreLinks not implemented")
+}
+func (UnimplementedLinkGraphServer) RestoreEdges(context.Context, *EdgeBatch) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEdges not implemented")
+}
 func (UnimplementedLinkGraphServer) mustEmbedUnimplementedLinkGraphServer() {}
 
 // UnsafeLinkGraphServer may be embedded to opt out of forward compatibility for this service.
@@ -199,6 +509,86 @@ func _LinkGraph_UpsertLink_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LinkGraph_UpsertLinks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LinkGraphServer).UpsertLinks(&linkGraphUpsertLinksServer{stream})
+}
+
+type LinkGraph_UpsertLinksServer interface {
+	SendAndClose(*LinkBatch) error
+	Recv() (*Link, error)
+	grpc.ServerStream
+}
+
+type linkGraphUpsertLinksServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphUpsertLinksServer) SendAndClose(m *LinkBatch) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertLinksServer) Recv() (*Link, error) {
+	m := new(Link)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LinkGraph_FindLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).FindLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/FindLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).FindLink(ctx, req.(*LinkID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_FindLinkByURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkURL)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).FindLinkByURL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/FindLinkByURL",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).FindLinkByURL(ctx, req.(*LinkURL))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_DeleteLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).DeleteLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/DeleteLink",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).DeleteLink(ctx, req.(*LinkID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LinkGraph_UpsertEdge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Edge)
 	if err := dec(in); err != nil {
@@ -217,6 +607,32 @@ func _LinkGraph_UpsertEdge_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LinkGraph_UpsertEdges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LinkGraphServer).UpsertEdges(&linkGraphUpsertEdgesServer{stream})
+}
+
+type LinkGraph_UpsertEdgesServer interface {
+	SendAndClose(*EdgeBatch) error
+	Recv() (*Edge, error)
+	grpc.ServerStream
+}
+
+type linkGraphUpsertEdgesServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphUpsertEdgesServer) SendAndClose(m *EdgeBatch) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *linkGraphUpsertEdgesServer) Recv() (*Edge, error) {
+	m := new(Edge)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _LinkGraph_Links_Handler(srv interface{}, stream grpc.ServerStream) error {
 	m := new(Range)
 	if err := stream.RecvMsg(m); err != nil {
@@ -277,6 +693,156 @@ func _LinkGraph_RemoveStaleEdges_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LinkGraph_EdgesTo_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(LinkID)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LinkGraphServer).EdgesTo(m, &linkGraphEdgesToServer{stream})
+}
+
+type LinkGraph_EdgesToServer interface {
+	Send(*Edge) error
+	grpc.ServerStream
+}
+
+type linkGraphEdgesToServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphEdgesToServer) Send(m *Edge) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LinkGraph_InboundDegree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).InboundDegree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/InboundDegree",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).InboundDegree(ctx, req.(*LinkID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_WatchLinks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LinkGraphServer).WatchLinks(m, &linkGraphWatchLinksServer{stream})
+}
+
+type LinkGraph_WatchLinksServer interface {
+	Send(*LinkEvent) error
+	grpc.ServerStream
+}
+
+type linkGraphWatchLinksServer struct {
+	grpc.ServerStream
+}
+
+func (x *linkGraphWatchLinksServer) Send(m *LinkEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LinkGraph_UpsertHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Host)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).UpsertHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/UpsertHost",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).UpsertHost(ctx, req.(*Host))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_FindHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HostName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).FindHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/FindHost",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).FindHost(ctx, req.(*HostName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_RecordHostFetch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HostFetch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).RecordHostFetch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/RecordHostFetch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).RecordHostFetch(ctx, req.(*HostFetch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_RestoreLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LinkBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).RestoreLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/RestoreLinks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).RestoreLinks(ctx, req.(*LinkBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LinkGraph_RestoreEdges_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EdgeBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LinkGraphServer).RestoreEdges(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.LinkGraph/RestoreEdges",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LinkGraphServer).RestoreEdges(ctx, req.(*EdgeBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LinkGraph_ServiceDesc is the grpc.ServiceDesc for LinkGraph service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -288,6 +854,18 @@ var LinkGraph_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpsertLink",
 			Handler:    _LinkGraph_UpsertLink_Handler,
 		},
+		{
+			MethodName: "FindLink",
+			Handler:    _LinkGraph_FindLink_Handler,
+		},
+		{
+			MethodName: "FindLinkByURL",
+			Handler:    _LinkGraph_FindLinkByURL_Handler,
+		},
+		{
+			MethodName: "DeleteLink",
+			Handler:    _LinkGraph_DeleteLink_Handler,
+		},
 		{
 			MethodName: "UpsertEdge",
 			Handler:    _LinkGraph_UpsertEdge_Handler,
@@ -296,8 +874,42 @@ var LinkGraph_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveStaleEdges",
 			Handler:    _LinkGraph_RemoveStaleEdges_Handler,
 		},
+		{
+			MethodName: "InboundDegree",
+			Handler:    _LinkGraph_InboundDegree_Handler,
+		},
+		{
+			MethodName: "UpsertHost",
+			Handler:    _LinkGraph_UpsertHost_Handler,
+		},
+		{
+			MethodName: "FindHost",
+			Handler:    _LinkGraph_FindHost_Handler,
+		},
+		{
+			MethodName: "RecordHostFetch",
+			Handler:    _LinkGraph_RecordHostFetch_Handler,
+		},
+		{
+			MethodName: "RestoreLinks",
+			Handler:    _LinkGraph_RestoreLinks_Handler,
+		},
+		{
+			MethodName: "RestoreEdges",
+			Handler:    _LinkGraph_RestoreEdges_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "UpsertLinks",
+			Handler:       _LinkGraph_UpsertLinks_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "UpsertEdges",
+			Handler:       _LinkGraph_UpsertEdges_Handler,
+			ClientStreams: true,
+		},
 		{
 			StreamName:    "Links",
 			Handler:       _LinkGraph_Links_Handler,
@@ -308,6 +920,16 @@ var LinkGraph_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _LinkGraph_Edges_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "EdgesTo",
+			Handler:       _LinkGraph_EdgesTo_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchLinks",
+			Handler:       _LinkGraph_WatchLinks_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api.proto",
 }
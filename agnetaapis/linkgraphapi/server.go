@@ -8,6 +8,10 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
 	"time"
 )
 
@@ -25,39 +29,86 @@ func NewLinkGraphServer(g graph.Graph) *LinkGraphServer {
 }
 
 // UpsertLink inserts or updates a link.
-func (s *LinkGraphServer) UpsertLink(_ context.Context, req *generated.Link) (*generated.Link, error) {
-	var (
-		err  error
-		link = graph.Link{
-			ID:  uuidFromBytes(req.Uuid),
-			URL: req.Url,
+func (s *LinkGraphServer) UpsertLink(ctx context.Context, req *generated.Link) (*generated.Link, error) {
+	link, err := linkFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.g.UpsertLink(ctx, link); err != nil {
+		return nil, toRPCError(err)
+	}
+
+	return linkToProto(link), nil
+}
+
+// UpsertLinks inserts or updates a stream of links using a single batch.
+func (s *LinkGraphServer) UpsertLinks(stream generated.LinkGraph_UpsertLinksServer) error {
+	var links []*graph.Link
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
 		}
-	)
 
-	if link.RetrievedAt, err = ptypes.Timestamp(req.RetrievedAt); err != nil {
-		return nil, err
+		link, err := linkFromProto(req)
+		if err != nil {
+			return err
+		}
+		links = append(links, link)
 	}
 
-	if err = s.g.UpsertLink(&link); err != nil {
-		return nil, err
+	if err := s.g.UpsertLinks(stream.Context(), links); err != nil {
+		return toRPCError(err)
 	}
 
-	req.RetrievedAt = timeToProto(link.RetrievedAt)
-	req.Url = link.URL
-	req.Uuid = link.ID[:]
-	return req, nil
+	res := &generated.LinkBatch{Links: make([]*generated.Link, len(links))}
+	for i, link := range links {
+		res.Links[i] = linkToProto(link)
+	}
+	return stream.SendAndClose(res)
+}
+
+// FindLink looks up a link by its ID.
+func (s *LinkGraphServer) FindLink(ctx context.Context, req *generated.LinkID) (*generated.Link, error) {
+	link, err := s.g.FindLink(ctx, uuidFromBytes(req.Uuid))
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+	return linkToProto(link), nil
+}
+
+// FindLinkByURL looks up a link by its URL.
+func (s *LinkGraphServer) FindLinkByURL(ctx context.Context, req *generated.LinkURL) (*generated.Link, error) {
+	link, err := s.g.FindLinkByURL(ctx, req.Url)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+	return linkToProto(link), nil
+}
+
+// DeleteLink removes a link and all edges that originate from or point to it.
+func (s *LinkGraphServer) DeleteLink(ctx context.Context, req *generated.LinkID) (*empty.Empty, error) {
+	if err := s.g.DeleteLink(ctx, uuidFromBytes(req.Uuid)); err != nil {
+		return nil, toRPCError(err)
+	}
+	return new(empty.Empty), nil
 }
 
 // UpsertEdge inserts or updates an edge.
-func (s *LinkGraphServer) UpsertEdge(_ context.Context, req *generated.Edge) (*generated.Edge, error) {
+func (s *LinkGraphServer) UpsertEdge(ctx context.Context, req *generated.Edge) (*generated.Edge, error) {
 	edge := graph.Edge{
-		ID:  uuidFromBytes(req.Uuid),
-		Src: uuidFromBytes(req.SrcUuid),
-		Dst: uuidFromBytes(req.DstUuid),
+		ID:         uuidFromBytes(req.Uuid),
+		Src:        uuidFromBytes(req.SrcUuid),
+		Dst:        uuidFromBytes(req.DstUuid),
+		AnchorText: req.AnchorText,
+		Rel:        req.Rel,
 	}
 
-	if err := s.g.UpsertEdge(&edge); err != nil {
-		return nil, err
+	if err := s.g.UpsertEdge(ctx, &edge); err != nil {
+		return nil, toRPCError(err)
 	}
 
 	req.Uuid = edge.ID[:]
@@ -67,6 +118,37 @@ func (s *LinkGraphServer) UpsertEdge(_ context.Context, req *generated.Edge) (*g
 	return req, nil
 }
 
+// UpsertEdges inserts or updates a stream of edges using a single batch.
+func (s *LinkGraphServer) UpsertEdges(stream generated.LinkGraph_UpsertEdgesServer) error {
+	var edges []*graph.Edge
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		edges = append(edges, &graph.Edge{
+			ID:         uuidFromBytes(req.Uuid),
+			Src:        uuidFromBytes(req.SrcUuid),
+			Dst:        uuidFromBytes(req.DstUuid),
+			AnchorText: req.AnchorText,
+			Rel:        req.Rel,
+		})
+	}
+
+	if err := s.g.UpsertEdges(stream.Context(), edges); err != nil {
+		return toRPCError(err)
+	}
+
+	res := &generated.EdgeBatch{Edges: make([]*generated.Edge, len(edges))}
+	for i, edge := range edges {
+		res.Edges[i] = edgeToProto(edge)
+	}
+	return stream.SendAndClose(res)
+}
+
 // Links streams the set of links whose IDs belong to the specified partition
 // range and were accessed before the specified timestamp.
 func (s *LinkGraphServer) Links(idRange *generated.Range, w generated.LinkGraph_LinksServer) error {
@@ -84,20 +166,14 @@ func (s *LinkGraphServer) Links(idRange *generated.Range, w generated.LinkGraph_
 		return err
 	}
 
-	it, err := s.g.Links(fromID, toID, accessedBefore)
+	it, err := s.g.Links(w.Context(), fromID, toID, accessedBefore)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = it.Close() }()
 
 	for it.Next() {
-		link := it.Link()
-		msg := &generated.Link{
-			Uuid:        link.ID[:],
-			Url:         link.URL,
-			RetrievedAt: timeToProto(link.RetrievedAt),
-		}
-		if err := w.Send(msg); err != nil {
+		if err := w.Send(linkToProto(it.Link())); err != nil {
 			_ = it.Close()
 			return err
 		}
@@ -127,21 +203,41 @@ func (s *LinkGraphServer) Edges(idRange *generated.Range, w generated.LinkGraph_
 		return err
 	}
 
-	it, err := s.g.Edges(fromID, toID, updatedBefore)
+	it, err := s.g.Edges(w.Context(), fromID, toID, updatedBefore)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = it.Close() }()
 
 	for it.Next() {
-		edge := it.Edge()
-		msg := &generated.Edge{
-			Uuid:      edge.ID[:],
-			SrcUuid:   edge.Src[:],
-			DstUuid:   edge.Dst[:],
-			UpdatedAt: timeToProto(edge.UpdatedAt),
+		if err := w.Send(edgeToProto(it.Edge())); err != nil {
+			_ = it.Close()
+			return err
 		}
-		if err := w.Send(msg); err != nil {
+	}
+
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	return it.Close()
+}
+
+// EdgesTo streams the set of edges that point to the specified link.
+func (s *LinkGraphServer) EdgesTo(req *generated.LinkID, w generated.LinkGraph_EdgesToServer) error {
+	dstID, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return err
+	}
+
+	it, err := s.g.EdgesTo(w.Context(), dstID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = it.Close() }()
+
+	for it.Next() {
+		if err := w.Send(edgeToProto(it.Edge())); err != nil {
 			_ = it.Close()
 			return err
 		}
@@ -154,15 +250,143 @@ func (s *LinkGraphServer) Edges(idRange *generated.Range, w generated.LinkGraph_
 	return it.Close()
 }
 
+// InboundDegree returns the number of edges that point to the specified link.
+func (s *LinkGraphServer) InboundDegree(ctx context.Context, req *generated.LinkID) (*generated.InboundDegreeResponse, error) {
+	dstID, err := uuid.FromBytes(req.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	degree, err := s.g.InboundDegree(ctx, dstID)
+	if err != nil {
+		return nil, err
+	}
+	return &generated.InboundDegreeResponse{Count: uint64(degree)}, nil
+}
+
+// WatchLinks streams an event for each link that is created, updated or
+// deleted while the stream is open.
+func (s *LinkGraphServer) WatchLinks(_ *empty.Empty, w generated.LinkGraph_WatchLinksServer) error {
+	watcher, err := s.g.WatchLinks(w.Context())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = watcher.Close() }()
+
+	for {
+		select {
+		case <-w.Context().Done():
+			return nil
+		case ev, ok := <-watcher.Events():
+			if !ok {
+				return watcher.Error()
+			}
+
+			// The graph event types share the same values as the proto enum.
+			msg := &generated.LinkEvent{
+				Type: generated.LinkEvent_Type(ev.Type),
+				Link: linkToProto(ev.Link),
+			}
+			if err := w.Send(msg); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// UpsertHost inserts a host or updates the crawl settings of a host.
+func (s *LinkGraphServer) UpsertHost(ctx context.Context, req *generated.Host) (*generated.Host, error) {
+	host, err := hostFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.g.UpsertHost(ctx, host); err != nil {
+		return nil, toRPCError(err)
+	}
+	return hostToProto(host), nil
+}
+
+// FindHost looks up a host by its name.
+func (s *LinkGraphServer) FindHost(ctx context.Context, req *generated.HostName) (*generated.Host, error) {
+	host, err := s.g.FindHost(ctx, req.Name)
+	if err != nil {
+		return nil, toRPCError(err)
+	}
+	return hostToProto(host), nil
+}
+
+// RecordHostFetch records a fetch attempt for a host.
+func (s *LinkGraphServer) RecordHostFetch(ctx context.Context, req *generated.HostFetch) (*empty.Empty, error) {
+	fetchedAt, err := ptypes.Timestamp(req.FetchedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.g.RecordHostFetch(ctx, req.Name, fetchedAt, req.Failed); err != nil {
+		return nil, toRPCError(err)
+	}
+	return new(empty.Empty), nil
+}
+
+// RestoreLinks inserts or replaces a batch of links keeping their IDs. It
+// fails with an Unimplemented error if the backing graph does not implement
+// graph.Restorer.
+func (s *LinkGraphServer) RestoreLinks(ctx context.Context, req *generated.LinkBatch) (*empty.Empty, error) {
+	r, ok := s.g.(graph.Restorer)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "link graph does not support restoring links")
+	}
+
+	links := make([]*graph.Link, len(req.Links))
+	for i, msg := range req.Links {
+		link, err := linkFromProto(msg)
+		if err != nil {
+			return nil, err
+		}
+		links[i] = link
+	}
+
+	if err := r.RestoreLinks(ctx, links); err != nil {
+		return nil, toRPCError(err)
+	}
+	return new(empty.Empty), nil
+}
+
+// RestoreEdges inserts or replaces a batch of edges keeping their IDs and
+// update timestamps. It fails with an Unimplemented error if the backing
+// graph does not implement graph.Restorer.
+func (s *LinkGraphServer) RestoreEdges(ctx context.Context, req *generated.EdgeBatch) (*empty.Empty, error) {
+	r, ok := s.g.(graph.Restorer)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "link graph does not support restoring edges")
+	}
+
+	edges := make([]*graph.Edge, len(req.Edges))
+	for i, msg := range req.Edges {
+		edge, err := edgeFromProto(msg)
+		if err != nil {
+			return nil, err
+		}
+		edges[i] = edge
+	}
+
+	if err := r.RestoreEdges(ctx, edges); err != nil {
+		return nil, toRPCError(err)
+	}
+	return new(empty.Empty), nil
+}
+
 // RemoveStaleEdges removes any edge that originates from the specified
 // link ID and was updated before the specified timestamp.
-func (s *LinkGraphServer) RemoveStaleEdges(_ context.Context, req *generated.RemoveStaleEdgesQuery) (*empty.Empty, error) {
+func (s *LinkGraphServer) RemoveStaleEdges(ctx context.Context, req *generated.RemoveStaleEdgesQuery) (*empty.Empty, error) {
 	updatedBefore, err := ptypes.Timestamp(req.UpdatedBefore)
 	if err != nil {
 		return nil, err
 	}
 
 	err = s.g.RemoveStaleEdges(
+		ctx,
 		uuidFromBytes(req.FromUuid),
 		updatedBefore,
 	)
@@ -170,6 +394,136 @@ func (s *LinkGraphServer) RemoveStaleEdges(_ context.Context, req *generated.Rem
 	return new(empty.Empty), err
 }
 
+// linkToProto converts a graph.Link into its protobuf representation.
+func linkToProto(link *graph.Link) *generated.Link {
+	return &generated.Link{
+		Uuid:         link.ID[:],
+		Url:          link.URL,
+		RetrievedAt:  timeToProto(link.RetrievedAt),
+		StatusCode:   int32(link.StatusCode),
+		ContentHash:  link.ContentHash,
+		Etag:         link.ETag,
+		LastModified: link.LastModified,
+		FailureCount: uint32(link.FailureCount),
+		NextCrawlAt:  timeToProto(link.NextCrawlAt),
+	}
+}
+
+// linkFromProto converts a protobuf link message into a graph.Link.
+func linkFromProto(msg *generated.Link) (*graph.Link, error) {
+	link := &graph.Link{
+		ID:           uuidFromBytes(msg.Uuid),
+		URL:          msg.Url,
+		StatusCode:   int(msg.StatusCode),
+		ContentHash:  msg.ContentHash,
+		ETag:         msg.Etag,
+		LastModified: msg.LastModified,
+		FailureCount: int(msg.FailureCount),
+	}
+
+	var err error
+	if link.RetrievedAt, err = ptypes.Timestamp(msg.RetrievedAt); err != nil {
+		return nil, err
+	}
+	if msg.NextCrawlAt != nil {
+		if link.NextCrawlAt, err = ptypes.Timestamp(msg.NextCrawlAt); err != nil {
+			return nil, err
+		}
+	}
+	return link, nil
+}
+
+// hostToProto converts a graph.Host into its protobuf representation.
+func hostToProto(host *graph.Host) *generated.Host {
+	return &generated.Host{
+		Name:            host.Name,
+		RobotsTxt:       host.RobotsTxt,
+		RobotsFetchedAt: timeToProto(host.RobotsFetchedAt),
+		CrawlDelay:      ptypes.DurationProto(host.CrawlDelay),
+		Blocked:         host.Blocked,
+		BlockReason:     host.BlockReason,
+		LastContactedAt: timeToProto(host.LastContactedAt),
+		FetchCount:      uint64(host.FetchCount),
+		ErrorCount:      uint64(host.ErrorCount),
+	}
+}
+
+// hostFromProto converts a protobuf host message into a graph.Host.
+func hostFromProto(msg *generated.Host) (*graph.Host, error) {
+	host := &graph.Host{
+		Name:        msg.Name,
+		RobotsTxt:   msg.RobotsTxt,
+		Blocked:     msg.Blocked,
+		BlockReason: msg.BlockReason,
+		FetchCount:  int(msg.FetchCount),
+		ErrorCount:  int(msg.ErrorCount),
+	}
+
+	var err error
+	if msg.RobotsFetchedAt != nil {
+		if host.RobotsFetchedAt, err = ptypes.Timestamp(msg.RobotsFetchedAt); err != nil {
+			return nil, err
+		}
+	}
+	if msg.LastContactedAt != nil {
+		if host.LastContactedAt, err = ptypes.Timestamp(msg.LastContactedAt); err != nil {
+			return nil, err
+		}
+	}
+	if msg.CrawlDelay != nil {
+		if host.CrawlDelay, err = ptypes.Duration(msg.CrawlDelay); err != nil {
+			return nil, err
+		}
+	}
+	return host, nil
+}
+
+// toRPCError maps the well-known graph errors to gRPC status errors so that
+// clients can reconstruct them.
+func toRPCError(err error) error {
+	switch {
+	case xerrors.Is(err, graph.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case xerrors.Is(err, graph.ErrLinkDeleted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case xerrors.Is(err, graph.ErrUnknownEdgeLinks):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case xerrors.Is(err, graph.ErrIDConflict):
+		return status.Error(codes.AlreadyExists, err.Error())
+	default:
+		return err
+	}
+}
+
+// edgeToProto converts a graph.Edge into its protobuf representation.
+func edgeToProto(edge *graph.Edge) *generated.Edge {
+	return &generated.Edge{
+		Uuid:       edge.ID[:],
+		SrcUuid:    edge.Src[:],
+		DstUuid:    edge.Dst[:],
+		UpdatedAt:  timeToProto(edge.UpdatedAt),
+		AnchorText: edge.AnchorText,
+		Rel:        edge.Rel,
+	}
+}
+
+// edgeFromProto converts a protobuf edge message into a graph.Edge.
+func edgeFromProto(msg *generated.Edge) (*graph.Edge, error) {
+	updatedAt, err := ptypes.Timestamp(msg.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &graph.Edge{
+		ID:         uuidFromBytes(msg.Uuid),
+		Src:        uuidFromBytes(msg.SrcUuid),
+		Dst:        uuidFromBytes(msg.DstUuid),
+		UpdatedAt:  updatedAt,
+		AnchorText: msg.AnchorText,
+		Rel:        msg.Rel,
+	}, nil
+}
+
 func uuidFromBytes(b []byte) uuid.UUID {
 	if len(b) != 16 {
 		return uuid.Nil
@@ -0,0 +1,539 @@
+package linkgraphapi
+
+import (
+	"Search_Engine/agneta/partition"
+	"Search_Engine/linkgraph/graph"
+	"bytes"
+	"context"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/xerrors"
+	"sync"
+	"time"
+)
+
+// Compile-time check for ensuring ShardedLinkGraphClient implements Graph.
+var _ graph.Graph = (*ShardedLinkGraphClient)(nil)
+
+// ShardedLinkGraphClient provides an API compatible with the graph.Graph
+// interface for accessing a link graph that is split across multiple shards.
+//
+// The UUID space is split into len(shards) contiguous partitions using
+// partition.NewFullRange and the i-th shard owns the links whose IDs belong
+// to the i-th partition together with the edges that originate from them.
+// New links are assigned an ID that is derived from their URL so that URL
+// based operations are always routed to the same shard. Links that were
+// created directly against a shard with a random ID are only visible through
+// the client if their ID happens to belong to the shard's partition.
+//
+// As shards can only store edges between links they know about, upserting an
+// edge whose destination is owned by another shard also creates a stub copy
+// of the destination link in the source shard. Stubs are never returned by
+// the client.
+type ShardedLinkGraphClient struct {
+	shards    []graph.Graph
+	partRange partition.Range
+}
+
+// NewShardedLinkGraphClient returns a new client that routes requests to the
+// provided shards. Each shard is typically a LinkGraphClient connected to the
+// server that owns the respective partition.
+func NewShardedLinkGraphClient(shards []graph.Graph) (*ShardedLinkGraphClient, error) {
+	if len(shards) == 0 {
+		return nil, xerrors.Errorf("sharded link graph client: at least one shard must be specified")
+	}
+
+	partRange, err := partition.NewFullRange(len(shards))
+	if err != nil {
+		return nil, xerrors.Errorf("sharded link graph client: %w", err)
+	}
+
+	return &ShardedLinkGraphClient{shards: shards, partRange: partRange}, nil
+}
+
+// UpsertLink creates a new link or updates an existing link.
+func (c *ShardedLinkGraphClient) UpsertLink(ctx context.Context, link *graph.Link) error {
+	lCopy := *link
+	lCopy.ID = graph.LinkIDForURL(link.URL)
+	shard, err := c.shardFor(lCopy.ID)
+	if err != nil {
+		return xerrors.Errorf("upsert link: %w", err)
+	}
+
+	if err = shard.UpsertLink(ctx, &lCopy); err != nil {
+		return err
+	}
+	*link = lCopy
+	return nil
+}
+
+// UpsertLinks creates or updates a batch of links. Links that have been
+// deleted from the graph are skipped and have their ID set to uuid.Nil.
+func (c *ShardedLinkGraphClient) UpsertLinks(ctx context.Context, links []*graph.Link) error {
+	batches := make([][]*graph.Link, len(c.shards))
+	for _, link := range links {
+		link.ID = graph.LinkIDForURL(link.URL)
+		shardIndex, err := c.partRange.PartitionForID(link.ID)
+		if err != nil {
+			return xerrors.Errorf("upsert links: %w", err)
+		}
+		batches[shardIndex] = append(batches[shardIndex], link)
+	}
+
+	for shardIndex, batch := range batches {
+		if len(batch) == 0 {
+			continue
+		}
+		if err := c.shards[shardIndex].UpsertLinks(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindLink looks up a link by its ID.
+func (c *ShardedLinkGraphClient) FindLink(ctx context.Context, id uuid.UUID) (*graph.Link, error) {
+	shard, err := c.shardFor(id)
+	if err != nil {
+		return nil, xerrors.Errorf("find link: %w", graph.ErrNotFound)
+	}
+	return shard.FindLink(ctx, id)
+}
+
+// FindLinkByURL looks up a link by its URL.
+func (c *ShardedLinkGraphClient) FindLinkByURL(ctx context.Context, url string) (*graph.Link, error) {
+	shard, err := c.shardFor(graph.LinkIDForURL(url))
+	if err != nil {
+		return nil, xerrors.Errorf("find link by URL: %w", err)
+	}
+	return shard.FindLinkByURL(ctx, url)
+}
+
+// DeleteLink removes a link together with all edges that originate from or
+// point to it and records a tombstone for the link's URL. Any stub copies of
+// the link that are kept by other shards are removed as well.
+func (c *ShardedLinkGraphClient) DeleteLink(ctx context.Context, id uuid.UUID) error {
+	shardIndex, err := c.partRange.PartitionForID(id)
+	if err != nil {
+		return xerrors.Errorf("delete link: %w", graph.ErrNotFound)
+	}
+
+	if err = c.shards[shardIndex].DeleteLink(ctx, id); err != nil {
+		return err
+	}
+
+	for i, shard := range c.shards {
+		if i == shardIndex {
+			continue
+		}
+		if err = shard.DeleteLink(ctx, id); err != nil && !xerrors.Is(err, graph.ErrNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpsertEdge creates a new edge or updates an existing edge.
+func (c *ShardedLinkGraphClient) UpsertEdge(ctx context.Context, edge *graph.Edge) error {
+	shardIndex, err := c.partRange.PartitionForID(edge.Src)
+	if err != nil {
+		return xerrors.Errorf("upsert edge: %w", graph.ErrUnknownEdgeLinks)
+	}
+
+	if err = c.ensureLinks(ctx, shardIndex, []*graph.Edge{edge}); err != nil {
+		return xerrors.Errorf("upsert edge: %w", err)
+	}
+	return c.shards[shardIndex].UpsertEdge(ctx, edge)
+}
+
+// UpsertEdges creates or updates a batch of edges.
+func (c *ShardedLinkGraphClient) UpsertEdges(ctx context.Context, edges []*graph.Edge) error {
+	batches := make([][]*graph.Edge, len(c.shards))
+	for _, edge := range edges {
+		shardIndex, err := c.partRange.PartitionForID(edge.Src)
+		if err != nil {
+			return xerrors.Errorf("upsert edges: %w", graph.ErrUnknownEdgeLinks)
+		}
+		batches[shardIndex] = append(batches[shardIndex], edge)
+	}
+
+	for shardIndex, batch := range batches {
+		if len(batch) == 0 {
+			continue
+		}
+		if err := c.ensureLinks(ctx, shardIndex, batch); err != nil {
+			return xerrors.Errorf("upsert edges: %w", err)
+		}
+		if err := c.shards[shardIndex].UpsertEdges(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureLinks creates stub copies of the edge destinations that are owned by
+// other shards in the shard with the specified index.
+func (c *ShardedLinkGraphClient) ensureLinks(ctx context.Context, shardIndex int, edges []*graph.Edge) error {
+	var (
+		shard = c.shards[shardIndex]
+		stubs []*graph.Link
+		seen  = make(map[uuid.UUID]bool)
+	)
+	for _, edge := range edges {
+		if seen[edge.Dst] {
+			continue
+		}
+		seen[edge.Dst] = true
+
+		owner, err := c.partRange.PartitionForID(edge.Dst)
+		if err != nil {
+			return graph.ErrUnknownEdgeLinks
+		} else if owner == shardIndex {
+			continue
+		}
+
+		if _, err = shard.FindLink(ctx, edge.Dst); err == nil {
+			continue
+		} else if !xerrors.Is(err, graph.ErrNotFound) {
+			return err
+		}
+
+		dst, err := c.shards[owner].FindLink(ctx, edge.Dst)
+		if err != nil {
+			if xerrors.Is(err, graph.ErrNotFound) {
+				err = graph.ErrUnknownEdgeLinks
+			}
+			return err
+		}
+		stubs = append(stubs, &graph.Link{ID: dst.ID, URL: dst.URL})
+	}
+
+	if len(stubs) == 0 {
+		return nil
+	}
+	return shard.UpsertLinks(ctx, stubs)
+}
+
+// RemoveStaleEdges removes any edge that originates from the specified link
+// ID and was updated before the specified timestamp.
+func (c *ShardedLinkGraphClient) RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time) error {
+	shard, err := c.shardFor(fromID)
+	if err != nil {
+		return xerrors.Errorf("remove stale edges: %w", err)
+	}
+	return shard.RemoveStaleEdges(ctx, fromID, updatedBefore)
+}
+
+// Links returns an iterator for the set of links whose IDs belong to the
+// [fromID, toID) range and were retrieved before the provided timestamp.
+// The range is scanned one shard at a time in partition order.
+func (c *ShardedLinkGraphClient) Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error) {
+	var scans []func() (graph.LinkIterator, error)
+	c.visitRange(fromID, toID, func(shard graph.Graph, from, to uuid.UUID) {
+		scans = append(scans, func() (graph.LinkIterator, error) {
+			return shard.Links(ctx, from, to, retrievedBefore)
+		})
+	})
+	return &shardedLinkIterator{scans: scans}, nil
+}
+
+// Edges returns an iterator for the set of edges whose source vertex IDs
+// belong to the [fromID, toID) range and were updated before the provided
+// timestamp. The range is scanned one shard at a time in partition order.
+func (c *ShardedLinkGraphClient) Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
+	var scans []func() (graph.EdgeIterator, error)
+	c.visitRange(fromID, toID, func(shard graph.Graph, from, to uuid.UUID) {
+		scans = append(scans, func() (graph.EdgeIterator, error) {
+			return shard.Edges(ctx, from, to, updatedBefore)
+		})
+	})
+	return &shardedEdgeIterator{scans: scans}, nil
+}
+
+// EdgesTo returns an iterator for the set of edges whose destination vertex
+// is the specified link ID. As edges are owned by the shard of their source
+// vertex, all shards are queried.
+func (c *ShardedLinkGraphClient) EdgesTo(ctx context.Context, dstID uuid.UUID) (graph.EdgeIterator, error) {
+	scans := make([]func() (graph.EdgeIterator, error), len(c.shards))
+	for i, shard := range c.shards {
+		shard := shard
+		scans[i] = func() (graph.EdgeIterator, error) {
+			return shard.EdgesTo(ctx, dstID)
+		}
+	}
+	return &shardedEdgeIterator{scans: scans}, nil
+}
+
+// InboundDegree returns the number of edges whose destination vertex is the
+// specified link ID.
+func (c *ShardedLinkGraphClient) InboundDegree(ctx context.Context, dstID uuid.UUID) (int, error) {
+	var total int
+	for _, shard := range c.shards {
+		degree, err := shard.InboundDegree(ctx, dstID)
+		if err != nil {
+			return 0, err
+		}
+		total += degree
+	}
+	return total, nil
+}
+
+// visitRange invokes visitFn for each shard whose partition overlaps the
+// [fromID, toID) range with the part of the range that the shard owns.
+func (c *ShardedLinkGraphClient) visitRange(fromID, toID uuid.UUID, visitFn func(shard graph.Graph, from, to uuid.UUID)) {
+	for i, shard := range c.shards {
+		// Extents are always available for valid partition indices.
+		from, to, _ := c.partRange.PartitionExtents(i)
+		if bytes.Compare(fromID[:], from[:]) > 0 {
+			from = fromID
+		}
+		if bytes.Compare(toID[:], to[:]) < 0 {
+			to = toID
+		}
+		if bytes.Compare(from[:], to[:]) < 0 {
+			visitFn(shard, from, to)
+		}
+	}
+}
+
+// WatchLinks returns a watcher that receives an event for each link that is
+// created, updated or deleted after the call returns. Events for stub links
+// are not reported. Events for links owned by the same shard are delivered in
+// order but events from different shards may be interleaved arbitrarily.
+func (c *ShardedLinkGraphClient) WatchLinks(ctx context.Context) (graph.LinkWatcher, error) {
+	w := &shardedLinkWatcher{
+		events: make(chan graph.LinkEvent),
+		doneCh: make(chan struct{}),
+	}
+	for _, shard := range c.shards {
+		shardWatcher, err := shard.WatchLinks(ctx)
+		if err != nil {
+			_ = w.Close()
+			return nil, xerrors.Errorf("watch links: %w", err)
+		}
+		w.watchers = append(w.watchers, shardWatcher)
+	}
+
+	w.wg.Add(len(w.watchers))
+	for i, shardWatcher := range w.watchers {
+		go w.relayEvents(c.partRange, i, shardWatcher)
+	}
+	go func() {
+		w.wg.Wait()
+		close(w.events)
+	}()
+	return w, nil
+}
+
+// UpsertHost creates a new host or updates an existing host.
+func (c *ShardedLinkGraphClient) UpsertHost(ctx context.Context, host *graph.Host) error {
+	shard, err := c.shardFor(hostKey(host.Name))
+	if err != nil {
+		return xerrors.Errorf("upsert host: %w", err)
+	}
+	return shard.UpsertHost(ctx, host)
+}
+
+// FindHost looks up a host by its name.
+func (c *ShardedLinkGraphClient) FindHost(ctx context.Context, name string) (*graph.Host, error) {
+	shard, err := c.shardFor(hostKey(name))
+	if err != nil {
+		return nil, xerrors.Errorf("find host: %w", err)
+	}
+	return shard.FindHost(ctx, name)
+}
+
+// RecordHostFetch records a fetch attempt for the specified host.
+func (c *ShardedLinkGraphClient) RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error {
+	shard, err := c.shardFor(hostKey(name))
+	if err != nil {
+		return xerrors.Errorf("record host fetch: %w", err)
+	}
+	return shard.RecordHostFetch(ctx, name, fetchedAt, failed)
+}
+
+// shardFor returns the shard whose partition contains id.
+func (c *ShardedLinkGraphClient) shardFor(id uuid.UUID) (graph.Graph, error) {
+	shardIndex, err := c.partRange.PartitionForID(id)
+	if err != nil {
+		return nil, err
+	}
+	return c.shards[shardIndex], nil
+}
+
+// hostKey maps a host name to the UUID that determines the shard that owns
+// the host.
+func hostKey(name string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceDNS, []byte(name))
+}
+
+// shardedLinkIterator chains the link iterators returned by a list of
+// per-shard scans.
+type shardedLinkIterator struct {
+	scans   []func() (graph.LinkIterator, error)
+	cur     graph.LinkIterator
+	lastErr error
+}
+
+// Next advances the iterator, moving on to the next shard once the current
+// one has been exhausted.
+func (it *shardedLinkIterator) Next() bool {
+	for it.lastErr == nil {
+		if it.cur != nil {
+			if it.cur.Next() {
+				return true
+			}
+			it.lastErr = closeShardIterator(it.cur)
+			it.cur = nil
+			continue
+		}
+
+		if len(it.scans) == 0 {
+			return false
+		}
+		it.cur, it.lastErr = it.scans[0]()
+		it.scans = it.scans[1:]
+	}
+	return false
+}
+
+// Error returns the last error encountered by the iterator.
+func (it *shardedLinkIterator) Error() error { return it.lastErr }
+
+// Link returns the currently fetched link object.
+func (it *shardedLinkIterator) Link() *graph.Link { return it.cur.Link() }
+
+// Close releases any resources associated with an iterator.
+func (it *shardedLinkIterator) Close() error {
+	if it.cur == nil {
+		return nil
+	}
+	err := it.cur.Close()
+	it.cur = nil
+	return err
+}
+
+// shardedEdgeIterator chains the edge iterators returned by a list of
+// per-shard scans.
+type shardedEdgeIterator struct {
+	scans   []func() (graph.EdgeIterator, error)
+	cur     graph.EdgeIterator
+	lastErr error
+}
+
+// Next advances the iterator, moving on to the next shard once the current
+// one has been exhausted.
+func (it *shardedEdgeIterator) Next() bool {
+	for it.lastErr == nil {
+		if it.cur != nil {
+			if it.cur.Next() {
+				return true
+			}
+			it.lastErr = closeShardIterator(it.cur)
+			it.cur = nil
+			continue
+		}
+
+		if len(it.scans) == 0 {
+			return false
+		}
+		it.cur, it.lastErr = it.scans[0]()
+		it.scans = it.scans[1:]
+	}
+	return false
+}
+
+// Error returns the last error encountered by the iterator.
+func (it *shardedEdgeIterator) Error() error { return it.lastErr }
+
+// Edge returns the currently fetched edge object.
+func (it *shardedEdgeIterator) Edge() *graph.Edge { return it.cur.Edge() }
+
+// Close releases any resources associated with an iterator.
+func (it *shardedEdgeIterator) Close() error {
+	if it.cur == nil {
+		return nil
+	}
+	err := it.cur.Close()
+	it.cur = nil
+	return err
+}
+
+// closeShardIterator closes an exhausted shard iterator and returns the first
+// error that it encountered.
+func closeShardIterator(it graph.Iterator) error {
+	var err error
+	if iterErr := it.Error(); iterErr != nil {
+		err = multierror.Append(err, iterErr)
+	}
+	if closeErr := it.Close(); closeErr != nil {
+		err = multierror.Append(err, closeErr)
+	}
+	return err
+}
+
+// shardedLinkWatcher is a graph.LinkWatcher implementation that merges the
+// events emitted by a set of per-shard watchers.
+type shardedLinkWatcher struct {
+	watchers []graph.LinkWatcher
+	events   chan graph.LinkEvent
+
+	wg        sync.WaitGroup
+	doneCh    chan struct{}
+	closeOnce sync.Once
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+// relayEvents forwards the events emitted by the watcher of the shard with
+// the specified index, skipping events for stub links. If the shard watcher
+// fails, all other watchers are closed as well.
+func (w *shardedLinkWatcher) relayEvents(partRange partition.Range, shardIndex int, shardWatcher graph.LinkWatcher) {
+	defer w.wg.Done()
+	for evt := range shardWatcher.Events() {
+		if owner, err := partRange.PartitionForID(evt.Link.ID); err != nil || owner != shardIndex {
+			continue
+		}
+
+		select {
+		case w.events <- evt:
+		case <-w.doneCh:
+			return
+		}
+	}
+
+	if err := shardWatcher.Error(); err != nil {
+		w.mu.Lock()
+		if w.lastErr == nil {
+			w.lastErr = err
+		}
+		w.mu.Unlock()
+		_ = w.Close()
+	}
+}
+
+// Events returns a channel that emits link change events.
+func (w *shardedLinkWatcher) Events() <-chan graph.LinkEvent { return w.events }
+
+// Error returns the error that caused the event channel to be closed. It
+// should only be called after the event channel has been closed.
+func (w *shardedLinkWatcher) Error() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastErr
+}
+
+// Close stops the watcher.
+func (w *shardedLinkWatcher) Close() error {
+	var err error
+	w.closeOnce.Do(func() {
+		close(w.doneCh)
+		for _, shardWatcher := range w.watchers {
+			if closeErr := shardWatcher.Close(); closeErr != nil {
+				err = multierror.Append(err, closeErr)
+			}
+		}
+	})
+	return err
+}
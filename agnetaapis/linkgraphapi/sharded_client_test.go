@@ -0,0 +1,120 @@
+package linkgraphapi
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/store/memory"
+	"context"
+	"github.com/google/uuid"
+	gc "gopkg.in/check.v1"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(ShardedLinkGraphClientTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type ShardedLinkGraphClientTestSuite struct {
+	graph.SuiteBase
+	shards []graph.Graph
+}
+
+func (s *ShardedLinkGraphClientTestSuite) SetUpTest(c *gc.C) {
+	s.shards = []graph.Graph{memory.NewInMemoryGraph(), memory.NewInMemoryGraph(), memory.NewInMemoryGraph()}
+	g, err := NewShardedLinkGraphClient(s.shards)
+	c.Assert(err, gc.IsNil)
+	s.SetGraph(g)
+}
+
+// TestUpsertLinkWithID is skipped as the client always assigns URL-derived IDs
+// to new links.
+func (s *ShardedLinkGraphClientTestSuite) TestUpsertLinkWithID(c *gc.C) {
+	c.Skip("link IDs are derived from URLs")
+}
+
+// TestWatchLinks overrides the base suite test as the client only preserves
+// the order of events for links owned by the same shard.
+func (s *ShardedLinkGraphClientTestSuite) TestWatchLinks(c *gc.C) {
+	g, err := NewShardedLinkGraphClient(s.shards)
+	c.Assert(err, gc.IsNil)
+
+	watcher, err := g.WatchLinks(context.Background())
+	c.Assert(err, gc.IsNil)
+
+	links := []*graph.Link{{URL: "https://example.com"}, {URL: "https://example.com/other"}}
+	c.Assert(g.UpsertLinks(context.Background(), links), gc.IsNil)
+	c.Assert(g.UpsertLink(context.Background(), &graph.Link{URL: links[0].URL}), gc.IsNil)
+	c.Assert(g.DeleteLink(context.Background(), links[1].ID), gc.IsNil)
+
+	expEvents := map[uuid.UUID][]graph.LinkEventType{
+		links[0].ID: {graph.LinkCreated, graph.LinkUpdated},
+		links[1].ID: {graph.LinkCreated, graph.LinkDeleted},
+	}
+	gotEvents := make(map[uuid.UUID][]graph.LinkEventType)
+	for i := 0; i < 4; i++ {
+		select {
+		case ev := <-watcher.Events():
+			gotEvents[ev.Link.ID] = append(gotEvents[ev.Link.ID], ev.Type)
+		case <-time.After(5 * time.Second):
+			c.Fatal("timed out waiting for link events")
+		}
+	}
+	c.Assert(gotEvents, gc.DeepEquals, expEvents)
+
+	c.Assert(watcher.Close(), gc.IsNil)
+	for range watcher.Events() {
+	}
+	c.Assert(watcher.Error(), gc.IsNil)
+}
+
+func (s *ShardedLinkGraphClientTestSuite) TestCrossShardEdge(c *gc.C) {
+	g, err := NewShardedLinkGraphClient(s.shards)
+	c.Assert(err, gc.IsNil)
+
+	// Find two URLs whose links are owned by different shards.
+	var links []*graph.Link
+	owners := make(map[int]bool)
+	for i := 0; len(links) < 2; i++ {
+		link := &graph.Link{URL: "https://example.com/" + string(rune('a'+i))}
+		owner, err := g.partRange.PartitionForID(graph.LinkIDForURL(link.URL))
+		c.Assert(err, gc.IsNil)
+		if owners[owner] {
+			continue
+		}
+		owners[owner] = true
+		links = append(links, link)
+	}
+	c.Assert(g.UpsertLinks(context.Background(), links), gc.IsNil)
+
+	edge := &graph.Edge{Src: links[0].ID, Dst: links[1].ID}
+	c.Assert(g.UpsertEdge(context.Background(), edge), gc.IsNil)
+
+	// The edge lives in the source shard which also keeps a stub of the
+	// destination link that is not visible through the client.
+	srcShard, err := g.shardFor(edge.Src)
+	c.Assert(err, gc.IsNil)
+	_, err = srcShard.FindLink(context.Background(), edge.Dst)
+	c.Assert(err, gc.IsNil)
+
+	it, err := g.Links(context.Background(), uuid.Nil, uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff"), time.Now())
+	c.Assert(err, gc.IsNil)
+	var count int
+	for it.Next() {
+		count++
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(count, gc.Equals, 2)
+
+	degree, err := g.InboundDegree(context.Background(), edge.Dst)
+	c.Assert(err, gc.IsNil)
+	c.Assert(degree, gc.Equals, 1)
+
+	// Deleting the destination also removes the stub and the edge.
+	c.Assert(g.DeleteLink(context.Background(), edge.Dst), gc.IsNil)
+	_, err = srcShard.FindLink(context.Background(), edge.Dst)
+	c.Assert(err, gc.NotNil)
+	degree, err = g.InboundDegree(context.Background(), edge.Dst)
+	c.Assert(err, gc.IsNil)
+	c.Assert(degree, gc.Equals, 0)
+}
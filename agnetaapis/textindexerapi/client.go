@@ -7,32 +7,35 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"io"
+	"time"
 )
 
 // TextIndexerClient provides an API compatible with the index.Indexer interface
 // for accessing text indexer instances exposed by a remote gRPC server.
 type TextIndexerClient struct {
-	ctx context.Context
 	cli generated.TextIndexerClient
 }
 
 // NewTextIndexerClient returns a new client that implements a subset of the index.Indexer interface by
 // delegating methods to an indexer instance exposed by a remote gRPC server.
-func NewTextIndexerClient(ctx context.Context, rpcClient generated.TextIndexerClient) *TextIndexerClient {
-	return &TextIndexerClient{ctx: ctx, cli: rpcClient}
+func NewTextIndexerClient(rpcClient generated.TextIndexerClient) *TextIndexerClient {
+	return &TextIndexerClient{cli: rpcClient}
 }
 
 // Index inserts a new document into the index or updates the index entry
 // for an existing document
-func (c *TextIndexerClient) Index(doc *index.Document) error {
+func (c *TextIndexerClient) Index(ctx context.Context, doc *index.Document) error {
 	req := &generated.Document{
-		LinkId:  doc.LinkID[:],
-		Url:     doc.URL,
-		Title:   doc.Title,
-		Content: doc.Content,
+		LinkId:     doc.LinkID[:],
+		Url:        doc.URL,
+		Title:      doc.Title,
+		Content:    doc.Content,
+		AnchorText: doc.AnchorText,
 	}
-	res, err := c.cli.Index(c.ctx, req)
+	res, err := c.cli.Index(ctx, req)
 	if err != nil {
 		return err
 	}
@@ -42,24 +45,141 @@ func (c *TextIndexerClient) Index(doc *index.Document) error {
 	return nil
 }
 
+// IndexBatch inserts or updates a batch of documents by streaming them to the
+// remote server. If some of the documents cannot be indexed, an
+// *index.BatchError describing the failures is returned.
+func (c *TextIndexerClient) IndexBatch(ctx context.Context, docs []*index.Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	stream, err := c.cli.IndexBatch(ctx)
+	if err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		req := &generated.Document{
+			LinkId:     doc.LinkID[:],
+			Url:        doc.URL,
+			Title:      doc.Title,
+			Content:    doc.Content,
+			AnchorText: doc.AnchorText,
+		}
+		if err = stream.Send(req); err != nil {
+			// The actual error is reported by CloseAndRecv.
+			break
+		}
+	}
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	} else if len(res.Results) != len(docs) {
+		return xerrors.Errorf("index batch: expected %d results; got %d", len(docs), len(res.Results))
+	}
+
+	batchErr := &index.BatchError{Errors: make(map[int]error)}
+	for pos, result := range res.Results {
+		switch {
+		case result.Error == index.ErrMissingLinkID.Error():
+			batchErr.Errors[pos] = index.ErrMissingLinkID
+		case result.Error != "":
+			batchErr.Errors[pos] = xerrors.New(result.Error)
+		default:
+			docs[pos].IndexedAt = result.IndexedAt.AsTime()
+		}
+	}
+	if len(batchErr.Errors) != 0 {
+		return xerrors.Errorf("index batch: %w", batchErr)
+	}
+	return nil
+}
+
+// Suggest returns up to n indexed terms that are spelled similarly to term.
+func (c *TextIndexerClient) Suggest(ctx context.Context, term string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	req := &generated.SuggestRequest{Term: term, Count: uint32(n)}
+	res, err := c.cli.Suggest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Terms, nil
+}
+
+// Complete returns up to n distinct titles of indexed documents that start
+// with prefix.
+func (c *TextIndexerClient) Complete(ctx context.Context, prefix string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	req := &generated.CompleteRequest{Prefix: prefix, Count: uint32(n)}
+	res, err := c.cli.Complete(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Completions, nil
+}
+
 // UpdateScore updates the PageRank score for a document with the specified
 // link ID.
-func (c *TextIndexerClient) UpdateScore(linkID uuid.UUID, score float64) error {
+func (c *TextIndexerClient) UpdateScore(ctx context.Context, linkID uuid.UUID, score float64) error {
 	req := &generated.UpdateScoreRequest{
 		LinkId:        linkID[:],
 		PageRankScore: score,
 	}
-	_, err := c.cli.UpdateScore(c.ctx, req)
+	_, err := c.cli.UpdateScore(ctx, req)
 	return err
 }
 
+// UpdateAnchorText replaces the anchor text of the document with the
+// specified link ID.
+func (c *TextIndexerClient) UpdateAnchorText(ctx context.Context, linkID uuid.UUID, anchorText string) error {
+	req := &generated.UpdateAnchorTextRequest{
+		LinkId:     linkID[:],
+		AnchorText: anchorText,
+	}
+	if _, err := c.cli.UpdateAnchorText(ctx, req); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return xerrors.Errorf("update anchor text: %w", index.ErrNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes the document with the specified link ID from the index.
+func (c *TextIndexerClient) Delete(ctx context.Context, linkID uuid.UUID) error {
+	req := &generated.DeleteRequest{LinkId: linkID[:]}
+	if _, err := c.cli.Delete(ctx, req); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return xerrors.Errorf("delete: %w", index.ErrNotFound)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteStale removes the documents that were last indexed before the
+// specified time.
+func (c *TextIndexerClient) DeleteStale(ctx context.Context, indexedBefore time.Time) (int, error) {
+	req := &generated.DeleteStaleRequest{IndexedBefore: timeToProto(indexedBefore)}
+	res, err := c.cli.DeleteStale(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+	return int(res.DeletedCount), nil
+}
+
 // Search the index for a particular query and return back a result iterator.
-func (c *TextIndexerClient) Search(query index.Query) (index.Iterator, error) {
-	ctx, cancelFn := context.WithCancel(c.ctx)
+func (c *TextIndexerClient) Search(ctx context.Context, query index.Query) (index.Iterator, error) {
+	ctx, cancelFn := context.WithCancel(ctx)
 	req := &generated.Query{
 		Type:       generated.Query_Type(query.Type),
 		Expression: query.Expression,
 		Offset:     query.Offset,
+		Root:       queryNodeToProto(query.Root),
+		Filter:     filterToProto(query.Filter),
+		Facets:     query.Facets,
 	}
 	stream, err := c.cli.Search(ctx, req)
 	if err != nil {
@@ -75,16 +195,29 @@ func (c *TextIndexerClient) Search(query index.Query) (index.Iterator, error) {
 		cancelFn()
 		return nil, xerrors.Errorf("expected server to report the result count before sending any documents")
 	}
-	return &resultIterator{
+	it := &resultIterator{
 		total:    res.GetDocCount(),
 		stream:   stream,
 		cancelFn: cancelFn,
-	}, nil
+	}
+	// Read facet counts
+	if query.Facets {
+		if res, err = stream.Recv(); err != nil {
+			cancelFn()
+			return nil, err
+		} else if res.GetFacets() == nil {
+			cancelFn()
+			return nil, xerrors.Errorf("expected server to report the facet counts before sending any documents")
+		}
+		it.facets = facetsFromProto(res.GetFacets())
+	}
+	return it, nil
 
 }
 
 type resultIterator struct {
 	total   uint64
+	facets  *index.Facets
 	stream  generated.TextIndexer_SearchClient
 	lastErr error
 	next    *index.Document
@@ -124,11 +257,12 @@ func (r *resultIterator) Next() bool {
 	}
 
 	r.next = &index.Document{
-		LinkID:    linkID,
-		URL:       resDoc.Url,
-		Title:     resDoc.Title,
-		Content:   resDoc.Content,
-		IndexedAt: t,
+		LinkID:     linkID,
+		URL:        resDoc.Url,
+		Title:      resDoc.Title,
+		Content:    resDoc.Content,
+		AnchorText: resDoc.AnchorText,
+		IndexedAt:  t,
 	}
 	return true
 }
@@ -144,3 +278,7 @@ func (r *resultIterator) Document() *index.Document {
 func (r *resultIterator) TotalCount() uint64 {
 	return r.total
 }
+
+func (r *resultIterator) Facets() *index.Facets {
+	return r.facets
+}
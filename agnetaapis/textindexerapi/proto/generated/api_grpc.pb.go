@@ -27,12 +27,31 @@ type TextIndexerClient interface {
 	// and existing document.
 	Index(ctx context.Context, in *Document, opts ...grpc.CallOption) (*Document, error)
 	// Search the index for a particular query and stream the results back to
-	// the client. The first response will include the total result count while
-	// all subsequent responses will include documents from the resultset.
+	// the client. The first response will include the total result count. If
+	// the query requests facets, the second response will include the facet
+	// counts. All subsequent responses will include documents from the
+	// resultset.
 	Search(ctx context.Context, in *Query, opts ...grpc.CallOption) (TextIndexer_SearchClient, error)
 	// UpdateScore updates the PageRank score for a document with the specified
 	// link ID.
 	UpdateScore(ctx context.Context, in *UpdateScoreRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// UpdateAnchorText replaces the anchor text of the document with the
+	// specified link ID.
+	UpdateAnchorText(ctx context.Context, in *UpdateAnchorTextRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Delete removes the document with the specified link ID from the index.
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// DeleteStale removes the documents that were last indexed before the
+	// specified time.
+	DeleteStale(ctx context.Context, in *DeleteStaleRequest, opts ...grpc.CallOption) (*DeleteStaleResponse, error)
+	// IndexBatch inserts or updates the documents streamed by the client as
+	// a single batch and reports the outcome for each one of them.
+	IndexBatch(ctx context.Context, opts ...grpc.CallOption) (TextIndexer_IndexBatchClient, error)
+	// Suggest returns indexed terms that are spelled similarly to the
+	// specified term.
+	Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error)
+	// Complete returns the titles of indexed documents that start with the
+	// specified prefix.
+	Complete(ctx context.Context, in *CompleteRequest, opts ...grpc.CallOption) (*CompleteResponse, error)
 }
 
 type textIndexerClient struct {
@@ -93,6 +112,85 @@ func (c *textIndexerClient) UpdateScore(ctx context.Context, in *UpdateScoreRequ
 	return out, nil
 }
 
+func (c *textIndexerClient) UpdateAnchorText(ctx context.Context, in *UpdateAnchorTextRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/UpdateAnchorText", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) DeleteStale(ctx context.Context, in *DeleteStaleRequest, opts ...grpc.CallOption) (*DeleteStaleResponse, error) {
+	out := new(DeleteStaleResponse)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/DeleteStale", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) IndexBatch(ctx context.Context, opts ...grpc.CallOption) (TextIndexer_IndexBatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TextIndexer_ServiceDesc.Streams[1], "/proto.TextIndexer/IndexBatch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &textIndexerIndexBatchClient{stream}
+	return x, nil
+}
+
+type TextIndexer_IndexBatchClient interface {
+	Send(*Document) error
+	CloseAndRecv() (*IndexBatchResponse, error)
+	grpc.ClientStream
+}
+
+type textIndexerIndexBatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *textIndexerIndexBatchClient) Send(m *Document) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *textIndexerIndexBatchClient) CloseAndRecv() (*IndexBatchResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(IndexBatchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *textIndexerClient) Suggest(ctx context.Context, in *SuggestRequest, opts ...grpc.CallOption) (*SuggestResponse, error) {
+	out := new(SuggestResponse)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/Suggest", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *textIndexerClient) Complete(ctx context.Context, in *CompleteRequest, opts ...grpc.CallOption) (*CompleteResponse, error) {
+	out := new(CompleteResponse)
+	err := c.cc.Invoke(ctx, "/proto.TextIndexer/Complete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TextIndexerServer is the server API for TextIndexer service.
 // All implementations must embed UnimplementedTextIndexerServer
 // for forward compatibility
@@ -101,12 +199,31 @@ type TextIndexerServer interface {
 	// and existing document.
 	Index(context.Context, *Document) (*Document, error)
 	// Search the index for a particular query and stream the results back to
-	// the client. The first response will include the total result count while
-	// all subsequent responses will include documents from the resultset.
+	// the client. The first response will include the total result count. If
+	// the query requests facets, the second response will include the facet
+	// counts. All subsequent responses will include documents from the
+	// resultset.
 	Search(*Query, TextIndexer_SearchServer) error
 	// UpdateScore updates the PageRank score for a document with the specified
 	// link ID.
 	UpdateScore(context.Context, *UpdateScoreRequest) (*emptypb.Empty, error)
+	// UpdateAnchorText replaces the anchor text of the document with the
+	// specified link ID.
+	UpdateAnchorText(context.Context, *UpdateAnchorTextRequest) (*emptypb.Empty, error)
+	// Delete removes the document with the specified link ID from the index.
+	Delete(context.Context, *DeleteRequest) (*emptypb.Empty, error)
+	// DeleteStale removes the documents that were last indexed before the
+	// specified time.
+	DeleteStale(context.Context, *DeleteStaleRequest) (*DeleteStaleResponse, error)
+	// IndexBatch inserts or updates the documents streamed by the client as
+	// a single batch and reports the outcome for each one of them.
+	IndexBatch(TextIndexer_IndexBatchServer) error
+	// Suggest returns indexed terms that are spelled similarly to the
+	// specified term.
+	Suggest(context.Context, *SuggestRequest) (*SuggestResponse, error)
+	// Complete returns the titles of indexed documents that start with the
+	// specified prefix.
+	Complete(context.Context, *CompleteRequest) (*CompleteResponse, error)
 	//mustEmbedUnimplementedTextIndexerServer()
 }
 
@@ -123,6 +240,24 @@ func (UnimplementedTextIndexerServer) Search(*Query, TextIndexer_SearchServer) e
 func (UnimplementedTextIndexerServer) UpdateScore(context.Context, *UpdateScoreRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateScore not implemented")
 }
+func (UnimplementedTextIndexerServer) UpdateAnchorText(context.Context, *UpdateAnchorTextRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAnchorText not implemented")
+}
+func (UnimplementedTextIndexerServer) Delete(context.Context, *DeleteRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedTextIndexerServer) DeleteStale(context.Context, *DeleteStaleRequest) (*DeleteStaleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteStale not implemented")
+}
+func (UnimplementedTextIndexerServer) IndexBatch(TextIndexer_IndexBatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method IndexBatch not implemented")
+}
+func (UnimplementedTextIndexerServer) Suggest(context.Context, *SuggestRequest) (*SuggestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Suggest not implemented")
+}
+func (UnimplementedTextIndexerServer) Complete(context.Context, *CompleteRequest) (*CompleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Complete not implemented")
+}
 
 //func (UnimplementedTextIndexerServer) mustEmbedUnimplementedTextIndexerServer() {}
 
@@ -194,6 +329,122 @@ func _TextIndexer_UpdateScore_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TextIndexer_UpdateAnchorText_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAnchorTextRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).UpdateAnchorText(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/UpdateAnchorText",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).UpdateAnchorText(ctx, req.(*UpdateAnchorTextRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_DeleteStale_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteStaleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).DeleteStale(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/DeleteStale",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).DeleteStale(ctx, req.(*DeleteStaleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_IndexBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TextIndexerServer).IndexBatch(&textIndexerIndexBatchServer{stream})
+}
+
+type TextIndexer_IndexBatchServer interface {
+	SendAndClose(*IndexBatchResponse) error
+	Recv() (*Document, error)
+	grpc.ServerStream
+}
+
+type textIndexerIndexBatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *textIndexerIndexBatchServer) SendAndClose(m *IndexBatchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *textIndexerIndexBatchServer) Recv() (*Document, error) {
+	m := new(Document)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TextIndexer_Suggest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).Suggest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/Suggest",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).Suggest(ctx, req.(*SuggestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TextIndexer_Complete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextIndexerServer).Complete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/proto.TextIndexer/Complete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextIndexerServer).Complete(ctx, req.(*CompleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TextIndexer_ServiceDesc is the grpc.ServiceDesc for TextIndexer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -209,6 +460,26 @@ var TextIndexer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateScore",
 			Handler:    _TextIndexer_UpdateScore_Handler,
 		},
+		{
+			MethodName: "UpdateAnchorText",
+			Handler:    _TextIndexer_UpdateAnchorText_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _TextIndexer_Delete_Handler,
+		},
+		{
+			MethodName: "DeleteStale",
+			Handler:    _TextIndexer_DeleteStale_Handler,
+		},
+		{
+			MethodName: "Suggest",
+			Handler:    _TextIndexer_Suggest_Handler,
+		},
+		{
+			MethodName: "Complete",
+			Handler:    _TextIndexer_Complete_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -216,6 +487,11 @@ var TextIndexer_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _TextIndexer_Search_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "IndexBatch",
+			Handler:       _TextIndexer_IndexBatch_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "api.proto",
 }
@@ -0,0 +1,179 @@
+package textindexerapi
+
+import (
+	"Search_Engine/agnetaapis/textindexerapi/proto/generated"
+	"Search_Engine/textindexer/index"
+	"golang.org/x/xerrors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"time"
+)
+
+// queryNodeToProto converts the root node of a structured query into its
+// protobuf representation. A nil node is converted to nil.
+func queryNodeToProto(n index.QueryNode) *generated.QueryNode {
+	switch n := n.(type) {
+	case *index.MatchNode:
+		return &generated.QueryNode{Node: &generated.QueryNode_Match{
+			Match: &generated.QueryNode_Term{Field: generated.QueryNode_Field(n.Field), Text: n.Text},
+		}}
+	case *index.PhraseNode:
+		return &generated.QueryNode{Node: &generated.QueryNode_Phrase{
+			Phrase: &generated.QueryNode_Term{Field: generated.QueryNode_Field(n.Field), Text: n.Text},
+		}}
+	case *index.AndNode:
+		return &generated.QueryNode{Node: &generated.QueryNode_And{
+			And: &generated.QueryNode_Children{Nodes: queryNodesToProto(n.Children)},
+		}}
+	case *index.OrNode:
+		return &generated.QueryNode{Node: &generated.QueryNode_Or{
+			Or: &generated.QueryNode_Children{Nodes: queryNodesToProto(n.Children)},
+		}}
+	case *index.NotNode:
+		return &generated.QueryNode{Node: &generated.QueryNode_Not{
+			Not: queryNodeToProto(n.Child),
+		}}
+	case *index.DateRangeNode:
+		return &generated.QueryNode{Node: &generated.QueryNode_DateRange_{
+			DateRange: &generated.QueryNode_DateRange{From: optionalTimeToProto(n.From), To: optionalTimeToProto(n.To)},
+		}}
+	default:
+		return nil
+	}
+}
+
+func queryNodesToProto(nodes []index.QueryNode) []*generated.QueryNode {
+	out := make([]*generated.QueryNode, len(nodes))
+	for i, n := range nodes {
+		out[i] = queryNodeToProto(n)
+	}
+	return out
+}
+
+// queryNodeFromProto converts the protobuf representation of a structured
+// query node into an index.QueryNode.
+func queryNodeFromProto(n *generated.QueryNode) (index.QueryNode, error) {
+	switch node := n.GetNode().(type) {
+	case *generated.QueryNode_Match:
+		return &index.MatchNode{Field: index.QueryField(node.Match.GetField()), Text: node.Match.GetText()}, nil
+	case *generated.QueryNode_Phrase:
+		return &index.PhraseNode{Field: index.QueryField(node.Phrase.GetField()), Text: node.Phrase.GetText()}, nil
+	case *generated.QueryNode_And:
+		children, err := queryNodesFromProto(node.And.GetNodes())
+		if err != nil {
+			return nil, err
+		}
+		return &index.AndNode{Children: children}, nil
+	case *generated.QueryNode_Or:
+		children, err := queryNodesFromProto(node.Or.GetNodes())
+		if err != nil {
+			return nil, err
+		}
+		return &index.OrNode{Children: children}, nil
+	case *generated.QueryNode_Not:
+		child, err := queryNodeFromProto(node.Not)
+		if err != nil {
+			return nil, err
+		}
+		return &index.NotNode{Child: child}, nil
+	case *generated.QueryNode_DateRange_:
+		return &index.DateRangeNode{
+			From: optionalTimeFromProto(node.DateRange.GetFrom()),
+			To:   optionalTimeFromProto(node.DateRange.GetTo()),
+		}, nil
+	default:
+		return nil, xerrors.Errorf("empty query node: %w", index.ErrInvalidQuery)
+	}
+}
+
+func queryNodesFromProto(nodes []*generated.QueryNode) ([]index.QueryNode, error) {
+	if len(nodes) == 0 {
+		return nil, xerrors.Errorf("query node without children: %w", index.ErrInvalidQuery)
+	}
+
+	out := make([]index.QueryNode, len(nodes))
+	for i, n := range nodes {
+		var err error
+		if out[i], err = queryNodeFromProto(n); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// filterToProto converts a search filter into its protobuf representation.
+func filterToProto(f index.Filter) *generated.Filter {
+	return &generated.Filter{
+		Sites:       f.Sites,
+		IndexedFrom: optionalTimeToProto(f.IndexedFrom),
+		IndexedTo:   optionalTimeToProto(f.IndexedTo),
+		MinPageRank: f.MinPageRank,
+	}
+}
+
+// filterFromProto converts the protobuf representation of a search filter
+// into an index.Filter.
+func filterFromProto(f *generated.Filter) index.Filter {
+	return index.Filter{
+		Sites:       f.GetSites(),
+		IndexedFrom: optionalTimeFromProto(f.GetIndexedFrom()),
+		IndexedTo:   optionalTimeFromProto(f.GetIndexedTo()),
+		MinPageRank: f.GetMinPageRank(),
+	}
+}
+
+// facetsToProto converts the facet counts for a resultset into their
+// protobuf representation.
+func facetsToProto(f *index.Facets) *generated.Facets {
+	out := new(generated.Facets)
+	if f == nil {
+		return out
+	}
+	for _, h := range f.Hosts {
+		out.Hosts = append(out.Hosts, &generated.Facets_HostFacet{Host: h.Host, Count: h.Count})
+	}
+	for _, d := range f.IndexedAt {
+		out.IndexedAt = append(out.IndexedAt, &generated.Facets_DateFacet{
+			Name:  d.Name,
+			From:  optionalTimeToProto(d.From),
+			To:    optionalTimeToProto(d.To),
+			Count: d.Count,
+		})
+	}
+	return out
+}
+
+// facetsFromProto converts the protobuf representation of the facet counts
+// for a resultset into an index.Facets.
+func facetsFromProto(f *generated.Facets) *index.Facets {
+	out := new(index.Facets)
+	for _, h := range f.GetHosts() {
+		out.Hosts = append(out.Hosts, index.HostFacet{Host: h.GetHost(), Count: h.GetCount()})
+	}
+	for _, d := range f.GetIndexedAt() {
+		out.IndexedAt = append(out.IndexedAt, index.DateFacet{
+			Name:  d.GetName(),
+			From:  optionalTimeFromProto(d.GetFrom()),
+			To:    optionalTimeFromProto(d.GetTo()),
+			Count: d.GetCount(),
+		})
+	}
+	return out
+}
+
+// optionalTimeToProto converts t to a protobuf timestamp, mapping the zero
+// time to nil.
+func optionalTimeToProto(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// optionalTimeFromProto converts a protobuf timestamp to a time.Time, mapping
+// nil to the zero time.
+func optionalTimeFromProto(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
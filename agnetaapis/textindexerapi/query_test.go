@@ -0,0 +1,64 @@
+package textindexerapi
+
+import (
+	"Search_Engine/agnetaapis/textindexerapi/proto/generated"
+	"Search_Engine/textindexer/index"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(QueryTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type QueryTestSuite struct{}
+
+func (s *QueryTestSuite) TestQueryNodeRoundTrip(c *gc.C) {
+	root := &index.AndNode{Children: []index.QueryNode{
+		&index.OrNode{Children: []index.QueryNode{
+			&index.MatchNode{Text: "go"},
+			&index.PhraseNode{Field: index.FieldTitle, Text: "go modules"},
+		}},
+		&index.NotNode{Child: &index.MatchNode{Field: index.FieldSite, Text: "example.com"}},
+		&index.DateRangeNode{From: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+	}}
+
+	got, err := queryNodeFromProto(queryNodeToProto(root))
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.DeepEquals, root)
+}
+
+func (s *QueryTestSuite) TestInvalidQueryNode(c *gc.C) {
+	for specIndex, n := range []*generated.QueryNode{
+		{},
+		{Node: &generated.QueryNode_And{And: &generated.QueryNode_Children{}}},
+		{Node: &generated.QueryNode_Not{}},
+	} {
+		c.Logf("[spec %d]", specIndex)
+		_, err := queryNodeFromProto(n)
+		c.Assert(xerrors.Is(err, index.ErrInvalidQuery), gc.Equals, true)
+	}
+}
+
+func (s *QueryTestSuite) TestFilterRoundTrip(c *gc.C) {
+	f := index.Filter{
+		Sites:       []string{"example.com", "other.org"},
+		IndexedFrom: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		MinPageRank: 0.25,
+	}
+	c.Assert(filterFromProto(filterToProto(f)), gc.DeepEquals, f)
+	c.Assert(filterFromProto(nil), gc.DeepEquals, index.Filter{})
+}
+
+func (s *QueryTestSuite) TestFacetsRoundTrip(c *gc.C) {
+	f := &index.Facets{
+		Hosts: []index.HostFacet{{Host: "example.com", Count: 2}},
+		IndexedAt: []index.DateFacet{
+			{Name: "day", From: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), Count: 1},
+		},
+	}
+	c.Assert(facetsFromProto(facetsToProto(f)), gc.DeepEquals, f)
+	c.Assert(facetsFromProto(facetsToProto(nil)), gc.DeepEquals, new(index.Facets))
+}
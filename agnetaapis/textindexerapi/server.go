@@ -7,8 +7,12 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"io"
 	"time"
 )
 
@@ -27,12 +31,13 @@ func NewTextIndexerServer(i index.Indexer) *TextIndexerServer {
 // an existing document
 func (t *TextIndexerServer) Index(ctx context.Context, req *generated.Document) (*generated.Document, error) {
 	doc := &index.Document{
-		LinkID:  uuidFromBytes(req.LinkId),
-		URL:     req.Url,
-		Title:   req.Title,
-		Content: req.Content,
+		LinkID:     uuidFromBytes(req.LinkId),
+		URL:        req.Url,
+		Title:      req.Title,
+		Content:    req.Content,
+		AnchorText: req.AnchorText,
 	}
-	err := t.i.Index(doc)
+	err := t.i.Index(ctx, doc)
 	if err != nil {
 		return nil, err
 	}
@@ -45,8 +50,17 @@ func (t *TextIndexerServer) Search(req *generated.Query, server generated.TextIn
 		Type:       index.QueryType(req.Type),
 		Expression: req.Expression,
 		Offset:     req.Offset,
+		Filter:     filterFromProto(req.Filter),
+		Facets:     req.Facets,
 	}
-	it, err := t.i.Search(query)
+	if req.Root != nil {
+		root, err := queryNodeFromProto(req.Root)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		query.Root = root
+	}
+	it, err := t.i.Search(server.Context(), query)
 	if err != nil {
 		return err
 	}
@@ -60,17 +74,30 @@ func (t *TextIndexerServer) Search(req *generated.Query, server generated.TextIn
 		_ = it.Close()
 		return err
 	}
+	// Send back the facet counts if requested
+	if query.Facets {
+		facetsRes := &generated.QueryResult{
+			Result: &generated.QueryResult_Facets{
+				Facets: facetsToProto(it.Facets()),
+			},
+		}
+		if err = server.Send(facetsRes); err != nil {
+			_ = it.Close()
+			return err
+		}
+	}
 	// Start streaming
 	for it.Next() {
 		doc := it.Document()
 		res := generated.QueryResult{
 			Result: &generated.QueryResult_Doc{
 				Doc: &generated.Document{
-					LinkId:    doc.LinkID[:],
-					Url:       doc.URL,
-					Title:     doc.Title,
-					Content:   doc.Content,
-					IndexedAt: timeToProto(doc.IndexedAt),
+					LinkId:     doc.LinkID[:],
+					Url:        doc.URL,
+					Title:      doc.Title,
+					Content:    doc.Content,
+					AnchorText: doc.AnchorText,
+					IndexedAt:  timeToProto(doc.IndexedAt),
 				},
 			},
 		}
@@ -86,10 +113,104 @@ func (t *TextIndexerServer) Search(req *generated.Query, server generated.TextIn
 	return it.Close()
 }
 
+// Suggest returns indexed terms that are spelled similarly to the specified
+// term.
+func (t *TextIndexerServer) Suggest(ctx context.Context, req *generated.SuggestRequest) (*generated.SuggestResponse, error) {
+	terms, err := t.i.Suggest(ctx, req.Term, int(req.Count))
+	if err != nil {
+		return nil, err
+	}
+	return &generated.SuggestResponse{Terms: terms}, nil
+}
+
+// Complete returns the titles of indexed documents that start with the
+// specified prefix.
+func (t *TextIndexerServer) Complete(ctx context.Context, req *generated.CompleteRequest) (*generated.CompleteResponse, error) {
+	completions, err := t.i.Complete(ctx, req.Prefix, int(req.Count))
+	if err != nil {
+		return nil, err
+	}
+	return &generated.CompleteResponse{Completions: completions}, nil
+}
+
 // UpdateScore updates the PageRank score for a document with the specified link ID.
 func (t *TextIndexerServer) UpdateScore(ctx context.Context, req *generated.UpdateScoreRequest) (*emptypb.Empty, error) {
 	linkID := uuidFromBytes(req.LinkId)
-	return new(empty.Empty), t.i.UpdateScore(linkID, req.PageRankScore)
+	return new(empty.Empty), t.i.UpdateScore(ctx, linkID, req.PageRankScore)
+}
+
+// UpdateAnchorText replaces the anchor text of the document with the
+// specified link ID.
+func (t *TextIndexerServer) UpdateAnchorText(ctx context.Context, req *generated.UpdateAnchorTextRequest) (*emptypb.Empty, error) {
+	if err := t.i.UpdateAnchorText(ctx, uuidFromBytes(req.LinkId), req.AnchorText); err != nil {
+		return nil, toRPCError(err)
+	}
+	return new(empty.Empty), nil
+}
+
+// Delete removes the document with the specified link ID from the index.
+func (t *TextIndexerServer) Delete(ctx context.Context, req *generated.DeleteRequest) (*emptypb.Empty, error) {
+	if err := t.i.Delete(ctx, uuidFromBytes(req.LinkId)); err != nil {
+		return nil, toRPCError(err)
+	}
+	return new(empty.Empty), nil
+}
+
+// DeleteStale removes the documents that were last indexed before the
+// specified time.
+func (t *TextIndexerServer) DeleteStale(ctx context.Context, req *generated.DeleteStaleRequest) (*generated.DeleteStaleResponse, error) {
+	if req.IndexedBefore == nil {
+		return nil, status.Error(codes.InvalidArgument, "indexed_before must be specified")
+	}
+	deleted, err := t.i.DeleteStale(ctx, req.IndexedBefore.AsTime())
+	if err != nil {
+		return nil, err
+	}
+	return &generated.DeleteStaleResponse{DeletedCount: uint64(deleted)}, nil
+}
+
+// IndexBatch inserts or updates the documents streamed by the client as a
+// single batch and reports the outcome for each one of them.
+func (t *TextIndexerServer) IndexBatch(stream generated.TextIndexer_IndexBatchServer) error {
+	var docs []*index.Document
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		docs = append(docs, &index.Document{
+			LinkID:     uuidFromBytes(req.LinkId),
+			URL:        req.Url,
+			Title:      req.Title,
+			Content:    req.Content,
+			AnchorText: req.AnchorText,
+		})
+	}
+
+	var batchErr *index.BatchError
+	if err := t.i.IndexBatch(stream.Context(), docs); err != nil && !xerrors.As(err, &batchErr) {
+		return err
+	}
+	res := &generated.IndexBatchResponse{Results: make([]*generated.IndexBatchResult, len(docs))}
+	for pos, doc := range docs {
+		if batchErr != nil && batchErr.Errors[pos] != nil {
+			res.Results[pos] = &generated.IndexBatchResult{Error: batchErr.Errors[pos].Error()}
+			continue
+		}
+		res.Results[pos] = &generated.IndexBatchResult{IndexedAt: timeToProto(doc.IndexedAt)}
+	}
+	return stream.SendAndClose(res)
+}
+
+// toRPCError maps the well-known indexer errors to gRPC status errors so that
+// clients can reconstruct them.
+func toRPCError(err error) error {
+	if xerrors.Is(err, index.ErrNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	return err
 }
 
 func uuidFromBytes(id []byte) uuid.UUID {
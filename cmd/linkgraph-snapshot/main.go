@@ -0,0 +1,177 @@
+// Command linkgraph-snapshot exports the contents of a link graph to a
+// snapshot file and restores snapshots into link graph stores.
+//
+// Usage:
+//
+//	linkgraph-snapshot export -link-graph-uri URI -file PATH
+//	linkgraph-snapshot restore -link-graph-uri URI -file PATH [-batch-size N] [-checkpoint PATH]
+//
+// In-memory link graphs only live inside the process that created them. To
+// export or restore them, start that process with -link-graph-api-listen-addr
+// and point -link-graph-uri to it with a grpc://host:port URI.
+//
+// Snapshots only contain links and edges; host records are not exported. The
+// crawler rebuilds the cached robots.txt files, crawl delays and fetch
+// statistics of a restored graph as it recrawls its links, but hosts that
+// were blocked through the link graph API must be blocked again.
+package main
+
+import (
+	"Search_Engine/agnetaapis/linkgraphapi"
+	"Search_Engine/agnetaapis/linkgraphapi/proto/generated"
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/snapshot"
+	"Search_Engine/linkgraph/store/boltdb"
+	"Search_Engine/linkgraph/store/cockroachdb"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// snapshotGraph is implemented by the link graph stores that can be used as
+// the source or destination of a snapshot.
+type snapshotGraph interface {
+	snapshot.Source
+	graph.Restorer
+	io.Closer
+}
+
+func main() {
+	logger := logrus.NewEntry(logrus.New())
+
+	// Interrupted restores can be resumed from their checkpoint.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err := runMain(ctx, os.Args[1:], logger)
+	stop()
+	if err != nil {
+		logger.WithField("err", err).Error("snapshot operation failed")
+		os.Exit(1)
+	}
+}
+
+func runMain(ctx context.Context, args []string, logger *logrus.Entry) error {
+	if len(args) == 0 {
+		return xerrors.Errorf("usage: linkgraph-snapshot export|restore [flags]")
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ExitOnError)
+	linkGraphURI := fs.String("link-graph-uri", "", "The URI for connecting to the link-graph (supported URIs: bolt:///path/to/graph.db, postgresql://user@host:26257/linkgraph?sslmode=disable, grpc://host:port)")
+	file := fs.String("file", "", "The path to the snapshot file")
+	batchSize := fs.Int("batch-size", 500, "The number of links or edges to restore with each batch")
+	checkpointPath := fs.String("checkpoint", "", "The path to the file for tracking the restore progress (defaults to a path derived from the snapshot file path and the link graph URI)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	} else if *file == "" {
+		return xerrors.Errorf("snapshot file must be specified with -file")
+	}
+
+	g, err := getLinkGraph(*linkGraphURI)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = g.Close() }()
+
+	switch args[0] {
+	case "export":
+		f, err := os.Create(*file)
+		if err != nil {
+			return xerrors.Errorf("unable to create snapshot file: %w", err)
+		}
+		stats, err := snapshot.Export(ctx, f, g)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		logger.WithFields(logrus.Fields{
+			"links": stats.Links,
+			"edges": stats.Edges,
+		}).Info("exported link graph snapshot")
+	case "restore":
+		if *checkpointPath == "" {
+			*checkpointPath = defaultCheckpointPath(*file, *linkGraphURI)
+		}
+
+		f, err := os.Open(*file)
+		if err != nil {
+			return xerrors.Errorf("unable to open snapshot file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+
+		stats, err := snapshot.Restore(ctx, f, g, snapshot.RestoreOptions{
+			BatchSize:      *batchSize,
+			CheckpointPath: *checkpointPath,
+		})
+		if err != nil {
+			return err
+		}
+		logger.WithFields(logrus.Fields{
+			"links":   stats.Links,
+			"edges":   stats.Edges,
+			"skipped": stats.Skipped,
+		}).Info("restored link graph snapshot")
+	default:
+		return xerrors.Errorf("unsupported command %q", args[0])
+	}
+	return nil
+}
+
+func getLinkGraph(linkGraphURI string) (snapshotGraph, error) {
+	if linkGraphURI == "" {
+		return nil, xerrors.Errorf("link graph URI must be specified with -link-graph-uri")
+	}
+
+	uri, err := url.Parse(linkGraphURI)
+	if err != nil {
+		return nil, xerrors.Errorf("could not parse link graph URI: %w", err)
+	}
+
+	switch uri.Scheme {
+	case "bolt":
+		return boltdb.NewBoltGraph(uri.Path)
+	case "postgresql":
+		return cockroachdb.NewCockroachDBGraph(linkGraphURI)
+	case "grpc":
+		conn, err := grpc.Dial(uri.Host, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, xerrors.Errorf("could not connect to link graph API: %w", err)
+		}
+		return &rpcGraph{
+			LinkGraphClient: linkgraphapi.NewLinkGraphClient(generated.NewLinkGraphClient(conn)),
+			conn:            conn,
+		}, nil
+	case "in-memindex":
+		return nil, xerrors.Errorf("in-memindex link graphs must be accessed through the link graph API of the process that hosts them using a grpc://host:port URI")
+	default:
+		return nil, xerrors.Errorf("unsupported link graph URI scheme: %q", uri.Scheme)
+	}
+}
+
+// rpcGraph is a link graph exposed by a remote link graph API server.
+type rpcGraph struct {
+	*linkgraphapi.LinkGraphClient
+	conn *grpc.ClientConn
+}
+
+// Close implements io.Closer.
+func (g *rpcGraph) Close() error { return g.conn.Close() }
+
+// defaultCheckpointPath returns the checkpoint path for restoring the
+// snapshot file into the link graph with the specified URI. The path is
+// keyed by the link graph so that restoring the same snapshot into several
+// graphs does not resume from the wrong checkpoint.
+func defaultCheckpointPath(file, linkGraphURI string) string {
+	sum := sha256.Sum256([]byte(linkGraphURI))
+	return file + "." + hex.EncodeToString(sum[:8]) + ".checkpoint"
+}
@@ -0,0 +1,107 @@
+// Command linkrot-report writes a CSV report of the links whose targets are
+// failing to be crawled.
+//
+// Usage:
+//
+//	linkrot-report -link-graph-uri URI [-min-failure-count N] [-output PATH]
+package main
+
+import (
+	"Search_Engine/linkgraph/linkrot"
+	"Search_Engine/linkgraph/store/boltdb"
+	"Search_Engine/linkgraph/store/cockroachdb"
+	"context"
+	"flag"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// reportGraph is implemented by the link graph stores that can be used for
+// generating reports.
+type reportGraph interface {
+	linkrot.Graph
+	io.Closer
+}
+
+func main() {
+	logger := logrus.NewEntry(logrus.New())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err := runMain(ctx, os.Args[1:], logger)
+	stop()
+	if err != nil {
+		logger.WithField("err", err).Error("unable to generate report")
+		os.Exit(1)
+	}
+}
+
+func runMain(ctx context.Context, args []string, logger *logrus.Entry) error {
+	fs := flag.NewFlagSet("linkrot-report", flag.ExitOnError)
+	linkGraphURI := fs.String("link-graph-uri", "", "The URI for connecting to the link-graph (supported URIs: bolt:///path/to/graph.db, postgresql://user@host:26257/linkgraph?sslmode=disable&edge_history=true)")
+	minFailureCount := fs.Int("min-failure-count", 1, "The minimum number of consecutive failed crawl attempts for a link target to be reported")
+	output := fs.String("output", "", "The path to the CSV file to write the report to (defaults to STDOUT)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	g, err := getLinkGraph(*linkGraphURI)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = g.Close() }()
+
+	broken, err := linkrot.Report(ctx, g, linkrot.Options{MinFailureCount: *minFailureCount})
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		err = linkrot.WriteCSV(os.Stdout, broken)
+	} else {
+		err = writeReportFile(*output, broken)
+	}
+	if err != nil {
+		return err
+	}
+	logger.WithField("broken_links", len(broken)).Info("generated broken link report")
+	return nil
+}
+
+func writeReportFile(path string, broken []*linkrot.BrokenLink) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return xerrors.Errorf("unable to create report file: %w", err)
+	}
+	err = linkrot.WriteCSV(f, broken)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func getLinkGraph(linkGraphURI string) (reportGraph, error) {
+	if linkGraphURI == "" {
+		return nil, xerrors.Errorf("link graph URI must be specified with -link-graph-uri")
+	}
+
+	uri, err := url.Parse(linkGraphURI)
+	if err != nil {
+		return nil, xerrors.Errorf("could not parse link graph URI: %w", err)
+	}
+
+	switch uri.Scheme {
+	case "bolt":
+		// The report only reads the graph so the edge history that has
+		// been recorded so far can always be used.
+		return boltdb.NewBoltGraph(uri.Path, boltdb.WithEdgeHistory())
+	case "postgresql":
+		return cockroachdb.NewCockroachDBGraph(linkGraphURI)
+	default:
+		return nil, xerrors.Errorf("unsupported link graph URI scheme: %q", uri.Scheme)
+	}
+}
@@ -2,12 +2,12 @@ package crawler
 
 import (
 	"Search_Engine/linkgraph/graph"
-	"Search_Engine/linkgraph/store/memory"
 	"Search_Engine/pipeline"
 	"Search_Engine/textindexer/index"
-	"Search_Engine/textindexer/store/memindex"
 	"context"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
 	"net/http"
 	"time"
 )
@@ -27,20 +27,38 @@ type PrivateNetworkDetector interface {
 // graph instance
 type Graph interface {
 	// UpsertLink creates a new link or updates an existing link.
-	UpsertLink(link *graph.Link) error
-	// UpsertEdge creates a new edge or updates an existing edge.
-	UpsertEdge(edge *graph.Edge) error
+	UpsertLink(ctx context.Context, link *graph.Link) error
+	// UpsertLinks creates or updates a batch of links. Links that have been
+	// deleted from the graph are skipped and have their ID set to uuid.Nil.
+	UpsertLinks(ctx context.Context, links []*graph.Link) error
+	// UpsertEdges creates or updates a batch of edges.
+	UpsertEdges(ctx context.Context, edges []*graph.Edge) error
 	// RemoveStaleEdges removes any edge that originates from the
 	// Specified link ID and was updated before the specified timestamp.
-	RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error
+	RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time) error
+}
+
+// HostTracker is implemented by objects that can look up and update the
+// per-host crawl state.
+type HostTracker interface {
+	// UpsertHost creates a new host or updates its robots.txt, crawl delay
+	// and block state.
+	UpsertHost(ctx context.Context, host *graph.Host) error
+	// FindHost looks up a host by its name.
+	FindHost(ctx context.Context, name string) (*graph.Host, error)
+	// RecordHostFetch records a fetch attempt for the specified host.
+	RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error
 }
 
 // Indexer is implement ed by objects that can index the contents of web-pages
 // retrieved by the crawler pipeline
 type Indexer interface {
-	// Index inserts a new document to the index or updates the index entry
-	// for an existing document
-	Index(doc *index.Document) error
+	// IndexBatch inserts or updates a batch of documents. If some of the
+	// documents cannot be indexed, an *index.BatchError is returned.
+	IndexBatch(ctx context.Context, docs []*index.Document) error
+	// Delete removes the document with the specified link ID from the
+	// index.
+	Delete(ctx context.Context, linkID uuid.UUID) error
 }
 
 // Config encapsulates the configuration options for creating a new Crawler.
@@ -51,46 +69,88 @@ type Config struct {
 	URLGetter URLGetter
 	// A GraphUpdater instance for adding new link to the link graph.
 	Graph Graph
-	// A TextIndexer instance for indexing the content of each retrieved link.
+	// A HostTracker instance for skipping blocked hosts, caching robots.txt
+	// files and recording fetch attempts. If not specified, host state is
+	// not tracked and robots.txt files are fetched once per hostStateTTL.
+	Hosts HostTracker
+	// A TextIndexer instance for indexing the content of each retrieved link
+	// and deleting the documents of pages that have been removed.
 	Indexer Indexer
 	// The number of concurrent workers used for retrieving links
 	FetchWorkers int
-}
+	// The maximum number of documents to accumulate before sending them to
+	// the Indexer as a single batch. If not specified, each document is
+	// indexed as soon as its page has been retrieved.
+	IndexBatchSize int
+	// The maximum amount of time to keep accumulating documents before
+	// sending a partial batch to the Indexer. If not specified, partial
+	// batches are only sent once all links have been crawled.
+	IndexFlushInterval time.Duration
+	// URLDerivedLinkIDs causes the IDs of discovered links to be computed
+	// locally via graph.LinkIDForURL instead of being assigned by the graph.
+	// It should be enabled when the graph is configured to use URL-derived
+	// IDs.
+	URLDerivedLinkIDs bool
+	// The logger to use for reporting host state lookups and updates that
+	// failed and documents that could not be indexed. If not specified,
+	// such errors are not reported.
+	Logger *logrus.Entry
+}
+
+// finalFlushTimeout bounds the time Crawl waits for the documents that are
+// still pending once all links have been processed to be indexed.
+const finalFlushTimeout = 30 * time.Second
 
 type Crawler struct {
-	p *pipeline.Pipeline
+	p       *pipeline.Pipeline
+	indexer *textIndexer
 }
 
 // NewCrawler returns a new crawler instance.
 func NewCrawler(cfg Config) *Crawler {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = logrus.NewEntry(&logrus.Logger{Out: ioutil.Discard})
+	}
+	indexer := newTextIndexer(cfg.Indexer, cfg.IndexBatchSize, cfg.IndexFlushInterval, logger)
 	return &Crawler{
-		p: assembleCrawlerPipeline(cfg),
+		p:       assembleCrawlerPipeline(cfg, indexer, logger),
+		indexer: indexer,
 	}
 }
 
 // assembleCrawlerPipeline creates the various stages of a crawler pipeline
 // using the options in cfg and assembles them into a pipeline instance.
-func assembleCrawlerPipeline(cfg Config) *pipeline.Pipeline {
-	indexer, _ := memindex.NewInMemoryBleveIndexer()
+func assembleCrawlerPipeline(cfg Config, indexer *textIndexer, logger *logrus.Entry) *pipeline.Pipeline {
 	return pipeline.New(
 		pipeline.FixedWorkerPool(
-			newLinkFetcher(cfg.URLGetter, cfg.PrivateNetworkDetector),
+			newLinkFetcher(cfg.URLGetter, cfg.PrivateNetworkDetector, newHostPolicy(cfg.Hosts, cfg.URLGetter, logger)),
 			cfg.FetchWorkers,
 		),
 		pipeline.NewFIFO(newLinkExtractor(cfg.PrivateNetworkDetector)),
 		pipeline.NewFIFO(newTextExtractor()),
 		pipeline.Broadcast(
-			newGraphUpdater(memory.NewInMemoryGraph()),
-			newTextIndexer(indexer),
+			newGraphUpdater(cfg.Graph, cfg.URLDerivedLinkIDs),
+			indexer,
 		),
 	)
 }
 
 // Crawl iterates linkIt and send each link through the crawler pipeline
-// returning the total count of links that went through the pipeline.
+// returning the total count of links that went through the pipeline. Any
+// documents that are still waiting to be indexed are flushed before Crawl
+// returns.
 func (c *Crawler) Crawl(ctx context.Context, linkIt graph.LinkIterator) (int, error) {
 	sink := new(countingSink)
 	err := c.p.Process(ctx, &linkSource{linkIt: linkIt}, sink)
+
+	// The pending documents are flushed with a separate context so that
+	// they are not lost when ctx has been cancelled.
+	flushCtx, cancel := context.WithTimeout(context.Background(), finalFlushTimeout)
+	defer cancel()
+	if flushErr := c.indexer.flush(flushCtx); err == nil {
+		err = flushErr
+	}
 	return sink.getCount(), err
 }
 
@@ -113,6 +173,7 @@ func (ls *linkSource) Payload() pipeline.Payload {
 	p.LinkID = link.ID
 	p.URL = link.URL
 	p.RetrievedAt = link.RetrievedAt
+	p.FailureCount = link.FailureCount
 	return p
 }
 
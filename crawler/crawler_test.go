@@ -0,0 +1,211 @@
+package crawler
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/store/memory"
+	"Search_Engine/textindexer/index"
+	"Search_Engine/textindexer/store/memindex"
+	"context"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(CrawlerTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type CrawlerTestSuite struct {
+	graph   *memory.InMemoryGraph
+	indexer *memindex.InMemoryBleveIndexer
+	getter  *stubGetter
+	crawler *Crawler
+}
+
+func (s *CrawlerTestSuite) SetUpTest(c *gc.C) {
+	var err error
+	s.graph = memory.NewInMemoryGraph()
+	s.indexer, err = memindex.NewInMemoryBleveIndexer()
+	c.Assert(err, gc.IsNil)
+	s.getter = new(stubGetter)
+	s.crawler = NewCrawler(Config{
+		PrivateNetworkDetector: publicNetDetector{},
+		URLGetter:              s.getter,
+		Graph:                  s.graph,
+		Indexer:                s.indexer,
+		FetchWorkers:           1,
+	})
+}
+
+func (s *CrawlerTestSuite) TearDownTest(c *gc.C) {
+	c.Assert(s.indexer.Close(), gc.IsNil)
+}
+
+func (s *CrawlerTestSuite) TestRemovedPageDeletedAfterTransientFailure(c *gc.C) {
+	link := &graph.Link{URL: "http://example.com/page"}
+	c.Assert(s.graph.UpsertLink(context.TODO(), link), gc.IsNil)
+
+	s.getter.statusCode = http.StatusOK
+	s.crawl(c, link.ID)
+	_, err := s.indexer.FindByID(context.TODO(), link.ID)
+	c.Assert(err, gc.IsNil, gc.Commentf("retrieved page should be indexed"))
+
+	s.getter.statusCode = http.StatusServiceUnavailable
+	s.crawl(c, link.ID)
+	_, err = s.indexer.FindByID(context.TODO(), link.ID)
+	c.Assert(err, gc.IsNil, gc.Commentf("transient failures should not remove the document"))
+
+	s.getter.statusCode = http.StatusNotFound
+	updated := s.crawl(c, link.ID)
+	c.Assert(updated.FailureCount, gc.Equals, 2)
+	_, err = s.indexer.FindByID(context.TODO(), link.ID)
+	c.Assert(xerrors.Is(err, index.ErrNotFound), gc.Equals, true, gc.Commentf("removed page should be deleted from the index"))
+}
+
+func (s *CrawlerTestSuite) TestRejectedDocumentsDoNotFailCrawl(c *gc.C) {
+	link := &graph.Link{URL: "http://example.com/page"}
+	c.Assert(s.graph.UpsertLink(context.TODO(), link), gc.IsNil)
+
+	s.getter.statusCode = http.StatusOK
+	s.crawler = NewCrawler(Config{
+		PrivateNetworkDetector: publicNetDetector{},
+		URLGetter:              s.getter,
+		Graph:                  s.graph,
+		Indexer:                rejectingIndexer{},
+		FetchWorkers:           1,
+	})
+	updated := s.crawl(c, link.ID)
+	c.Assert(updated.RetrievedAt.IsZero(), gc.Equals, false, gc.Commentf("link should be updated even if its document is rejected"))
+}
+
+func (s *CrawlerTestSuite) TestRobotsTxtEnforced(c *gc.C) {
+	allowed := &graph.Link{URL: "http://example.com/page"}
+	disallowed := &graph.Link{URL: "http://example.com/private/page"}
+	c.Assert(s.graph.UpsertLink(context.TODO(), allowed), gc.IsNil)
+	c.Assert(s.graph.UpsertLink(context.TODO(), disallowed), gc.IsNil)
+
+	s.getter.statusCode = http.StatusOK
+	s.getter.robotsTxt = "User-agent: *\nDisallow: /private\n"
+	s.crawler = NewCrawler(Config{
+		PrivateNetworkDetector: publicNetDetector{},
+		URLGetter:              s.getter,
+		Graph:                  s.graph,
+		Hosts:                  s.graph,
+		Indexer:                s.indexer,
+		FetchWorkers:           1,
+	})
+
+	c.Assert(s.crawl(c, allowed.ID).RetrievedAt.IsZero(), gc.Equals, false)
+	c.Assert(s.crawl(c, disallowed.ID).RetrievedAt.IsZero(), gc.Equals, true, gc.Commentf("links disallowed by robots.txt should not be fetched"))
+
+	host, err := s.graph.FindHost(context.TODO(), "example.com")
+	c.Assert(err, gc.IsNil)
+	c.Assert(host.RobotsTxt, gc.Equals, s.getter.robotsTxt)
+	c.Assert(host.RobotsFetchedAt.IsZero(), gc.Equals, false)
+	c.Assert(host.FetchCount, gc.Equals, 1)
+}
+
+func (s *CrawlerTestSuite) TestHostTrackerErrorsDoNotFailCrawl(c *gc.C) {
+	link := &graph.Link{URL: "http://example.com/page"}
+	c.Assert(s.graph.UpsertLink(context.TODO(), link), gc.IsNil)
+
+	s.getter.statusCode = http.StatusOK
+	s.crawler = NewCrawler(Config{
+		PrivateNetworkDetector: publicNetDetector{},
+		URLGetter:              s.getter,
+		Graph:                  s.graph,
+		Hosts:                  failingHostTracker{},
+		Indexer:                s.indexer,
+		FetchWorkers:           1,
+	})
+	c.Assert(s.crawl(c, link.ID).RetrievedAt.IsZero(), gc.Equals, false)
+}
+
+// crawl sends the current state of the specified link through the crawler
+// and returns the updated link.
+func (s *CrawlerTestSuite) crawl(c *gc.C, linkID uuid.UUID) *graph.Link {
+	link, err := s.graph.FindLink(context.TODO(), linkID)
+	c.Assert(err, gc.IsNil)
+
+	_, err = s.crawler.Crawl(context.TODO(), &linkSliceIterator{links: []*graph.Link{link}})
+	c.Assert(err, gc.IsNil)
+
+	link, err = s.graph.FindLink(context.TODO(), linkID)
+	c.Assert(err, gc.IsNil)
+	return link
+}
+
+type stubGetter struct {
+	statusCode int
+	robotsTxt  string
+}
+
+func (g *stubGetter) Get(url string) (*http.Response, error) {
+	statusCode := g.statusCode
+	body := "<html><head><title>Page</title></head><body>Hello world</body></html>"
+	if strings.HasSuffix(url, "/robots.txt") {
+		statusCode, body = http.StatusOK, g.robotsTxt
+		if body == "" {
+			statusCode = http.StatusNotFound
+		}
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"text/html"}},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}, nil
+}
+
+// rejectingIndexer is an Indexer that fails to index every document.
+type rejectingIndexer struct{}
+
+func (rejectingIndexer) IndexBatch(_ context.Context, docs []*index.Document) error {
+	batchErr := &index.BatchError{Errors: make(map[int]error)}
+	for i := range docs {
+		batchErr.Errors[i] = index.ErrMissingLinkID
+	}
+	return batchErr
+}
+
+func (rejectingIndexer) Delete(context.Context, uuid.UUID) error { return nil }
+
+// failingHostTracker is a HostTracker whose methods always fail.
+type failingHostTracker struct{}
+
+func (failingHostTracker) UpsertHost(context.Context, *graph.Host) error {
+	return xerrors.New("host tracker unavailable")
+}
+
+func (failingHostTracker) FindHost(context.Context, string) (*graph.Host, error) {
+	return nil, xerrors.New("host tracker unavailable")
+}
+
+func (failingHostTracker) RecordHostFetch(context.Context, string, time.Time, bool) error {
+	return xerrors.New("host tracker unavailable")
+}
+
+type publicNetDetector struct{}
+
+func (publicNetDetector) IsPrivate(string) (bool, error) { return false, nil }
+
+type linkSliceIterator struct {
+	links    []*graph.Link
+	curIndex int
+}
+
+func (it *linkSliceIterator) Next() bool {
+	if it.curIndex >= len(it.links) {
+		return false
+	}
+	it.curIndex++
+	return true
+}
+
+func (it *linkSliceIterator) Link() *graph.Link { return it.links[it.curIndex-1] }
+func (it *linkSliceIterator) Error() error      { return nil }
+func (it *linkSliceIterator) Close() error      { return nil }
@@ -4,55 +4,124 @@ import (
 	"Search_Engine/linkgraph/graph"
 	"Search_Engine/pipeline"
 	"context"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
 	"time"
 )
 
+const (
+	// Links that cannot be fetched are retried with an exponential backoff
+	// that starts at minFailureBackoff and is capped at maxFailureBackoff.
+	minFailureBackoff = time.Hour
+	maxFailureBackoff = 7 * 24 * time.Hour
+)
+
 type graphUpdater struct {
-	updater graph.Graph
+	updater       Graph
+	urlDerivedIDs bool
 }
 
-func newGraphUpdater(updater graph.Graph) *graphUpdater {
+func newGraphUpdater(updater Graph, urlDerivedIDs bool) *graphUpdater {
 	return &graphUpdater{
-		updater: updater,
+		updater:       updater,
+		urlDerivedIDs: urlDerivedIDs,
 	}
 }
 
 func (gu *graphUpdater) Process(ctx context.Context, p pipeline.Payload) (pipeline.Payload, error) {
 	payload := p.(*crawlerPayload)
 
+	now := time.Now()
 	src := &graph.Link{
-		ID:          payload.LinkID,
-		URL:         payload.URL,
-		RetrievedAt: time.Now(),
+		ID:           payload.LinkID,
+		URL:          payload.URL,
+		RetrievedAt:  now,
+		StatusCode:   payload.StatusCode,
+		ContentHash:  payload.ContentHash,
+		ETag:         payload.ETag,
+		LastModified: payload.LastModified,
 	}
 
-	if err := gu.updater.UpsertLink(src); err != nil {
+	// Record failed fetches and back off; there are no outgoing links to process.
+	// The previous retrieval time is kept so that the link remains a crawl
+	// candidate and is retried as soon as its backoff expires.
+	if payload.FetchFailed {
+		src.RetrievedAt = payload.RetrievedAt
+		src.FailureCount = payload.FailureCount + 1
+		src.NextCrawlAt = now.Add(failureBackoff(src.FailureCount))
+		if err := gu.updater.UpsertLink(ctx, src); err != nil && !xerrors.Is(err, graph.ErrLinkDeleted) {
+			return nil, err
+		}
+		return p, nil
+	}
+
+	if err := gu.updater.UpsertLink(ctx, src); err != nil {
+		// The link was deleted while it was being crawled.
+		if xerrors.Is(err, graph.ErrLinkDeleted) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
-	// Upsert discovered no-follow links without creating an edge
+	// Upsert all discovered links with a single batch. The followed links
+	// come first so their IDs can be used for creating edges. When the graph
+	// uses URL-derived IDs, the IDs are assigned up front so the graph does
+	// not have to allocate them.
+	dstLinks := make([]*graph.Link, 0, len(payload.Links)+len(payload.NoFollowLinks))
+	for _, dstLink := range payload.Links {
+		dstLinks = append(dstLinks, gu.newLink(dstLink))
+	}
 	for _, dstLink := range payload.NoFollowLinks {
-		dst := &graph.Link{URL: dstLink}
-		if err := gu.updater.UpsertLink(dst); err != nil {
-			return nil, err
-		}
+		dstLinks = append(dstLinks, gu.newLink(dstLink))
+	}
+	if err := gu.updater.UpsertLinks(ctx, dstLinks); err != nil {
+		return nil, err
 	}
 
-	// Upsert discovered links and create edges for them. Keep track of them
-	// the current time so we can drop stale edges that have not been update after this loop
+	// Create edges for the followed links, skipping any links that have been
+	// deleted from the graph. Keep track of the current time so we can drop
+	// stale edges that have not been updated by this batch.
 	removeEdgesOlderThan := time.Now()
-	for _, dstLink := range payload.Links {
-		dst := &graph.Link{URL: dstLink}
-		if err := gu.updater.UpsertLink(src); err != nil {
-			return nil, err
+	edges := make([]*graph.Edge, 0, len(payload.Links))
+	for i, dst := range dstLinks[:len(payload.Links)] {
+		if dst.ID == uuid.Nil {
+			continue
 		}
-		if err := gu.updater.UpsertEdge(&graph.Edge{Src: src.ID, Dst: dst.ID}); err != nil {
-			return nil, err
+		edge := &graph.Edge{Src: src.ID, Dst: dst.ID}
+		if i < len(payload.LinkAnchors) {
+			edge.AnchorText = payload.LinkAnchors[i].Text
+			edge.Rel = payload.LinkAnchors[i].Rel
 		}
+		edges = append(edges, edge)
+	}
+	if err := gu.updater.UpsertEdges(ctx, edges); err != nil {
+		return nil, err
 	}
 	// Drop stale edges that were not touched while upserting the outgoing edges.
-	if err := gu.updater.RemoveStaleEdges(src.ID, removeEdgesOlderThan); err != nil {
+	if err := gu.updater.RemoveStaleEdges(ctx, src.ID, removeEdgesOlderThan); err != nil {
 		return nil, err
 	}
 	return p, nil
 }
+
+// newLink returns a link for a discovered URL.
+func (gu *graphUpdater) newLink(url string) *graph.Link {
+	link := &graph.Link{URL: url}
+	if gu.urlDerivedIDs {
+		link.ID = graph.LinkIDForURL(url)
+	}
+	return link
+}
+
+// failureBackoff returns the delay before a link that failed to be fetched
+// failureCount consecutive times should be crawled again.
+func failureBackoff(failureCount int) time.Duration {
+	backoff := minFailureBackoff
+	for i := 1; i < failureCount && backoff < maxFailureBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxFailureBackoff {
+		backoff = maxFailureBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,219 @@
+package crawler
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"context"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// hostStateTTL specifies how long the crawl state of a host is cached
+	// before it is looked up again.
+	hostStateTTL = 5 * time.Minute
+
+	// robotsTxtTTL specifies how long a host's robots.txt file is used
+	// before it is fetched again.
+	robotsTxtTTL = 24 * time.Hour
+
+	// maxRobotsTxtSize is the maximum number of bytes read from a robots.txt
+	// file; any remaining content is ignored.
+	maxRobotsTxtSize = 512 * 1024
+
+	// maxCachedHosts is the number of cached hosts above which expired
+	// entries are purged from the cache.
+	maxCachedHosts = 10000
+)
+
+// hostPolicy decides whether links can be fetched based on the crawl state
+// of their hosts. It skips blocked hosts, enforces the rules of each host's
+// robots.txt file and spaces out requests to the same host by its crawl
+// delay. Host state is cached for hostStateTTL so that the host tracker is
+// not queried for every link.
+type hostPolicy struct {
+	tracker   HostTracker
+	urlGetter URLGetter
+	logger    *logrus.Entry
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// hostState holds the cached crawl state of a host.
+type hostState struct {
+	mu       sync.Mutex
+	host     *graph.Host
+	robots   *robotsRules
+	loadedAt time.Time
+	// nextFetchAt is the earliest time the next request to the host can
+	// be made.
+	nextFetchAt time.Time
+}
+
+func newHostPolicy(tracker HostTracker, urlGetter URLGetter, logger *logrus.Entry) *hostPolicy {
+	return &hostPolicy{
+		tracker:   tracker,
+		urlGetter: urlGetter,
+		logger:    logger,
+		hosts:     make(map[string]*hostState),
+	}
+}
+
+// admit returns true if u can be fetched. If the host of u specifies a crawl
+// delay, admit blocks until the delay since the previous request to the host
+// has elapsed. Errors while looking up or updating the host state are logged
+// and the link is fetched as if the host had no restrictions.
+func (hp *hostPolicy) admit(ctx context.Context, u *url.URL, hostName string) bool {
+	state := hp.state(hostName)
+
+	state.mu.Lock()
+	now := time.Now()
+	if now.Sub(state.loadedAt) >= hostStateTTL {
+		hp.load(ctx, u, hostName, state, now)
+	}
+	if (state.host != nil && state.host.Blocked) || !state.robots.allowed(u) {
+		state.mu.Unlock()
+		return false
+	}
+
+	// Reserve the next request slot for this host.
+	fetchAt := state.nextFetchAt
+	if fetchAt.Before(now) {
+		fetchAt = now
+	}
+	state.nextFetchAt = fetchAt.Add(state.robots.crawlDelay)
+	state.mu.Unlock()
+
+	if wait := fetchAt.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return false
+		case <-timer.C:
+		}
+	}
+	return true
+}
+
+// recordFetch updates the fetch statistics of the specified host.
+func (hp *hostPolicy) recordFetch(ctx context.Context, hostName string, failed bool) {
+	if hp.tracker == nil {
+		return
+	}
+	if err := hp.tracker.RecordHostFetch(ctx, hostName, time.Now(), failed); err != nil {
+		hp.logger.WithFields(logrus.Fields{"host": hostName, "err": err}).Warn("unable to record host fetch")
+	}
+}
+
+// state returns the cached state for the specified host, creating it if
+// required.
+func (hp *hostPolicy) state(hostName string) *hostState {
+	hp.mu.Lock()
+	defer hp.mu.Unlock()
+
+	if state := hp.hosts[hostName]; state != nil {
+		return state
+	}
+
+	if len(hp.hosts) >= maxCachedHosts {
+		hp.purgeExpiredLocked()
+	}
+	state := &hostState{robots: new(robotsRules)}
+	hp.hosts[hostName] = state
+	return state
+}
+
+// purgeExpiredLocked removes the cached hosts whose state has expired. The
+// caller must hold hp.mu.
+func (hp *hostPolicy) purgeExpiredLocked() {
+	for hostName, state := range hp.hosts {
+		// Hosts whose state is locked are being loaded or admitted.
+		if !state.mu.TryLock() {
+			continue
+		}
+		expired := time.Since(state.loadedAt) >= hostStateTTL && time.Now().After(state.nextFetchAt)
+		state.mu.Unlock()
+		if expired {
+			delete(hp.hosts, hostName)
+		}
+	}
+}
+
+// load refreshes the cached state of a host and fetches its robots.txt file
+// if the stored copy is missing or stale. The caller must hold state.mu.
+func (hp *hostPolicy) load(ctx context.Context, u *url.URL, hostName string, state *hostState, now time.Time) {
+	state.loadedAt = now
+
+	host := &graph.Host{Name: hostName}
+	if hp.tracker != nil {
+		found, err := hp.tracker.FindHost(ctx, hostName)
+		switch {
+		case err == nil:
+			host = found
+		case !xerrors.Is(err, graph.ErrNotFound):
+			hp.logger.WithFields(logrus.Fields{"host": hostName, "err": err}).Warn("unable to look up host")
+			return
+		}
+	}
+	state.host = host
+	if host.LastContactedAt.Add(host.CrawlDelay).After(state.nextFetchAt) {
+		state.nextFetchAt = host.LastContactedAt.Add(host.CrawlDelay)
+	}
+
+	if !host.RobotsFetchedAt.IsZero() && now.Sub(host.RobotsFetchedAt) < robotsTxtTTL {
+		state.robots = parseRobotsTxt(host.RobotsTxt)
+		state.robots.crawlDelay = host.CrawlDelay
+		return
+	}
+
+	robotsTxt, err := hp.fetchRobotsTxt(u)
+	if err != nil {
+		hp.logger.WithFields(logrus.Fields{"host": hostName, "err": err}).Warn("unable to fetch robots.txt")
+		state.robots = parseRobotsTxt(host.RobotsTxt)
+		state.robots.crawlDelay = host.CrawlDelay
+		return
+	}
+	state.robots = parseRobotsTxt(robotsTxt)
+
+	if hp.tracker == nil {
+		return
+	}
+	host.RobotsTxt = robotsTxt
+	host.RobotsFetchedAt = now
+	host.CrawlDelay = state.robots.crawlDelay
+	if err = hp.tracker.UpsertHost(ctx, host); err != nil {
+		hp.logger.WithFields(logrus.Fields{"host": hostName, "err": err}).Warn("unable to store robots.txt")
+	}
+}
+
+// fetchRobotsTxt retrieves the robots.txt file of the host that u points to.
+// An empty file is returned if the host does not provide one.
+func (hp *hostPolicy) fetchRobotsTxt(u *url.URL) (string, error) {
+	robotsURL := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+	res, err := hp.urlGetter.Get(robotsURL.String())
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	switch {
+	case res.StatusCode >= 200 && res.StatusCode <= 299:
+		content, err := ioutil.ReadAll(io.LimitReader(res.Body, maxRobotsTxtSize))
+		if err != nil {
+			return "", err
+		}
+		return string(content), nil
+	case res.StatusCode >= 400 && res.StatusCode <= 499:
+		// Hosts without a robots.txt file can be crawled without restrictions.
+		return "", nil
+	default:
+		return "", xerrors.Errorf("unexpected status code %d (%s)", res.StatusCode, http.StatusText(res.StatusCode))
+	}
+}
@@ -3,15 +3,23 @@ package crawler
 import (
 	"Search_Engine/pipeline"
 	"context"
+	"html"
 	"net/url"
 	"regexp"
+	"strings"
 )
 
+// The maximum length (in characters) of the anchor text recorded for a link.
+const maxAnchorTextLength = 256
+
 var (
 	exclusionRegex = regexp.MustCompile(`(?i)\.(?:jpg|jpeg|png|gif|ico|css|js)$`)
 	baseHrefRegex  = regexp.MustCompile(`(?i)<base.*?href\s*?=\s?"(.*?)\s*?"`)
 	findLinkRegex  = regexp.MustCompile(`(?i)<a.*?href\s*?=\s*?"\s*?(.*?)\s*?".*?>`)
 	nofollowRegex  = regexp.MustCompile(`(?i)rel\s*?=\s*?"?nofollow"?`)
+	relRegex       = regexp.MustCompile(`(?i)\srel\s*?=\s*?(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+	anchorEndRegex = regexp.MustCompile(`(?i)</a\s*>|<a[\s>]`)
+	tagRegex       = regexp.MustCompile(`<[^>]*>`)
 )
 
 type linkExtractor struct {
@@ -26,6 +34,10 @@ func newLinkExtractor(netDetector PrivateNetworkDetector) *linkExtractor {
 
 func (le *linkExtractor) Process(ctc context.Context, p pipeline.Payload) (pipeline.Payload, error) {
 	payload := p.(*crawlerPayload)
+	if payload.FetchFailed {
+		return payload, nil
+	}
+
 	relTo, err := url.Parse(payload.URL)
 	if err != nil {
 		return nil, err
@@ -38,34 +50,72 @@ func (le *linkExtractor) Process(ctc context.Context, p pipeline.Payload) (pipel
 		}
 	}
 	// Find the unique set of links from the document, resole them and
-	// add them to the payload.
-	seenMap := make(map[string]struct{})
+	// add them to the payload. seenMap tracks the index of each followed
+	// link in payload.Links or -1 for no-follow links.
+	seenMap := make(map[string]int)
 
-	for _, match := range findLinkRegex.FindAllStringSubmatch(content, -1) {
-		link := resolveURL(relTo, match[1])
+	for _, match := range findLinkRegex.FindAllStringSubmatchIndex(content, -1) {
+		tag := content[match[0]:match[1]]
+		link := resolveURL(relTo, content[match[2]:match[3]])
 		if !le.retainLink(relTo.Hostname(), link) {
 			continue
 		}
-		// Truncate anchors and drop duplicates
+		// Truncate anchors and drop duplicates. If a link appears more
+		// than once, keep the first non-empty anchor text.
 		link.Fragment = ""
 		linkStr := link.String()
-		if _, seen := seenMap[linkStr]; seen {
+		if index, seen := seenMap[linkStr]; seen {
+			if index >= 0 && payload.LinkAnchors[index].Text == "" {
+				payload.LinkAnchors[index].Text = extractAnchorText(content[match[1]:])
+			}
 			continue
 		}
 		// Skip URLs that point to files that cannot contain html content.
 		if exclusionRegex.MatchString(linkStr) {
 			continue
 		}
-		seenMap[linkStr] = struct{}{}
-		if nofollowRegex.MatchString(match[0]) {
+		if nofollowRegex.MatchString(tag) {
+			seenMap[linkStr] = -1
 			payload.NoFollowLinks = append(payload.NoFollowLinks, linkStr)
 		} else {
+			seenMap[linkStr] = len(payload.Links)
 			payload.Links = append(payload.Links, linkStr)
+			payload.LinkAnchors = append(payload.LinkAnchors, linkAnchor{
+				Text: extractAnchorText(content[match[1]:]),
+				Rel:  extractRel(tag),
+			})
 		}
 	}
 	return payload, nil
 }
 
+// extractAnchorText returns the normalized text of an anchor whose contents
+// start at the beginning of s. The anchor ends at the next closing or opening
+// anchor tag; unterminated anchors yield an empty string.
+func extractAnchorText(s string) string {
+	end := anchorEndRegex.FindStringIndex(s)
+	if end == nil {
+		return ""
+	}
+
+	text := html.UnescapeString(tagRegex.ReplaceAllString(s[:end[0]], " "))
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > maxAnchorTextLength {
+		text = strings.TrimSpace(string(runes[:maxAnchorTextLength]))
+	}
+	return text
+}
+
+// extractRel returns the lower-cased value of the rel attribute of an anchor
+// tag.
+func extractRel(tag string) string {
+	match := relRegex.FindStringSubmatch(tag)
+	if match == nil {
+		return ""
+	}
+	return strings.ToLower(strings.Join(strings.Fields(match[1]+match[2]+match[3]), " "))
+}
+
 func (le *linkExtractor) retainLink(srcHost string, link *url.URL) bool {
 	// Skip links that could not be resolved
 	if link == nil {
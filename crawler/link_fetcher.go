@@ -1,8 +1,11 @@
 package crawler
 
 import (
+	"Search_Engine/linkgraph/graph"
 	"Search_Engine/pipeline"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"net/url"
 	"strings"
@@ -11,12 +14,14 @@ import (
 type linkFetcher struct {
 	urlGetter   URLGetter
 	netDetector PrivateNetworkDetector
+	hosts       *hostPolicy
 }
 
-func newLinkFetcher(urlGetter URLGetter, netDetector PrivateNetworkDetector) *linkFetcher {
+func newLinkFetcher(urlGetter URLGetter, netDetector PrivateNetworkDetector, hosts *hostPolicy) *linkFetcher {
 	return &linkFetcher{
 		urlGetter:   urlGetter,
 		netDetector: netDetector,
+		hosts:       hosts,
 	}
 }
 
@@ -34,10 +39,25 @@ func (lf *linkFetcher) Process(ctx context.Context, p pipeline.Payload) (pipelin
 		return nil, nil
 	}
 
-	res, err := lf.urlGetter.Get(payload.URL)
+	// Never crawl blocked hosts or pages disallowed by robots.txt and wait
+	// for the crawl delay of the host to elapse.
+	u, err := url.Parse(payload.URL)
 	if err != nil {
 		return nil, nil
 	}
+	hostName, err := graph.HostName(payload.URL)
+	if err != nil || !lf.hosts.admit(ctx, u, hostName) {
+		return nil, nil
+	}
+
+	// Failed fetches are still forwarded so that the failure can be
+	// recorded in the link graph.
+	res, err := lf.urlGetter.Get(payload.URL)
+	if err != nil {
+		payload.FetchFailed = true
+		lf.hosts.recordFetch(ctx, hostName, true)
+		return payload, nil
+	}
 
 	_, err = io.Copy(&payload.RawContent, res.Body)
 	_ = res.Body.Close()
@@ -45,9 +65,19 @@ func (lf *linkFetcher) Process(ctx context.Context, p pipeline.Payload) (pipelin
 	if err != nil {
 		return nil, err
 	}
-	// Skip payloads for invalid http status code
-	if res.StatusCode < 200 || res.StatusCode > 299 {
-		return nil, nil
+
+	payload.StatusCode = res.StatusCode
+	payload.ETag = res.Header.Get("ETag")
+	payload.LastModified = res.Header.Get("Last-Modified")
+	contentHash := sha256.Sum256(payload.RawContent.Bytes())
+	payload.ContentHash = hex.EncodeToString(contentHash[:])
+
+	// Flag payloads for invalid http status code
+	failed := res.StatusCode < 200 || res.StatusCode > 299
+	lf.hosts.recordFetch(ctx, hostName, failed)
+	if failed {
+		payload.FetchFailed = true
+		return payload, nil
 	}
 	// Skip payloads for non-html payloads
 	if contentType := res.Header.Get("Content-Type"); !strings.Contains(contentType, "html") {
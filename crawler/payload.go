@@ -28,8 +28,26 @@ type crawlerPayload struct {
 	// will be created from this link to them
 	NoFollowLinks []string
 	Links         []string
-	Title         string
-	TextContent   string
+	// LinkAnchors holds the anchor details for each entry in Links.
+	LinkAnchors []linkAnchor
+	Title       string
+	TextContent string
+
+	// Details about the fetch attempt that are persisted to the link graph.
+	// FetchFailed is set when the link could not be retrieved; such payloads
+	// skip the content processing stages and only update the link graph.
+	StatusCode   int
+	ContentHash  string
+	ETag         string
+	LastModified string
+	FailureCount int
+	FetchFailed  bool
+}
+
+// linkAnchor describes the anchor that a link was discovered through.
+type linkAnchor struct {
+	Text string
+	Rel  string
 }
 
 func (p *crawlerPayload) MarkAsProcessed() {
@@ -37,8 +55,15 @@ func (p *crawlerPayload) MarkAsProcessed() {
 	p.RawContent.Reset()
 	p.NoFollowLinks = p.NoFollowLinks[:0]
 	p.Links = p.Links[:0]
+	p.LinkAnchors = p.LinkAnchors[:0]
 	p.Title = p.Title[:0]
 	p.TextContent = p.TextContent[:0]
+	p.StatusCode = 0
+	p.ContentHash = p.ContentHash[:0]
+	p.ETag = p.ETag[:0]
+	p.LastModified = p.LastModified[:0]
+	p.FailureCount = 0
+	p.FetchFailed = false
 	payloadPool.Put(p)
 }
 
@@ -51,8 +76,15 @@ func (p *crawlerPayload) Clone() pipeline.Payload {
 	newP.RetrievedAt = p.RetrievedAt
 	newP.NoFollowLinks = append([]string(nil), p.NoFollowLinks...)
 	newP.Links = append([]string(nil), p.Links...)
+	newP.LinkAnchors = append([]linkAnchor(nil), p.LinkAnchors...)
 	newP.Title = p.Title
 	newP.TextContent = p.TextContent
+	newP.StatusCode = p.StatusCode
+	newP.ContentHash = p.ContentHash
+	newP.ETag = p.ETag
+	newP.LastModified = p.LastModified
+	newP.FailureCount = p.FailureCount
+	newP.FetchFailed = p.FetchFailed
 
 	_, err := io.Copy(&newP.RawContent, &p.RawContent)
 	if err != nil {
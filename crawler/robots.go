@@ -0,0 +1,156 @@
+package crawler
+
+import (
+	"bufio"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// robotsUserAgent is the product token that robots.txt groups are matched
+// against.
+const robotsUserAgent = "agneta"
+
+// maxCrawlDelay caps the crawl delay that a robots.txt file can request.
+const maxCrawlDelay = time.Minute
+
+// robotsRules holds the robots.txt rules that apply to the crawler.
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// parseRobotsTxt extracts the rules of the group that applies to the crawler
+// from the contents of a robots.txt file. The group for robotsUserAgent is
+// used if present; otherwise the rules of the "*" group apply.
+func parseRobotsTxt(content string) *robotsRules {
+	var (
+		matched, fallback *robotsRules
+		group             *robotsRules
+		inAgentLines      bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if idx := strings.IndexByte(line, '#'); idx != -1 {
+			line = line[:idx]
+		}
+		sep := strings.IndexByte(line, ':')
+		if sep == -1 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:sep]))
+		value := strings.TrimSpace(line[sep+1:])
+
+		if key == "user-agent" {
+			// Consecutive user-agent lines share the same group.
+			if !inAgentLines {
+				group = nil
+				inAgentLines = true
+			}
+			agent := strings.ToLower(value)
+			switch {
+			case agent == "*" && fallback == nil:
+				if group == nil {
+					group = new(robotsRules)
+				}
+				fallback = group
+			case agent == robotsUserAgent && matched == nil:
+				if group == nil {
+					group = new(robotsRules)
+				}
+				matched = group
+			}
+			continue
+		}
+		inAgentLines = false
+		if group == nil {
+			continue
+		}
+
+		switch key {
+		case "allow", "disallow":
+			// An empty disallow rule allows everything.
+			if value != "" {
+				group.rules = append(group.rules, robotsRule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+				group.crawlDelay = time.Duration(secs * float64(time.Second))
+				if group.crawlDelay > maxCrawlDelay {
+					group.crawlDelay = maxCrawlDelay
+				}
+			}
+		}
+	}
+
+	switch {
+	case matched != nil:
+		return matched
+	case fallback != nil:
+		return fallback
+	default:
+		return new(robotsRules)
+	}
+}
+
+// allowed returns true if the rules allow the crawler to fetch u. The rule
+// with the longest matching pattern wins; allow rules win ties.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+
+	allow, matchLen := true, -1
+	for _, rule := range r.rules {
+		if !robotsPatternMatches(rule.pattern, path) {
+			continue
+		}
+		if n := len(rule.pattern); n > matchLen || (n == matchLen && rule.allow) {
+			allow, matchLen = rule.allow, n
+		}
+	}
+	return allow
+}
+
+// robotsPatternMatches returns true if path starts with pattern. Patterns
+// may contain "*" wildcards and a trailing "$" that anchors the pattern to
+// the end of the path.
+func robotsPatternMatches(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	if anchored {
+		pattern = pattern[:len(pattern)-1]
+	}
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	for i, part := range parts[1:] {
+		// The last part of an anchored pattern must match the end of the path.
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx == -1 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return !anchored || rest == ""
+}
@@ -0,0 +1,69 @@
+package crawler
+
+import (
+	gc "gopkg.in/check.v1"
+	"net/url"
+	"time"
+)
+
+var _ = gc.Suite(new(RobotsTestSuite))
+
+type RobotsTestSuite struct{}
+
+func (s *RobotsTestSuite) TestGroupSelection(c *gc.C) {
+	robotsTxt := `
+# Rules for everyone else
+User-agent: *
+Disallow: /
+
+User-agent: googlebot
+User-agent: Agneta
+Disallow: /private
+Crawl-delay: 2.5
+`
+	rules := parseRobotsTxt(robotsTxt)
+	c.Assert(rules.crawlDelay, gc.Equals, 2500*time.Millisecond)
+	c.Assert(rules.allowed(mustParseURL(c, "http://example.com/")), gc.Equals, true)
+	c.Assert(rules.allowed(mustParseURL(c, "http://example.com/private/page")), gc.Equals, false)
+
+	rules = parseRobotsTxt("User-agent: *\nDisallow: /\nCrawl-delay: 3600")
+	c.Assert(rules.crawlDelay, gc.Equals, maxCrawlDelay)
+	c.Assert(rules.allowed(mustParseURL(c, "http://example.com/page")), gc.Equals, false)
+	c.Assert(rules.allowed(mustParseURL(c, "http://example.com/robots.txt")), gc.Equals, true)
+
+	rules = parseRobotsTxt("User-agent: googlebot\nDisallow: /")
+	c.Assert(rules.allowed(mustParseURL(c, "http://example.com/page")), gc.Equals, true)
+}
+
+func (s *RobotsTestSuite) TestRulePrecedence(c *gc.C) {
+	rules := parseRobotsTxt(`
+User-agent: *
+Disallow: /docs
+Allow: /docs/public
+Disallow: /*.pdf$
+Disallow: /search?q=
+Disallow:
+`)
+	specs := []struct {
+		url     string
+		allowed bool
+	}{
+		{"http://example.com/", true},
+		{"http://example.com/docs/secret", false},
+		{"http://example.com/docs/public/page", true},
+		{"http://example.com/files/report.pdf", false},
+		{"http://example.com/files/report.pdf?download=1", true},
+		{"http://example.com/search?q=foo", false},
+		{"http://example.com/search", true},
+	}
+	for i, spec := range specs {
+		c.Logf("[spec %d] url: %s", i, spec.url)
+		c.Assert(rules.allowed(mustParseURL(c, spec.url)), gc.Equals, spec.allowed)
+	}
+}
+
+func mustParseURL(c *gc.C, rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	c.Assert(err, gc.IsNil)
+	return u
+}
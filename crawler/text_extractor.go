@@ -31,6 +31,10 @@ func newTextExtractor() *textExtractor {
 
 func (te *textExtractor) Process(ctx context.Context, p pipeline.Payload) (pipeline.Payload, error) {
 	payload := p.(*crawlerPayload)
+	if payload.FetchFailed {
+		return payload, nil
+	}
+
 	policy := te.policyPool.Get().(*bluemonday.Policy)
 
 	if titleMatch := titleRegex.FindStringSubmatch(payload.RawContent.String()); len(titleMatch) == 2 {
@@ -4,21 +4,46 @@ import (
 	"Search_Engine/pipeline"
 	"Search_Engine/textindexer/index"
 	"context"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/xerrors"
+	"net/http"
+	"sync"
 	"time"
 )
 
+// textIndexer accumulates the documents of the retrieved pages and indexes
+// them in batches. A batch is flushed once it reaches batchSize documents or
+// when a payload arrives after its oldest document has been buffered for at
+// least flushInterval. Any remaining documents are flushed by the Crawler
+// once the pipeline has processed all links.
 type textIndexer struct {
-	indexer index.Indexer
+	indexer       Indexer
+	batchSize     int
+	flushInterval time.Duration
+	logger        *logrus.Entry
+
+	mu           sync.Mutex
+	pending      []*index.Document
+	pendingSince time.Time
 }
 
-func newTextIndexer(indexer index.Indexer) *textIndexer {
+func newTextIndexer(indexer Indexer, batchSize int, flushInterval time.Duration, logger *logrus.Entry) *textIndexer {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
 	return &textIndexer{
-		indexer: indexer,
+		indexer:       indexer,
+		batchSize:     batchSize,
+		flushInterval: flushInterval,
+		logger:        logger,
 	}
 }
 
 func (t *textIndexer) Process(ctx context.Context, p pipeline.Payload) (pipeline.Payload, error) {
 	payload := p.(*crawlerPayload)
+	if payload.FetchFailed {
+		return p, t.deleteRemovedPage(ctx, payload)
+	}
 
 	doc := &index.Document{
 		LinkID:    payload.LinkID,
@@ -28,8 +53,95 @@ func (t *textIndexer) Process(ctx context.Context, p pipeline.Payload) (pipeline
 		IndexedAt: time.Now(),
 	}
 
-	if err := t.indexer.Index(doc); err != nil {
+	if err := t.add(ctx, doc); err != nil {
 		return nil, err
 	}
 	return p, nil
 }
+
+// add appends doc to the pending batch and flushes the batch if it is full
+// or has been pending for longer than the flush interval.
+func (t *textIndexer) add(ctx context.Context, doc *index.Document) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.pending) == 0 {
+		t.pendingSince = time.Now()
+	}
+	t.pending = append(t.pending, doc)
+
+	if len(t.pending) < t.batchSize && (t.flushInterval <= 0 || time.Since(t.pendingSince) < t.flushInterval) {
+		return nil
+	}
+	return t.flushLocked(ctx)
+}
+
+// flush indexes any pending documents.
+func (t *textIndexer) flush(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.flushLocked(ctx)
+}
+
+// flushLocked indexes the pending documents. The caller must hold t.mu.
+// Documents that are rejected by the indexer are logged and skipped; an error
+// is only returned if the batch could not be indexed at all.
+func (t *textIndexer) flushLocked(ctx context.Context) error {
+	if len(t.pending) == 0 {
+		return nil
+	}
+	batch := t.pending
+	t.pending = nil
+
+	err := t.indexer.IndexBatch(ctx, batch)
+	if err == nil {
+		return nil
+	}
+
+	var batchErr *index.BatchError
+	if xerrors.As(err, &batchErr) {
+		for pos, docErr := range batchErr.Errors {
+			fields := logrus.Fields{"err": docErr}
+			if pos >= 0 && pos < len(batch) {
+				fields["link_id"] = batch[pos].LinkID.String()
+				fields["url"] = batch[pos].URL
+			}
+			t.logger.WithFields(fields).Warn("skipping document that could not be indexed")
+		}
+		return nil
+	}
+
+	t.logger.WithFields(logrus.Fields{
+		"err":       err,
+		"doc_count": len(batch),
+	}).Error("dropping batch of documents that could not be indexed")
+	return xerrors.Errorf("text indexer: %w", err)
+}
+
+// deleteRemovedPage deletes the document of a previously indexed page that has
+// been removed for good so that it no longer shows up in search results.
+// Pages that have never been retrieved successfully are skipped as they have
+// no document. Failed fetches do not update the retrieval time of a link, so
+// the document is also deleted if the page was removed after one or more
+// transient failures; deleting a document that is already gone is a no-op.
+func (t *textIndexer) deleteRemovedPage(ctx context.Context, payload *crawlerPayload) error {
+	if payload.RetrievedAt.IsZero() || !isPermanentFailure(payload.StatusCode) {
+		return nil
+	}
+
+	if err := t.indexer.Delete(ctx, payload.LinkID); err != nil && !xerrors.Is(err, index.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+// isPermanentFailure returns true if the HTTP status code of a failed fetch
+// attempt indicates that the page has been removed for good.
+func isPermanentFailure(statusCode int) bool {
+	switch statusCode {
+	case http.StatusNotFound, http.StatusGone, http.StatusUnavailableForLegalReasons:
+		return true
+	default:
+		return false
+	}
+}
@@ -7,4 +7,17 @@ var (
 	ErrNotFound = xerrors.New("not found")
 
 	ErrUnknownEdgeLinks = xerrors.New("unknown source and/or destination or edge")
+
+	// ErrLinkDeleted is returned when attempting to upsert a link whose URL
+	// has been tombstoned by a recent call to DeleteLink.
+	ErrLinkDeleted = xerrors.New("link has been deleted")
+
+	// ErrIDConflict is returned when restoring a link or edge that already
+	// exists in the graph with a different ID or when creating a link with a
+	// caller-supplied ID that is already assigned to another link.
+	ErrIDConflict = xerrors.New("link or edge already exists with a different ID")
+
+	// ErrEdgeHistoryDisabled is returned when querying the edge history of a
+	// store that has not been configured to record it.
+	ErrEdgeHistoryDisabled = xerrors.New("edge history is disabled")
 )
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"time"
 )
@@ -33,10 +34,56 @@ type EdgeIterator interface {
 	Edge() *Edge
 }
 
+// TombstoneTTL specifies how long a deleted link's URL is remembered by the
+// graph. While the tombstone is active, attempts to upsert a link with the
+// same URL fail with ErrLinkDeleted.
+const TombstoneTTL = 30 * 24 * time.Hour
+
 type Link struct {
-	ID          uuid.UUID
-	URL         string
+	ID  uuid.UUID
+	URL string
+	// RetrievedAt is the time the link was last fetched successfully or the
+	// zero value if it has never been fetched.
 	RetrievedAt time.Time
+
+	// The following fields describe the outcome of the last crawl attempt.
+	// Stores only replace them when the upserted link carries a crawl
+	// result (see HasCrawlResult) whose RetrievedAt value is not older than
+	// the one already stored so that links discovered while crawling other
+	// pages do not reset them.
+
+	// StatusCode is the HTTP status code returned by the last fetch or 0 if
+	// the fetch failed before a response was received.
+	StatusCode int
+	// ContentHash is the hex-encoded SHA-256 digest of the fetched content.
+	ContentHash string
+	// ETag and LastModified hold the values of the respective HTTP response
+	// headers.
+	ETag         string
+	LastModified string
+	// FailureCount is the number of consecutive failed crawl attempts.
+	FailureCount int
+	// NextCrawlAt is the earliest time the link should be crawled again.
+	// Links submitted for crawling are created with NextCrawlAt set to the
+	// submission time while links discovered by the crawler leave it unset;
+	// the crawler only crawls the former as soon as they are created.
+	NextCrawlAt time.Time
+}
+
+// HasCrawlResult returns true if the link carries the outcome of a crawl
+// attempt. Failed attempts leave RetrievedAt unset, so a link that has never
+// been fetched successfully still carries a crawl result if any of its
+// StatusCode, FailureCount or NextCrawlAt fields are set.
+func (l *Link) HasCrawlResult() bool {
+	return !l.RetrievedAt.IsZero() || l.StatusCode != 0 || l.FailureCount != 0 || !l.NextCrawlAt.IsZero()
+}
+
+// LinkIDForURL returns a name-based (version 5) UUID derived from a link URL.
+// Stores that are configured to use URL-derived IDs assign it to new links so
+// that clients can compute the ID of a link without querying the graph. URLs
+// are used verbatim; callers must canonicalize them before upserting links.
+func LinkIDForURL(url string) uuid.UUID {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(url))
 }
 
 type Edge struct {
@@ -44,25 +91,100 @@ type Edge struct {
 	Src       uuid.UUID
 	Dst       uuid.UUID
 	UpdatedAt time.Time
+
+	// AnchorText is the text of the anchor that links Src to Dst and Rel
+	// holds the value of the anchor's rel attribute. Both fields are
+	// replaced each time the edge is upserted.
+	AnchorText string
+	Rel        string
+}
+
+// Restorer is implemented by graph stores that can insert links and edges
+// while preserving their IDs. It is used for restoring graph snapshots.
+type Restorer interface {
+	// RestoreLinks inserts or replaces a batch of links keeping their IDs.
+	RestoreLinks(ctx context.Context, links []*Link) error
+	// RestoreEdges inserts or replaces a batch of edges keeping their IDs
+	// and update timestamps.
+	RestoreEdges(ctx context.Context, edges []*Edge) error
 }
 
+// EdgeRecord describes the lifetime of an edge between two links.
+type EdgeRecord struct {
+	Src uuid.UUID
+	Dst uuid.UUID
+
+	// FirstSeenAt is the time the edge was first upserted.
+	FirstSeenAt time.Time
+	// RemovedAt is the time the edge was removed from the graph or the
+	// zero time if the edge still exists.
+	RemovedAt time.Time
+}
+
+// EdgeHistorian is implemented by graph stores that can keep a record of the
+// edges that were added to and removed from the graph. Stores only record
+// edge history when it has been enabled via their configuration.
+type EdgeHistorian interface {
+	// EdgeTimeline returns the history of the edges originating from the
+	// specified link ID ordered by their first-seen time. An edge that was
+	// removed and later added back is reported as two separate records.
+	// ErrEdgeHistoryDisabled is returned if the store does not record edge
+	// history.
+	EdgeTimeline(ctx context.Context, srcID uuid.UUID) ([]*EdgeRecord, error)
+}
+
+// Graph is implemented by link graph stores. Cancelling the context passed to
+// a method aborts any store or network call that is still in flight. Watchers
+// are closed once the context used to create them is cancelled.
 type Graph interface {
-	// UpsertLink creates a new Link or update an existing link
-	UpsertLink(link *Link) error
+	// UpsertLink creates a new Link or update an existing link. New links
+	// keep their ID if one is specified; otherwise a new ID is assigned.
+	UpsertLink(ctx context.Context, link *Link) error
+	// UpsertLinks creates or updates a batch of links. Links whose URL has
+	// been tombstoned are skipped and have their ID set to uuid.Nil.
+	UpsertLinks(ctx context.Context, links []*Link) error
 	// FindLink looks up s link by its ID.
-	FindLink(id uuid.UUID) (*Link, error)
+	FindLink(ctx context.Context, id uuid.UUID) (*Link, error)
+	// FindLinkByURL looks up a link by its URL.
+	FindLinkByURL(ctx context.Context, url string) (*Link, error)
+	// DeleteLink removes a link together with all edges that originate from
+	// or point to it and records a tombstone for the link's URL.
+	DeleteLink(ctx context.Context, id uuid.UUID) error
 
 	// UpsertEdge creates a new edge or updates an existing edge
-	UpsertEdge(edge *Edge) error
+	UpsertEdge(ctx context.Context, edge *Edge) error
+	// UpsertEdges creates or updates a batch of edges.
+	UpsertEdges(ctx context.Context, edges []*Edge) error
 	// RemoveStaleEdges removes any edge that originates from the specified
 	// link ID and was updated before the specified timestamp.
-	RemoveStaleEdges(fromID uuid.UUID, updateBefore time.Time) error
+	RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updateBefore time.Time) error
 
 	// Links returns an iterator for the set of links whose IDs belong to the
 	// [fromID, toID) range and were retrieved before the provided timestamp.
-	Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (LinkIterator, error)
+	Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (LinkIterator, error)
 	// Edges returns an iterator for the set of edges whose source vertex IDs
 	// belong to the [fromID, toID) range and were updated before the provided
 	// timestamp.
-	Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (EdgeIterator, error)
+	Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (EdgeIterator, error)
+
+	// EdgesTo returns an iterator for the set of edges whose destination
+	// vertex is the specified link ID.
+	EdgesTo(ctx context.Context, dstID uuid.UUID) (EdgeIterator, error)
+	// InboundDegree returns the number of edges whose destination vertex is
+	// the specified link ID.
+	InboundDegree(ctx context.Context, dstID uuid.UUID) (int, error)
+
+	// WatchLinks returns a watcher that receives an event for each link that
+	// is created, updated or deleted after the call returns.
+	WatchLinks(ctx context.Context) (LinkWatcher, error)
+
+	// UpsertHost creates a new host or updates the robots.txt, crawl delay
+	// and block settings of an existing host. The fetch statistics of
+	// existing hosts are preserved and copied into host.
+	UpsertHost(ctx context.Context, host *Host) error
+	// FindHost looks up a host by its name.
+	FindHost(ctx context.Context, name string) (*Host, error)
+	// RecordHostFetch records a fetch attempt for the specified host,
+	// creating the host if it does not exist.
+	RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error
 }
@@ -0,0 +1,58 @@
+package graph
+
+import (
+	"golang.org/x/xerrors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Host describes the crawl state shared by all links that point to the same
+// host. Links are mapped to their host using HostName.
+type Host struct {
+	// Name is the lower-cased host part of a URL including the port, if
+	// one is specified.
+	Name string
+
+	// The following fields are replaced by UpsertHost.
+
+	// RobotsTxt holds the contents of the host's robots.txt file as of
+	// RobotsFetchedAt.
+	RobotsTxt       string
+	RobotsFetchedAt time.Time
+	// CrawlDelay is the minimum time between subsequent requests to the
+	// host.
+	CrawlDelay time.Duration
+	// Blocked prevents links pointing to the host from being crawled or
+	// submitted. BlockReason optionally describes why the host was blocked.
+	Blocked     bool
+	BlockReason string
+
+	// The following fields are maintained by RecordHostFetch.
+
+	// LastContactedAt is the time of the last request to the host.
+	LastContactedAt time.Time
+	// FetchCount and ErrorCount track the total number of fetch attempts
+	// and how many of them failed.
+	FetchCount int
+	ErrorCount int
+}
+
+// ErrorRate returns the fraction of fetch attempts for the host that failed.
+func (h *Host) ErrorRate() float64 {
+	if h.FetchCount == 0 {
+		return 0
+	}
+	return float64(h.ErrorCount) / float64(h.FetchCount)
+}
+
+// HostName returns the name of the host that the specified link URL points to.
+func HostName(linkURL string) (string, error) {
+	u, err := url.Parse(linkURL)
+	if err != nil {
+		return "", xerrors.Errorf("host name: %w", err)
+	} else if u.Host == "" {
+		return "", xerrors.Errorf("host name: URL %q does not specify a host", linkURL)
+	}
+	return strings.ToLower(u.Host), nil
+}
@@ -1,6 +1,7 @@
 package graph
 
 import (
+	"context"
 	"fmt"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
@@ -30,7 +31,7 @@ func (s *SuiteBase) TestUpsertLink(c *gc.C) {
 		RetrievedAt: time.Now().Add(-10 * time.Hour),
 	}
 
-	err := s.g.UpsertLink(original)
+	err := s.g.UpsertLink(context.Background(), original)
 	c.Assert(err, gc.IsNil)
 	c.Assert(original.ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected a linkID to be assigned to the new link"))
 
@@ -41,11 +42,11 @@ func (s *SuiteBase) TestUpsertLink(c *gc.C) {
 		URL:         "https://example.com",
 		RetrievedAt: accessedAt,
 	}
-	err = s.g.UpsertLink(existing)
+	err = s.g.UpsertLink(context.Background(), existing)
 	c.Assert(err, gc.IsNil)
 	c.Assert(existing.ID, gc.Equals, original.ID, gc.Commentf("link ID changed while upserting"))
 
-	stored, err := s.g.FindLink(existing.ID)
+	stored, err := s.g.FindLink(context.Background(), existing.ID)
 	c.Assert(err, gc.IsNil)
 	c.Assert(stored.RetrievedAt, gc.Equals, accessedAt, gc.Commentf("last accessed timestamp was not updated"))
 
@@ -55,11 +56,11 @@ func (s *SuiteBase) TestUpsertLink(c *gc.C) {
 		URL:         existing.URL,
 		RetrievedAt: time.Now().Add(-10 * time.Hour).UTC(),
 	}
-	err = s.g.UpsertLink(sameURL)
+	err = s.g.UpsertLink(context.Background(), sameURL)
 	c.Assert(err, gc.IsNil)
 	c.Assert(sameURL.ID, gc.Equals, existing.ID)
 
-	stored, err = s.g.FindLink(existing.ID)
+	stored, err = s.g.FindLink(context.Background(), existing.ID)
 	c.Assert(err, gc.IsNil)
 	c.Assert(stored.RetrievedAt, gc.Equals, accessedAt, gc.Commentf("last accessed timestamp was overwritten with an older value"))
 
@@ -68,11 +69,119 @@ func (s *SuiteBase) TestUpsertLink(c *gc.C) {
 	dup := &Link{
 		URL: "foo",
 	}
-	err = s.g.UpsertLink(dup)
+	err = s.g.UpsertLink(context.Background(), dup)
 	c.Assert(err, gc.IsNil)
 	c.Assert(dup.ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected a linkID to be assigned to the new link"))
 }
 
+// TestUpsertLinkWithID verifies that new links keep a caller-supplied ID.
+func (s *SuiteBase) TestUpsertLinkWithID(c *gc.C) {
+	id := uuid.New()
+	link := &Link{ID: id, URL: "https://example.com"}
+	c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
+	c.Assert(link.ID, gc.Equals, id)
+
+	stored, err := s.g.FindLink(context.Background(), id)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored.URL, gc.Equals, link.URL)
+
+	// Upserting an existing URL with a different ID returns the stored ID.
+	sameURL := &Link{ID: uuid.New(), URL: link.URL}
+	c.Assert(s.g.UpsertLink(context.Background(), sameURL), gc.IsNil)
+	c.Assert(sameURL.ID, gc.Equals, id)
+
+	// IDs cannot be reused for a different URL.
+	err = s.g.UpsertLink(context.Background(), &Link{ID: id, URL: "https://example.com/other"})
+	c.Assert(xerrors.Is(err, ErrIDConflict), gc.Equals, true)
+
+	batch := []*Link{{ID: uuid.New(), URL: "https://example.com/a"}, {URL: "https://example.com/b"}}
+	wantID := batch[0].ID
+	c.Assert(s.g.UpsertLinks(context.Background(), batch), gc.IsNil)
+	c.Assert(batch[0].ID, gc.Equals, wantID)
+	c.Assert(batch[1].ID, gc.Not(gc.Equals), uuid.Nil)
+}
+
+// TestUpsertLinkCrawlDetails verifies that the crawl details of a link are
+// persisted and never overwritten by an upsert with an older timestamp.
+func (s *SuiteBase) TestUpsertLinkCrawlDetails(c *gc.C) {
+	retrievedAt := time.Now().Truncate(time.Second).UTC()
+	crawled := &Link{
+		URL:          "https://example.com",
+		RetrievedAt:  retrievedAt,
+		StatusCode:   404,
+		ContentHash:  "deadbeef",
+		ETag:         `"abc"`,
+		LastModified: "Mon, 02 Jan 2006 15:04:05 GMT",
+		FailureCount: 2,
+		NextCrawlAt:  retrievedAt.Add(time.Hour),
+	}
+	c.Assert(s.g.UpsertLink(context.Background(), crawled), gc.IsNil)
+
+	stored, err := s.g.FindLink(context.Background(), crawled.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, crawled)
+
+	// Upserting the same URL without any crawl details (e.g. when the link
+	// is discovered while crawling another page) must not reset them.
+	discovered := &Link{URL: crawled.URL}
+	c.Assert(s.g.UpsertLink(context.Background(), discovered), gc.IsNil)
+	c.Assert(discovered, gc.DeepEquals, crawled, gc.Commentf("expected upsert to return the stored crawl details"))
+
+	stored, err = s.g.FindLink(context.Background(), crawled.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, crawled, gc.Commentf("crawl details were overwritten with older values"))
+
+	// A more recent crawl attempt replaces the crawl details.
+	recrawled := &Link{
+		URL:         crawled.URL,
+		RetrievedAt: retrievedAt.Add(time.Minute),
+		StatusCode:  200,
+		ContentHash: "cafebabe",
+	}
+	c.Assert(s.g.UpsertLink(context.Background(), recrawled), gc.IsNil)
+
+	stored, err = s.g.FindLink(context.Background(), crawled.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, recrawled)
+}
+
+// TestUpsertLinkFailureDetails verifies that rediscovering a link that has
+// never been fetched successfully does not reset its failure details.
+func (s *SuiteBase) TestUpsertLinkFailureDetails(c *gc.C) {
+	failed := &Link{
+		URL:          "https://example.com/unreachable",
+		FailureCount: 3,
+		NextCrawlAt:  time.Now().Add(time.Hour).Truncate(time.Second).UTC(),
+	}
+	c.Assert(s.g.UpsertLink(context.Background(), failed), gc.IsNil)
+
+	discovered := &Link{URL: failed.URL}
+	c.Assert(s.g.UpsertLink(context.Background(), discovered), gc.IsNil)
+	c.Assert(discovered, gc.DeepEquals, failed, gc.Commentf("expected upsert to return the stored failure details"))
+
+	stored, err := s.g.FindLink(context.Background(), failed.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, failed, gc.Commentf("failure details were reset by the rediscovered link"))
+
+	// The same applies to links rediscovered as part of a batch.
+	discovered = &Link{URL: failed.URL}
+	c.Assert(s.g.UpsertLinks(context.Background(), []*Link{discovered}), gc.IsNil)
+	c.Assert(discovered, gc.DeepEquals, failed)
+
+	// A further failed attempt replaces the failure details.
+	refailed := &Link{
+		URL:          failed.URL,
+		StatusCode:   503,
+		FailureCount: 4,
+		NextCrawlAt:  failed.NextCrawlAt.Add(time.Hour),
+	}
+	c.Assert(s.g.UpsertLinks(context.Background(), []*Link{refailed, {URL: failed.URL}}), gc.IsNil)
+
+	stored, err = s.g.FindLink(context.Background(), failed.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored, gc.DeepEquals, refailed)
+}
+
 // TestFindLink verifies the link lookup logic.
 func (s *SuiteBase) TestFindLink(c *gc.C) {
 	// Create a new link
@@ -81,20 +190,75 @@ func (s *SuiteBase) TestFindLink(c *gc.C) {
 		RetrievedAt: time.Now().Truncate(time.Second).UTC(),
 	}
 
-	err := s.g.UpsertLink(link)
+	err := s.g.UpsertLink(context.Background(), link)
 	c.Assert(err, gc.IsNil)
 	c.Assert(link.ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected a linkID to be assigned to the new link"))
 
 	// Lookup link by ID
-	other, err := s.g.FindLink(link.ID)
+	other, err := s.g.FindLink(context.Background(), link.ID)
 	c.Assert(err, gc.IsNil)
 	c.Assert(other, gc.DeepEquals, link, gc.Commentf("lookup by ID returned the wrong link"))
 
 	// Lookup link by unknown ID
-	_, err = s.g.FindLink(uuid.Nil)
+	_, err = s.g.FindLink(context.Background(), uuid.Nil)
 	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
 }
 
+// TestFindLinkByURL verifies the link lookup by URL logic.
+func (s *SuiteBase) TestFindLinkByURL(c *gc.C) {
+	link := &Link{
+		URL:         "https://example.com",
+		RetrievedAt: time.Now().Truncate(time.Second).UTC(),
+		StatusCode:  200,
+	}
+	c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
+
+	// Lookup link by URL
+	other, err := s.g.FindLinkByURL(context.Background(), link.URL)
+	c.Assert(err, gc.IsNil)
+	c.Assert(other, gc.DeepEquals, link, gc.Commentf("lookup by URL returned the wrong link"))
+
+	// Lookup link by unknown URL
+	_, err = s.g.FindLinkByURL(context.Background(), "https://example.com/unknown")
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+
+	// Deleted links can no longer be looked up
+	c.Assert(s.g.DeleteLink(context.Background(), link.ID), gc.IsNil)
+	_, err = s.g.FindLinkByURL(context.Background(), link.URL)
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+}
+
+// TestUpsertLinks verifies the batch link upsert logic.
+func (s *SuiteBase) TestUpsertLinks(c *gc.C) {
+	existing := &Link{URL: "https://example.com/existing"}
+	c.Assert(s.g.UpsertLink(context.Background(), existing), gc.IsNil)
+
+	deleted := &Link{URL: "https://example.com/deleted"}
+	c.Assert(s.g.UpsertLink(context.Background(), deleted), gc.IsNil)
+	c.Assert(s.g.DeleteLink(context.Background(), deleted.ID), gc.IsNil)
+
+	retrievedAt := time.Now().Truncate(time.Second).UTC()
+	batch := []*Link{
+		{URL: "https://example.com/new"},
+		{URL: existing.URL, RetrievedAt: retrievedAt},
+		{URL: deleted.URL},
+		{URL: "https://example.com/new"},
+	}
+	c.Assert(s.g.UpsertLinks(context.Background(), batch), gc.IsNil)
+
+	c.Assert(batch[0].ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected a linkID to be assigned to the new link"))
+	c.Assert(batch[3].ID, gc.Equals, batch[0].ID, gc.Commentf("duplicate links within a batch should share the same ID"))
+	c.Assert(batch[1].ID, gc.Equals, existing.ID, gc.Commentf("link ID changed while upserting"))
+	c.Assert(batch[2].ID, gc.Equals, uuid.Nil, gc.Commentf("expected deleted link to be skipped"))
+
+	stored, err := s.g.FindLink(context.Background(), existing.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(stored.RetrievedAt, gc.Equals, retrievedAt, gc.Commentf("last accessed timestamp was not updated"))
+
+	// Upserting an empty batch is a no-op
+	c.Assert(s.g.UpsertLinks(context.Background(), nil), gc.IsNil)
+}
+
 // TestConcurrentLinkIterators verifies that multiple clients can concurrently
 // access the store.
 func (s *SuiteBase) TestConcurrentLinkIterators(c *gc.C) {
@@ -106,7 +270,7 @@ func (s *SuiteBase) TestConcurrentLinkIterators(c *gc.C) {
 
 	for i := 0; i < numLinks; i++ {
 		link := &Link{URL: fmt.Sprint(i)}
-		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
 	}
 
 	wg.Add(numIterators)
@@ -156,7 +320,7 @@ func (s *SuiteBase) TestLinkIteratorTimeFilter(c *gc.C) {
 	linkInsertTimes := make([]time.Time, len(linkUUIDs))
 	for i := 0; i < len(linkUUIDs); i++ {
 		link := &Link{URL: fmt.Sprint(i), RetrievedAt: time.Now()}
-		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
 		linkUUIDs[i] = link.ID
 		linkInsertTimes[i] = time.Now()
 	}
@@ -189,7 +353,7 @@ func (s *SuiteBase) TestPartitionedLinkIterators(c *gc.C) {
 	numLinks := 100
 	numPartitions := 10
 	for i := 0; i < numLinks; i++ {
-		c.Assert(s.g.UpsertLink(&Link{URL: fmt.Sprint(i)}), gc.IsNil)
+		c.Assert(s.g.UpsertLink(context.Background(), &Link{URL: fmt.Sprint(i)}), gc.IsNil)
 	}
 
 	// Check with both odd and even partition counts to check for rounding-related bugs.
@@ -226,7 +390,7 @@ func (s *SuiteBase) TestUpsertEdge(c *gc.C) {
 	linkUUIDs := make([]uuid.UUID, 3)
 	for i := 0; i < 3; i++ {
 		link := &Link{URL: fmt.Sprint(i)}
-		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
 		linkUUIDs[i] = link.ID
 	}
 
@@ -236,7 +400,7 @@ func (s *SuiteBase) TestUpsertEdge(c *gc.C) {
 		Dst: linkUUIDs[1],
 	}
 
-	err := s.g.UpsertEdge(edge)
+	err := s.g.UpsertEdge(context.Background(), edge)
 	c.Assert(err, gc.IsNil)
 	c.Assert(edge.ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected an edgeID to be assigned to the new edge"))
 	c.Assert(edge.UpdatedAt.IsZero(), gc.Equals, false, gc.Commentf("UpdatedAt field not set"))
@@ -247,7 +411,7 @@ func (s *SuiteBase) TestUpsertEdge(c *gc.C) {
 		Src: linkUUIDs[0],
 		Dst: linkUUIDs[1],
 	}
-	err = s.g.UpsertEdge(other)
+	err = s.g.UpsertEdge(context.Background(), other)
 	c.Assert(err, gc.IsNil)
 	c.Assert(other.ID, gc.Equals, edge.ID, gc.Commentf("edge ID changed while upserting"))
 	c.Assert(other.UpdatedAt, gc.Not(gc.Equals), edge.UpdatedAt, gc.Commentf("UpdatedAt field not modified"))
@@ -257,10 +421,72 @@ func (s *SuiteBase) TestUpsertEdge(c *gc.C) {
 		Src: linkUUIDs[0],
 		Dst: uuid.New(),
 	}
-	err = s.g.UpsertEdge(bogus)
+	err = s.g.UpsertEdge(context.Background(), bogus)
 	c.Assert(xerrors.Is(err, ErrUnknownEdgeLinks), gc.Equals, true)
 }
 
+// TestUpsertEdges verifies the batch edge upsert logic.
+func (s *SuiteBase) TestUpsertEdges(c *gc.C) {
+	links := make([]*Link, 3)
+	for i := range links {
+		links[i] = &Link{URL: fmt.Sprint(i)}
+	}
+	c.Assert(s.g.UpsertLinks(context.Background(), links), gc.IsNil)
+
+	existing := &Edge{Src: links[0].ID, Dst: links[1].ID}
+	c.Assert(s.g.UpsertEdge(context.Background(), existing), gc.IsNil)
+
+	batch := []*Edge{
+		{Src: links[0].ID, Dst: links[1].ID},
+		{Src: links[0].ID, Dst: links[2].ID},
+		{Src: links[1].ID, Dst: links[2].ID},
+		{Src: links[0].ID, Dst: links[2].ID},
+	}
+	c.Assert(s.g.UpsertEdges(context.Background(), batch), gc.IsNil)
+
+	c.Assert(batch[0].ID, gc.Equals, existing.ID, gc.Commentf("edge ID changed while upserting"))
+	c.Assert(batch[0].UpdatedAt.After(existing.UpdatedAt), gc.Equals, true, gc.Commentf("UpdatedAt field not modified"))
+	for i, edge := range batch {
+		c.Assert(edge.ID, gc.Not(gc.Equals), uuid.Nil, gc.Commentf("expected an edgeID to be assigned to edge %d", i))
+		c.Assert(edge.UpdatedAt.IsZero(), gc.Equals, false, gc.Commentf("UpdatedAt field not set for edge %d", i))
+	}
+	c.Assert(batch[3].ID, gc.Equals, batch[1].ID, gc.Commentf("duplicate edges within a batch should share the same ID"))
+	s.assertIteratedEdgeIDsMatch(c, time.Now(), []uuid.UUID{batch[0].ID, batch[1].ID, batch[2].ID})
+
+	// Batches with unknown link IDs
+	err := s.g.UpsertEdges(context.Background(), []*Edge{{Src: links[0].ID, Dst: uuid.New()}})
+	c.Assert(xerrors.Is(err, ErrUnknownEdgeLinks), gc.Equals, true)
+}
+
+// TestEdgeAnchorDetails verifies that the anchor text and rel attribute of
+// edges are persisted and replaced by subsequent upserts.
+func (s *SuiteBase) TestEdgeAnchorDetails(c *gc.C) {
+	links := make([]*Link, 3)
+	for i := range links {
+		links[i] = &Link{URL: fmt.Sprint(i)}
+	}
+	c.Assert(s.g.UpsertLinks(context.Background(), links), gc.IsNil)
+
+	c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: links[0].ID, Dst: links[2].ID, AnchorText: "old text", Rel: "nofollow"}), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: links[0].ID, Dst: links[2].ID, AnchorText: "first"}), gc.IsNil)
+	c.Assert(s.g.UpsertEdges(context.Background(), []*Edge{{Src: links[1].ID, Dst: links[2].ID, AnchorText: "second", Rel: "external"}}), gc.IsNil)
+
+	it, err := s.g.EdgesTo(context.Background(), links[2].ID)
+	c.Assert(err, gc.IsNil)
+	got := make(map[uuid.UUID][2]string)
+	for it.Next() {
+		edge := it.Edge()
+		got[edge.Src] = [2]string{edge.AnchorText, edge.Rel}
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+
+	c.Assert(got, gc.DeepEquals, map[uuid.UUID][2]string{
+		links[0].ID: {"first", ""},
+		links[1].ID: {"second", "external"},
+	})
+}
+
 // TestConcurrentEdgeIterators verifies that multiple clients can concurrently
 // access the store.
 func (s *SuiteBase) TestConcurrentEdgeIterators(c *gc.C) {
@@ -273,11 +499,11 @@ func (s *SuiteBase) TestConcurrentEdgeIterators(c *gc.C) {
 
 	for i := 0; i < numEdges*2; i++ {
 		link := &Link{URL: fmt.Sprint(i)}
-		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
 		linkUUIDs[i] = link.ID
 	}
 	for i := 0; i < numEdges; i++ {
-		c.Assert(s.g.UpsertEdge(&Edge{
+		c.Assert(s.g.UpsertEdge(context.Background(), &Edge{
 			Src: linkUUIDs[0],
 			Dst: linkUUIDs[i],
 		}), gc.IsNil)
@@ -330,7 +556,7 @@ func (s *SuiteBase) TestEdgeIteratorTimeFilter(c *gc.C) {
 	linkInsertTimes := make([]time.Time, len(linkUUIDs))
 	for i := 0; i < len(linkUUIDs); i++ {
 		link := &Link{URL: fmt.Sprint(i)}
-		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
 		linkUUIDs[i] = link.ID
 		linkInsertTimes[i] = time.Now()
 	}
@@ -339,7 +565,7 @@ func (s *SuiteBase) TestEdgeIteratorTimeFilter(c *gc.C) {
 	edgeInsertTimes := make([]time.Time, len(linkUUIDs))
 	for i := 0; i < len(linkUUIDs); i++ {
 		edge := &Edge{Src: linkUUIDs[0], Dst: linkUUIDs[i]}
-		c.Assert(s.g.UpsertEdge(edge), gc.IsNil)
+		c.Assert(s.g.UpsertEdge(context.Background(), edge), gc.IsNil)
 		edgeUUIDs[i] = edge.ID
 		edgeInsertTimes[i] = time.Now()
 	}
@@ -374,11 +600,11 @@ func (s *SuiteBase) TestPartitionedEdgeIterators(c *gc.C) {
 	linkUUIDs := make([]uuid.UUID, numEdges*2)
 	for i := 0; i < numEdges*2; i++ {
 		link := &Link{URL: fmt.Sprint(i)}
-		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
 		linkUUIDs[i] = link.ID
 	}
 	for i := 0; i < numEdges; i++ {
-		c.Assert(s.g.UpsertEdge(&Edge{
+		c.Assert(s.g.UpsertEdge(context.Background(), &Edge{
 			Src: linkUUIDs[0],
 			Dst: linkUUIDs[i],
 		}), gc.IsNil)
@@ -432,7 +658,7 @@ func (s *SuiteBase) TestRemoveStaleEdges(c *gc.C) {
 	goneUUIDs := make(map[uuid.UUID]struct{})
 	for i := 0; i < numEdges*4; i++ {
 		link := &Link{URL: fmt.Sprint(i)}
-		c.Assert(s.g.UpsertLink(link), gc.IsNil)
+		c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
 		linkUUIDs[i] = link.ID
 	}
 
@@ -442,7 +668,7 @@ func (s *SuiteBase) TestRemoveStaleEdges(c *gc.C) {
 			Src: linkUUIDs[0],
 			Dst: linkUUIDs[i],
 		}
-		c.Assert(s.g.UpsertEdge(e1), gc.IsNil)
+		c.Assert(s.g.UpsertEdge(context.Background(), e1), gc.IsNil)
 		goneUUIDs[e1.ID] = struct{}{}
 		lastTs = e1.UpdatedAt
 	}
@@ -456,9 +682,9 @@ func (s *SuiteBase) TestRemoveStaleEdges(c *gc.C) {
 			Src: linkUUIDs[0],
 			Dst: linkUUIDs[numEdges+i+1],
 		}
-		c.Assert(s.g.UpsertEdge(e2), gc.IsNil)
+		c.Assert(s.g.UpsertEdge(context.Background(), e2), gc.IsNil)
 	}
-	c.Assert(s.g.RemoveStaleEdges(linkUUIDs[0], deleteBefore), gc.IsNil)
+	c.Assert(s.g.RemoveStaleEdges(context.Background(), linkUUIDs[0], deleteBefore), gc.IsNil)
 
 	it, err := s.partitionedEdgeIterator(c, 0, 1, time.Now())
 	c.Assert(err, gc.IsNil)
@@ -475,14 +701,318 @@ func (s *SuiteBase) TestRemoveStaleEdges(c *gc.C) {
 	c.Assert(seen, gc.Equals, numEdges)
 }
 
+// TestEdgesTo verifies that the edges pointing to a link can be looked up by
+// their destination and that the inbound degree is kept up to date.
+func (s *SuiteBase) TestEdgesTo(c *gc.C) {
+	numSources := 10
+	target := &Link{URL: "target"}
+	c.Assert(s.g.UpsertLink(context.Background(), target), gc.IsNil)
+	other := &Link{URL: "other"}
+	c.Assert(s.g.UpsertLink(context.Background(), other), gc.IsNil)
+
+	expEdges := make(map[uuid.UUID]uuid.UUID)
+	for i := 0; i < numSources; i++ {
+		src := &Link{URL: fmt.Sprint(i)}
+		c.Assert(s.g.UpsertLink(context.Background(), src), gc.IsNil)
+
+		edge := &Edge{Src: src.ID, Dst: target.ID}
+		c.Assert(s.g.UpsertEdge(context.Background(), edge), gc.IsNil)
+		expEdges[edge.ID] = src.ID
+
+		// Upserting an existing edge must not affect the inbound degree.
+		c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: src.ID, Dst: target.ID}), gc.IsNil)
+
+		// Edges to other links must not be returned.
+		c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: src.ID, Dst: other.ID}), gc.IsNil)
+	}
+
+	s.assertEdgesTo(c, target.ID, expEdges)
+	degree, err := s.g.InboundDegree(context.Background(), target.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(degree, gc.Equals, numSources)
+
+	// Remove the edges of one of the source links; the removed edge should
+	// no longer be returned.
+	var staleSrcID uuid.UUID
+	for edgeID, srcID := range expEdges {
+		staleSrcID = srcID
+		delete(expEdges, edgeID)
+		break
+	}
+	time.Sleep(10 * time.Millisecond)
+	c.Assert(s.g.RemoveStaleEdges(context.Background(), staleSrcID, time.Now()), gc.IsNil)
+
+	s.assertEdgesTo(c, target.ID, expEdges)
+	degree, err = s.g.InboundDegree(context.Background(), target.ID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(degree, gc.Equals, numSources-1)
+
+	// Links without inbound edges
+	degree, err = s.g.InboundDegree(context.Background(), uuid.New())
+	c.Assert(err, gc.IsNil)
+	c.Assert(degree, gc.Equals, 0)
+}
+
+// TestDeleteLink verifies that deleting a link also removes its inbound and
+// outbound edges and prevents the link from being recreated.
+func (s *SuiteBase) TestDeleteLink(c *gc.C) {
+	target := &Link{URL: "target"}
+	c.Assert(s.g.UpsertLink(context.Background(), target), gc.IsNil)
+	src := &Link{URL: "src"}
+	c.Assert(s.g.UpsertLink(context.Background(), src), gc.IsNil)
+	dst := &Link{URL: "dst"}
+	c.Assert(s.g.UpsertLink(context.Background(), dst), gc.IsNil)
+
+	c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: src.ID, Dst: target.ID}), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: target.ID, Dst: dst.ID}), gc.IsNil)
+	keptEdge := &Edge{Src: src.ID, Dst: dst.ID}
+	c.Assert(s.g.UpsertEdge(context.Background(), keptEdge), gc.IsNil)
+
+	c.Assert(s.g.DeleteLink(context.Background(), target.ID), gc.IsNil)
+
+	_, err := s.g.FindLink(context.Background(), target.ID)
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+
+	// Only the edge between the remaining links should be left
+	s.assertIteratedEdgeIDsMatch(c, time.Now(), []uuid.UUID{keptEdge.ID})
+	s.assertEdgesTo(c, target.ID, map[uuid.UUID]uuid.UUID{})
+	s.assertEdgesTo(c, dst.ID, map[uuid.UUID]uuid.UUID{keptEdge.ID: src.ID})
+
+	// The deleted link must not be recreated while its tombstone is active
+	err = s.g.UpsertLink(context.Background(), &Link{URL: "target"})
+	c.Assert(xerrors.Is(err, ErrLinkDeleted), gc.Equals, true)
+
+	// Deleting an unknown link
+	err = s.g.DeleteLink(context.Background(), target.ID)
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+}
+
+// TestWatchLinks verifies that watchers are notified about link changes.
+func (s *SuiteBase) TestWatchLinks(c *gc.C) {
+	watcher, err := s.g.WatchLinks(context.Background())
+	c.Assert(err, gc.IsNil)
+
+	link := &Link{URL: "https://example.com"}
+	c.Assert(s.g.UpsertLink(context.Background(), link), gc.IsNil)
+	s.assertLinkEvent(c, watcher, LinkCreated, link)
+
+	batch := []*Link{{URL: link.URL}, {URL: "https://example.com/other"}}
+	c.Assert(s.g.UpsertLinks(context.Background(), batch), gc.IsNil)
+	s.assertLinkEvent(c, watcher, LinkUpdated, batch[0])
+	s.assertLinkEvent(c, watcher, LinkCreated, batch[1])
+
+	c.Assert(s.g.DeleteLink(context.Background(), link.ID), gc.IsNil)
+	s.assertLinkEvent(c, watcher, LinkDeleted, link)
+
+	// Closing the watcher should close the event channel
+	c.Assert(watcher.Close(), gc.IsNil)
+	for range watcher.Events() {
+	}
+	c.Assert(watcher.Error(), gc.IsNil)
+}
+
+// TestWatchLinksContext verifies that watchers are closed once their context
+// is cancelled.
+func (s *SuiteBase) TestWatchLinksContext(c *gc.C) {
+	ctx, cancelFn := context.WithCancel(context.Background())
+	watcher, err := s.g.WatchLinks(ctx)
+	c.Assert(err, gc.IsNil)
+
+	cancelFn()
+	select {
+	case _, ok := <-watcher.Events():
+		for ok {
+			_, ok = <-watcher.Events()
+		}
+	case <-time.After(5 * time.Second):
+		c.Fatal("timed out waiting for the watcher to be closed")
+	}
+	c.Assert(watcher.Close(), gc.IsNil)
+}
+
+func (s *SuiteBase) assertLinkEvent(c *gc.C, watcher LinkWatcher, expType LinkEventType, expLink *Link) {
+	select {
+	case ev, ok := <-watcher.Events():
+		c.Assert(ok, gc.Equals, true, gc.Commentf("event channel closed unexpectedly"))
+		c.Assert(ev.Type, gc.Equals, expType)
+		c.Assert(ev.Link.ID, gc.Equals, expLink.ID)
+		c.Assert(ev.Link.URL, gc.Equals, expLink.URL)
+	case <-time.After(5 * time.Second):
+		c.Fatalf("timed out waiting for link event %d", expType)
+	}
+}
+
+// TestRestore verifies that stores implementing Restorer preserve the IDs of
+// restored links and edges.
+func (s *SuiteBase) TestRestore(c *gc.C) {
+	r, ok := s.g.(Restorer)
+	if !ok {
+		c.Skip("graph does not implement Restorer")
+	}
+
+	retrievedAt := time.Now().Truncate(time.Second).UTC()
+	links := []*Link{
+		{ID: uuid.New(), URL: "https://example.com/a", RetrievedAt: retrievedAt, StatusCode: 200},
+		{ID: uuid.New(), URL: "https://example.com/b"},
+	}
+	c.Assert(r.RestoreLinks(context.Background(), links), gc.IsNil)
+
+	updatedAt := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	edge := &Edge{ID: uuid.New(), Src: links[0].ID, Dst: links[1].ID, UpdatedAt: updatedAt}
+	c.Assert(r.RestoreEdges(context.Background(), []*Edge{edge}), gc.IsNil)
+
+	for _, link := range links {
+		stored, err := s.g.FindLink(context.Background(), link.ID)
+		c.Assert(err, gc.IsNil)
+		c.Assert(stored, gc.DeepEquals, link)
+	}
+	s.assertEdgesTo(c, links[1].ID, map[uuid.UUID]uuid.UUID{edge.ID: links[0].ID})
+	s.assertIteratedEdgeIDsMatch(c, updatedAt.Add(time.Second), []uuid.UUID{edge.ID})
+
+	// Restoring the same data again is a no-op
+	c.Assert(r.RestoreLinks(context.Background(), links), gc.IsNil)
+	c.Assert(r.RestoreEdges(context.Background(), []*Edge{edge}), gc.IsNil)
+
+	// Restoring a link whose URL exists with a different ID
+	err := r.RestoreLinks(context.Background(), []*Link{{ID: uuid.New(), URL: links[0].URL}})
+	c.Assert(xerrors.Is(err, ErrIDConflict), gc.Equals, true)
+
+	// Restoring an edge whose ID is assigned to an edge between other links
+	err = r.RestoreEdges(context.Background(), []*Edge{{ID: edge.ID, Src: links[1].ID, Dst: links[0].ID}})
+	c.Assert(xerrors.Is(err, ErrIDConflict), gc.Equals, true)
+
+	// Restoring edges with unknown link IDs
+	err = r.RestoreEdges(context.Background(), []*Edge{{ID: uuid.New(), Src: links[0].ID, Dst: uuid.New()}})
+	c.Assert(xerrors.Is(err, ErrUnknownEdgeLinks), gc.Equals, true)
+}
+
+// TestEdgeTimeline verifies that stores implementing EdgeHistorian record
+// when edges are added to and removed from the graph.
+func (s *SuiteBase) TestEdgeTimeline(c *gc.C) {
+	h, ok := s.g.(EdgeHistorian)
+	if !ok {
+		c.Skip("graph does not implement EdgeHistorian")
+	}
+	if _, err := h.EdgeTimeline(context.Background(), uuid.New()); xerrors.Is(err, ErrEdgeHistoryDisabled) {
+		c.Skip("graph does not record edge history")
+	}
+
+	links := make([]*Link, 3)
+	for i := range links {
+		links[i] = &Link{URL: fmt.Sprintf("https://example.com/%d", i)}
+		c.Assert(s.g.UpsertLink(context.Background(), links[i]), gc.IsNil)
+	}
+	src, kept, removed := links[0].ID, links[1].ID, links[2].ID
+
+	c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: src, Dst: kept}), gc.IsNil)
+	c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: src, Dst: removed}), gc.IsNil)
+	s.assertEdgeTimeline(c, h, src, []uuid.UUID{kept, removed}, []bool{false, false})
+
+	// Refreshing an existing edge must not add a new record.
+	time.Sleep(10 * time.Millisecond)
+	staleBefore := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: src, Dst: kept}), gc.IsNil)
+	c.Assert(s.g.RemoveStaleEdges(context.Background(), src, staleBefore), gc.IsNil)
+	s.assertEdgeTimeline(c, h, src, []uuid.UUID{kept, removed}, []bool{false, true})
+
+	// Adding the edge back opens a new record.
+	c.Assert(s.g.UpsertEdge(context.Background(), &Edge{Src: src, Dst: removed}), gc.IsNil)
+	s.assertEdgeTimeline(c, h, src, []uuid.UUID{kept, removed, removed}, []bool{false, true, false})
+
+	// Deleting the destination link closes the records of its inbound edges.
+	c.Assert(s.g.DeleteLink(context.Background(), removed), gc.IsNil)
+	s.assertEdgeTimeline(c, h, src, []uuid.UUID{kept, removed, removed}, []bool{false, true, true})
+
+	// Links without any edges have an empty timeline.
+	s.assertEdgeTimeline(c, h, kept, nil, nil)
+}
+
+func (s *SuiteBase) assertEdgeTimeline(c *gc.C, h EdgeHistorian, srcID uuid.UUID, expDst []uuid.UUID, expRemoved []bool) {
+	records, err := h.EdgeTimeline(context.Background(), srcID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(records, gc.HasLen, len(expDst))
+
+	for i, rec := range records {
+		c.Assert(rec.Src, gc.Equals, srcID)
+		c.Assert(rec.Dst, gc.Equals, expDst[i], gc.Commentf("record %d", i))
+		c.Assert(rec.FirstSeenAt.IsZero(), gc.Equals, false)
+		c.Assert(rec.RemovedAt.IsZero(), gc.Equals, !expRemoved[i], gc.Commentf("record %d", i))
+		if expRemoved[i] {
+			c.Assert(rec.RemovedAt.Before(rec.FirstSeenAt), gc.Equals, false)
+		}
+		if i > 0 {
+			c.Assert(rec.FirstSeenAt.Before(records[i-1].FirstSeenAt), gc.Equals, false)
+		}
+	}
+}
+
+// TestHosts verifies the host upsert, lookup and fetch tracking logic.
+func (s *SuiteBase) TestHosts(c *gc.C) {
+	_, err := s.g.FindHost(context.Background(), "example.com")
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+
+	// Recording a fetch for an unknown host creates it.
+	fetchedAt := time.Now().Truncate(time.Second).UTC()
+	c.Assert(s.g.RecordHostFetch(context.Background(), "example.com", fetchedAt, false), gc.IsNil)
+	c.Assert(s.g.RecordHostFetch(context.Background(), "example.com", fetchedAt.Add(-time.Hour), true), gc.IsNil)
+
+	host, err := s.g.FindHost(context.Background(), "example.com")
+	c.Assert(err, gc.IsNil)
+	c.Assert(host.LastContactedAt, gc.Equals, fetchedAt, gc.Commentf("last contacted time should never move backwards"))
+	c.Assert(host.FetchCount, gc.Equals, 2)
+	c.Assert(host.ErrorCount, gc.Equals, 1)
+	c.Assert(host.ErrorRate(), gc.Equals, 0.5)
+
+	// Upserting the host settings must preserve the fetch statistics.
+	update := &Host{
+		Name:            "example.com",
+		RobotsTxt:       "User-agent: *\nDisallow: /private",
+		RobotsFetchedAt: fetchedAt,
+		CrawlDelay:      2 * time.Second,
+		Blocked:         true,
+		BlockReason:     "spam",
+	}
+	c.Assert(s.g.UpsertHost(context.Background(), update), gc.IsNil)
+	c.Assert(update.FetchCount, gc.Equals, 2)
+	c.Assert(update.ErrorCount, gc.Equals, 1)
+
+	host, err = s.g.FindHost(context.Background(), "example.com")
+	c.Assert(err, gc.IsNil)
+	c.Assert(host, gc.DeepEquals, update)
+
+	// Upserting a new host creates it.
+	other := &Host{Name: "other.com:8080", CrawlDelay: time.Second}
+	c.Assert(s.g.UpsertHost(context.Background(), other), gc.IsNil)
+	host, err = s.g.FindHost(context.Background(), other.Name)
+	c.Assert(err, gc.IsNil)
+	c.Assert(host.CrawlDelay, gc.Equals, time.Second)
+	c.Assert(host.FetchCount, gc.Equals, 0)
+}
+
+func (s *SuiteBase) assertEdgesTo(c *gc.C, dstID uuid.UUID, exp map[uuid.UUID]uuid.UUID) {
+	it, err := s.g.EdgesTo(context.Background(), dstID)
+	c.Assert(err, gc.IsNil)
+
+	got := make(map[uuid.UUID]uuid.UUID)
+	for it.Next() {
+		edge := it.Edge()
+		c.Assert(edge.Dst, gc.Equals, dstID)
+		got[edge.ID] = edge.Src
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(got, gc.DeepEquals, exp)
+}
+
 func (s *SuiteBase) partitionedLinkIterator(c *gc.C, partition, numPartitions int, accessedBefore time.Time) (LinkIterator, error) {
 	from, to := s.partitionRange(c, partition, numPartitions)
-	return s.g.Links(from, to, accessedBefore)
+	return s.g.Links(context.Background(), from, to, accessedBefore)
 }
 
 func (s *SuiteBase) partitionedEdgeIterator(c *gc.C, partition, numPartitions int, updatedBefore time.Time) (EdgeIterator, error) {
 	from, to := s.partitionRange(c, partition, numPartitions)
-	return s.g.Edges(from, to, updatedBefore)
+	return s.g.Edges(context.Background(), from, to, updatedBefore)
 }
 
 func (s *SuiteBase) partitionRange(c *gc.C, partition, numPartitions int) (from, to uuid.UUID) {
@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+// The number of events that can be buffered by a watcher before new events
+// start getting dropped.
+const watcherBufferSize = 256
+
+// LinkEventType describes the kind of change reported by a LinkEvent.
+type LinkEventType uint8
+
+const (
+	// LinkCreated is emitted when a new link is inserted into the graph.
+	LinkCreated LinkEventType = iota + 1
+	// LinkUpdated is emitted when an existing link is upserted.
+	LinkUpdated
+	// LinkDeleted is emitted when a link is removed from the graph.
+	LinkDeleted
+)
+
+// LinkEvent describes a change to a link in the graph.
+type LinkEvent struct {
+	Type LinkEventType
+	Link *Link
+}
+
+// LinkWatcher is implemented by objects that stream link change events.
+type LinkWatcher interface {
+	// Events returns a channel that emits link change events. The channel
+	// is closed once the watcher is closed or an error occurs.
+	Events() <-chan LinkEvent
+
+	// Error returns the error that caused the event channel to be closed.
+	Error() error
+
+	// Close stops the watcher and releases any associated resources.
+	Close() error
+}
+
+// LinkEventBroadcaster fans out link events to a dynamic set of in-process
+// watchers. To ensure that slow watchers never block graph updates, events are
+// dropped for watchers whose buffer is full. The zero value is ready to use.
+type LinkEventBroadcaster struct {
+	mu       sync.Mutex
+	watchers map[*linkWatcher]struct{}
+}
+
+// Watch registers and returns a new watcher. The watcher is closed once ctx
+// is cancelled.
+func (b *LinkEventBroadcaster) Watch(ctx context.Context) LinkWatcher {
+	w := &linkWatcher{
+		b:      b,
+		events: make(chan LinkEvent, watcherBufferSize),
+		doneCh: make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.watchers == nil {
+		b.watchers = make(map[*linkWatcher]struct{})
+	}
+	b.watchers[w] = struct{}{}
+	b.mu.Unlock()
+
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				_ = w.Close()
+			case <-w.doneCh:
+			}
+		}()
+	}
+	return w
+}
+
+// HasWatchers returns true if at least one watcher is registered.
+func (b *LinkEventBroadcaster) HasWatchers() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.watchers) != 0
+}
+
+// Publish emits an event with the specified type and a copy of link to all
+// registered watchers.
+func (b *LinkEventBroadcaster) Publish(evType LinkEventType, link *Link) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for w := range b.watchers {
+		lCopy := new(Link)
+		*lCopy = *link
+
+		select {
+		case w.events <- LinkEvent{Type: evType, Link: lCopy}:
+		default: // watcher is lagging behind; drop the event
+		}
+	}
+}
+
+type linkWatcher struct {
+	b      *LinkEventBroadcaster
+	events chan LinkEvent
+	doneCh chan struct{}
+}
+
+// Events implements LinkWatcher.
+func (w *linkWatcher) Events() <-chan LinkEvent { return w.events }
+
+// Error implements LinkWatcher.
+func (w *linkWatcher) Error() error { return nil }
+
+// Close implements LinkWatcher.
+func (w *linkWatcher) Close() error {
+	w.b.mu.Lock()
+	defer w.b.mu.Unlock()
+
+	if _, registered := w.b.watchers[w]; registered {
+		delete(w.b.watchers, w)
+		close(w.events)
+		close(w.doneCh)
+	}
+	return nil
+}
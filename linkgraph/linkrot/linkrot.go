@@ -0,0 +1,239 @@
+// Package linkrot generates broken-link reports from the contents of a link
+// graph. A report lists the outbound links of each page whose target can no
+// longer be crawled successfully so that site owners can fix or remove them.
+package linkrot
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"context"
+	"encoding/csv"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+var (
+	// maxUUID and maxTime are used for selecting every edge from the graph.
+	maxUUID = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+	maxTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// Graph defines the set of graph API methods that are needed for generating
+// broken-link reports. If the graph also implements graph.EdgeHistorian, the
+// report includes the time each broken link was first seen.
+type Graph interface {
+	// Edges returns an iterator for the set of edges whose source vertex
+	// IDs belong to the [fromID, toID) range and were updated before the
+	// provided timestamp.
+	Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error)
+
+	// FindLink looks up a link by its ID.
+	FindLink(ctx context.Context, id uuid.UUID) (*graph.Link, error)
+}
+
+// BrokenLink describes an edge whose target link is failing.
+type BrokenLink struct {
+	SourceID  uuid.UUID
+	SourceURL string
+	TargetID  uuid.UUID
+	TargetURL string
+
+	// AnchorText is the text of the anchor that links the source to the
+	// target.
+	AnchorText string
+
+	// StatusCode and FailureCount describe the outcome of the last crawl
+	// attempt for the target and LastCrawledAt is the time it was made.
+	StatusCode    int
+	FailureCount  int
+	LastCrawledAt time.Time
+
+	// LinkedSince is the time the source started linking to the target or
+	// the zero time if the graph does not record edge history.
+	LinkedSince time.Time
+}
+
+// Options configures the generation of broken-link reports.
+type Options struct {
+	// The minimum number of consecutive failed crawl attempts for a target
+	// to be reported as broken. Defaults to 1.
+	MinFailureCount int
+}
+
+// Report returns the edges of g whose target link is failing, sorted by their
+// source and target URLs.
+func Report(ctx context.Context, g Graph, opts Options) ([]*BrokenLink, error) {
+	if opts.MinFailureCount <= 0 {
+		opts.MinFailureCount = 1
+	}
+
+	r := &reporter{g: g, opts: opts, links: make(map[uuid.UUID]*graph.Link)}
+	if h, ok := g.(graph.EdgeHistorian); ok {
+		r.historian = h
+	}
+
+	broken, err := r.findBrokenLinks(ctx)
+	if err != nil {
+		return nil, xerrors.Errorf("broken link report: %w", err)
+	}
+	if err = r.annotateLinkedSince(ctx, broken); err != nil {
+		return nil, xerrors.Errorf("broken link report: %w", err)
+	}
+
+	sort.Slice(broken, func(i, j int) bool {
+		if broken[i].SourceURL != broken[j].SourceURL {
+			return broken[i].SourceURL < broken[j].SourceURL
+		}
+		return broken[i].TargetURL < broken[j].TargetURL
+	})
+	return broken, nil
+}
+
+type reporter struct {
+	g         Graph
+	historian graph.EdgeHistorian
+	opts      Options
+
+	// links caches the links that have been looked up so far as most
+	// targets are linked from more than one page.
+	links map[uuid.UUID]*graph.Link
+}
+
+func (r *reporter) findBrokenLinks(ctx context.Context) ([]*BrokenLink, error) {
+	it, err := r.g.Edges(ctx, uuid.Nil, maxUUID, maxTime)
+	if err != nil {
+		return nil, err
+	}
+
+	var broken []*BrokenLink
+	for it.Next() {
+		edge := it.Edge()
+		target, err := r.findLink(ctx, edge.Dst)
+		if err != nil {
+			_ = it.Close()
+			return nil, err
+		} else if target == nil || target.FailureCount < r.opts.MinFailureCount {
+			continue
+		}
+
+		source, err := r.findLink(ctx, edge.Src)
+		if err != nil {
+			_ = it.Close()
+			return nil, err
+		} else if source == nil {
+			continue
+		}
+
+		broken = append(broken, &BrokenLink{
+			SourceID:      source.ID,
+			SourceURL:     source.URL,
+			TargetID:      target.ID,
+			TargetURL:     target.URL,
+			AnchorText:    edge.AnchorText,
+			StatusCode:    target.StatusCode,
+			FailureCount:  target.FailureCount,
+			LastCrawledAt: target.RetrievedAt,
+		})
+	}
+	if err = it.Error(); err != nil {
+		_ = it.Close()
+		return nil, err
+	}
+	if err = it.Close(); err != nil {
+		return nil, err
+	}
+	return broken, nil
+}
+
+// findLink looks up the link with the specified ID. It returns a nil link if
+// the link has been deleted while the report was being generated.
+func (r *reporter) findLink(ctx context.Context, id uuid.UUID) (*graph.Link, error) {
+	if link, found := r.links[id]; found {
+		return link, nil
+	}
+
+	link, err := r.g.FindLink(ctx, id)
+	if err != nil && !xerrors.Is(err, graph.ErrNotFound) {
+		return nil, err
+	}
+	r.links[id] = link
+	return link, nil
+}
+
+// annotateLinkedSince populates the LinkedSince field of each broken link from
+// the edge history of its source.
+func (r *reporter) annotateLinkedSince(ctx context.Context, broken []*BrokenLink) error {
+	if r.historian == nil {
+		return nil
+	}
+
+	bySource := make(map[uuid.UUID][]*BrokenLink)
+	for _, b := range broken {
+		bySource[b.SourceID] = append(bySource[b.SourceID], b)
+	}
+
+	for srcID, links := range bySource {
+		records, err := r.historian.EdgeTimeline(ctx, srcID)
+		if xerrors.Is(err, graph.ErrEdgeHistoryDisabled) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		for _, rec := range records {
+			if !rec.RemovedAt.IsZero() {
+				continue
+			}
+			for _, b := range links {
+				if b.TargetID == rec.Dst {
+					b.LinkedSince = rec.FirstSeenAt
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// csvHeader lists the columns written by WriteCSV.
+var csvHeader = []string{
+	"source_url", "target_url", "anchor_text", "status_code",
+	"failure_count", "last_crawled_at", "linked_since",
+}
+
+// WriteCSV writes a broken-link report to w in CSV format. Timestamps are
+// formatted according to RFC 3339; unknown timestamps are left empty.
+func WriteCSV(w io.Writer, broken []*BrokenLink) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return xerrors.Errorf("write CSV report: %w", err)
+	}
+	for _, b := range broken {
+		if err := cw.Write([]string{
+			b.SourceURL,
+			b.TargetURL,
+			b.AnchorText,
+			strconv.Itoa(b.StatusCode),
+			strconv.Itoa(b.FailureCount),
+			formatTime(b.LastCrawledAt),
+			formatTime(b.LinkedSince),
+		}); err != nil {
+			return xerrors.Errorf("write CSV report: %w", err)
+		}
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return xerrors.Errorf("write CSV report: %w", err)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,134 @@
+package linkrot
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/store/memory"
+	"bytes"
+	"context"
+	gc "gopkg.in/check.v1"
+	"strings"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(LinkRotTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type LinkRotTestSuite struct{}
+
+func (s *LinkRotTestSuite) TestReport(c *gc.C) {
+	g := memory.NewInMemoryGraph(memory.WithEdgeHistory())
+	s.populateGraph(c, g)
+
+	broken, err := Report(context.Background(), g, Options{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(reportedPairs(broken), gc.DeepEquals, []string{
+		"https://a.example.com -> https://example.com/gone",
+		"https://b.example.com -> https://example.com/gone",
+		"https://b.example.com -> https://example.com/timeout",
+	})
+	c.Assert(broken[0].AnchorText, gc.Equals, "gone page")
+	c.Assert(broken[0].StatusCode, gc.Equals, 404)
+	c.Assert(broken[0].FailureCount, gc.Equals, 3)
+	for _, b := range broken {
+		c.Assert(b.LinkedSince.IsZero(), gc.Equals, false)
+	}
+
+	// Only report targets that failed repeatedly.
+	broken, err = Report(context.Background(), g, Options{MinFailureCount: 2})
+	c.Assert(err, gc.IsNil)
+	c.Assert(reportedPairs(broken), gc.DeepEquals, []string{
+		"https://a.example.com -> https://example.com/gone",
+		"https://b.example.com -> https://example.com/gone",
+	})
+}
+
+func (s *LinkRotTestSuite) TestReportRediscoveredTarget(c *gc.C) {
+	g := memory.NewInMemoryGraph()
+	src := &graph.Link{URL: "https://a.example.com", RetrievedAt: time.Now(), StatusCode: 200}
+	c.Assert(g.UpsertLink(context.Background(), src), gc.IsNil)
+
+	// The target has never been fetched successfully so its failures are
+	// recorded without a retrieval time.
+	target := &graph.Link{URL: "https://example.com/unreachable", FailureCount: 2, NextCrawlAt: time.Now().Add(time.Hour)}
+	c.Assert(g.UpsertLink(context.Background(), target), gc.IsNil)
+
+	// Crawling the source page again rediscovers the target.
+	rediscovered := &graph.Link{URL: target.URL}
+	c.Assert(g.UpsertLinks(context.Background(), []*graph.Link{rediscovered}), gc.IsNil)
+	c.Assert(g.UpsertEdge(context.Background(), &graph.Edge{Src: src.ID, Dst: rediscovered.ID}), gc.IsNil)
+
+	broken, err := Report(context.Background(), g, Options{MinFailureCount: 2})
+	c.Assert(err, gc.IsNil)
+	c.Assert(reportedPairs(broken), gc.DeepEquals, []string{"https://a.example.com -> https://example.com/unreachable"})
+	c.Assert(broken[0].FailureCount, gc.Equals, 2)
+}
+
+func (s *LinkRotTestSuite) TestReportWithoutEdgeHistory(c *gc.C) {
+	g := memory.NewInMemoryGraph()
+	s.populateGraph(c, g)
+
+	broken, err := Report(context.Background(), g, Options{})
+	c.Assert(err, gc.IsNil)
+	c.Assert(broken, gc.HasLen, 3)
+	for _, b := range broken {
+		c.Assert(b.LinkedSince.IsZero(), gc.Equals, true)
+	}
+}
+
+func (s *LinkRotTestSuite) TestWriteCSV(c *gc.C) {
+	crawledAt := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, []*BrokenLink{
+		{
+			SourceURL:     "https://a.example.com",
+			TargetURL:     "https://example.com/gone",
+			AnchorText:    "gone, for good",
+			StatusCode:    404,
+			FailureCount:  3,
+			LastCrawledAt: crawledAt,
+		},
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(buf.String(), gc.Equals, strings.Join([]string{
+		"source_url,target_url,anchor_text,status_code,failure_count,last_crawled_at,linked_since",
+		`https://a.example.com,https://example.com/gone,"gone, for good",404,3,2024-03-01T12:00:00Z,`,
+		"",
+	}, "\n"))
+}
+
+// populateGraph creates two source pages that link to a healthy page and to
+// pages that fail with a 404 status code or time out.
+func (s *LinkRotTestSuite) populateGraph(c *gc.C, g graph.Graph) {
+	now := time.Now()
+	links := map[string]*graph.Link{
+		"a":       {URL: "https://a.example.com", RetrievedAt: now, StatusCode: 200},
+		"b":       {URL: "https://b.example.com", RetrievedAt: now, StatusCode: 200},
+		"ok":      {URL: "https://example.com/ok", RetrievedAt: now, StatusCode: 200},
+		"gone":    {URL: "https://example.com/gone", RetrievedAt: now, StatusCode: 404, FailureCount: 3},
+		"timeout": {URL: "https://example.com/timeout", RetrievedAt: now, FailureCount: 1},
+	}
+	for _, link := range links {
+		c.Assert(g.UpsertLink(context.Background(), link), gc.IsNil)
+	}
+
+	for _, e := range []struct{ src, dst, anchor string }{
+		{"a", "ok", "ok page"},
+		{"a", "gone", "gone page"},
+		{"b", "gone", "gone page"},
+		{"b", "timeout", "slow page"},
+		{"ok", "a", "home"},
+	} {
+		edge := &graph.Edge{Src: links[e.src].ID, Dst: links[e.dst].ID, AnchorText: e.anchor}
+		c.Assert(g.UpsertEdge(context.Background(), edge), gc.IsNil)
+	}
+}
+
+func reportedPairs(broken []*BrokenLink) []string {
+	pairs := make([]string, len(broken))
+	for i, b := range broken {
+		pairs[i] = b.SourceURL + " -> " + b.TargetURL
+	}
+	return pairs
+}
@@ -0,0 +1,233 @@
+package snapshot
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// The default number of links or edges that are restored by each batch.
+const defaultBatchSize = 500
+
+// RestoreOptions configures a snapshot restore.
+type RestoreOptions struct {
+	// The number of links or edges to pass to the destination graph with
+	// each call. If not specified, a default value of 500 will be used.
+	BatchSize int
+
+	// The path to a file for tracking the restore progress. If the file
+	// exists, the restore resumes after the last batch that was restored by
+	// a previous attempt. The file is removed once the restore completes.
+	// If not specified, restores always start from the beginning of the
+	// snapshot.
+	CheckpointPath string
+}
+
+type checkpoint struct {
+	SnapshotID uuid.UUID `json:"snapshot_id"`
+	Records    int       `json:"records"`
+}
+
+// Restore reads a snapshot from r and restores its links and edges into dst
+// preserving their IDs.
+func Restore(ctx context.Context, r io.Reader, dst graph.Restorer, opts RestoreOptions) (*Stats, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+
+	dec := json.NewDecoder(r)
+	var hdr header
+	if err := dec.Decode(&hdr); err != nil {
+		return nil, xerrors.Errorf("restore: %w", decodeError(err))
+	} else if hdr.Version != FormatVersion {
+		return nil, xerrors.Errorf("restore: %w: %d", ErrUnsupportedVersion, hdr.Version)
+	}
+
+	rs := &restorer{dst: dst, opts: opts, snapshotID: hdr.ID}
+	if err := rs.loadCheckpoint(); err != nil {
+		return nil, xerrors.Errorf("restore: %w", err)
+	}
+
+	if err := rs.run(ctx, dec); err != nil {
+		return nil, xerrors.Errorf("restore: %w", err)
+	}
+	return &rs.stats, nil
+}
+
+type restorer struct {
+	dst        graph.Restorer
+	opts       RestoreOptions
+	snapshotID uuid.UUID
+
+	// The number of leading records that were restored by a previous
+	// attempt and the number of records that have been restored so far.
+	skip      int
+	committed int
+
+	links []*graph.Link
+	edges []*graph.Edge
+	stats Stats
+}
+
+func (rs *restorer) run(ctx context.Context, dec *json.Decoder) error {
+	for seen := 0; ; {
+		var rec record
+		if err := dec.Decode(&rec); err != nil {
+			return decodeError(err)
+		}
+
+		switch {
+		case rec.End != nil:
+			if err := rs.flush(ctx); err != nil {
+				return err
+			} else if seen != rec.End.Links+rec.End.Edges {
+				return xerrors.Errorf("%w: expected %d records; got %d", ErrTruncated, rec.End.Links+rec.End.Edges, seen)
+			}
+			return rs.removeCheckpoint()
+		case rec.Link != nil:
+			if seen++; seen <= rs.skip {
+				rs.stats.Skipped++
+				continue
+			} else if err := rs.flushEdges(ctx); err != nil {
+				return err
+			}
+
+			rs.links = append(rs.links, &graph.Link{
+				ID:           rec.Link.ID,
+				URL:          rec.Link.URL,
+				RetrievedAt:  rec.Link.RetrievedAt,
+				StatusCode:   rec.Link.StatusCode,
+				ContentHash:  rec.Link.ContentHash,
+				ETag:         rec.Link.ETag,
+				LastModified: rec.Link.LastModified,
+				FailureCount: rec.Link.FailureCount,
+				NextCrawlAt:  rec.Link.NextCrawlAt,
+			})
+			if len(rs.links) >= rs.opts.BatchSize {
+				if err := rs.flushLinks(ctx); err != nil {
+					return err
+				}
+			}
+		case rec.Edge != nil:
+			if seen++; seen <= rs.skip {
+				rs.stats.Skipped++
+				continue
+			} else if err := rs.flushLinks(ctx); err != nil {
+				return err
+			}
+
+			rs.edges = append(rs.edges, &graph.Edge{
+				ID:         rec.Edge.ID,
+				Src:        rec.Edge.Src,
+				Dst:        rec.Edge.Dst,
+				UpdatedAt:  rec.Edge.UpdatedAt,
+				AnchorText: rec.Edge.AnchorText,
+				Rel:        rec.Edge.Rel,
+			})
+			if len(rs.edges) >= rs.opts.BatchSize {
+				if err := rs.flushEdges(ctx); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (rs *restorer) flush(ctx context.Context) error {
+	if err := rs.flushLinks(ctx); err != nil {
+		return err
+	}
+	return rs.flushEdges(ctx)
+}
+
+func (rs *restorer) flushLinks(ctx context.Context) error {
+	if len(rs.links) == 0 {
+		return nil
+	} else if err := rs.dst.RestoreLinks(ctx, rs.links); err != nil {
+		return err
+	}
+
+	rs.stats.Links += len(rs.links)
+	rs.committed += len(rs.links)
+	rs.links = rs.links[:0]
+	return rs.saveCheckpoint()
+}
+
+func (rs *restorer) flushEdges(ctx context.Context) error {
+	if len(rs.edges) == 0 {
+		return nil
+	} else if err := rs.dst.RestoreEdges(ctx, rs.edges); err != nil {
+		return err
+	}
+
+	rs.stats.Edges += len(rs.edges)
+	rs.committed += len(rs.edges)
+	rs.edges = rs.edges[:0]
+	return rs.saveCheckpoint()
+}
+
+func (rs *restorer) loadCheckpoint() error {
+	if rs.opts.CheckpointPath == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(rs.opts.CheckpointPath)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var cp checkpoint
+	if err = json.Unmarshal(data, &cp); err != nil {
+		return xerrors.Errorf("unable to parse checkpoint: %w", err)
+	} else if cp.SnapshotID != rs.snapshotID {
+		return ErrCheckpointMismatch
+	}
+
+	rs.skip, rs.committed = cp.Records, cp.Records
+	return nil
+}
+
+// saveCheckpoint atomically replaces the checkpoint file with the current
+// restore progress.
+func (rs *restorer) saveCheckpoint() error {
+	if rs.opts.CheckpointPath == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(checkpoint{SnapshotID: rs.snapshotID, Records: rs.committed})
+	if err != nil {
+		return err
+	}
+
+	tmpPath := rs.opts.CheckpointPath + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, rs.opts.CheckpointPath)
+}
+
+func (rs *restorer) removeCheckpoint() error {
+	if rs.opts.CheckpointPath == "" {
+		return nil
+	}
+	if err := os.Remove(rs.opts.CheckpointPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// decodeError maps errors caused by a snapshot ending prematurely to
+// ErrTruncated.
+func decodeError(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrTruncated
+	}
+	return err
+}
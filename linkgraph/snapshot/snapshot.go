@@ -0,0 +1,180 @@
+// Package snapshot implements a portable file format for exporting the
+// contents of a link graph and restoring them into any graph store.
+//
+// A snapshot is a JSONL stream. The first line contains a header with the
+// format version and a unique snapshot ID. It is followed by one line for each
+// link and then one line for each edge. The last line contains a trailer with
+// the number of exported links and edges which allows restores to detect
+// truncated snapshots. The host records of the graph are not part of the
+// snapshot.
+package snapshot
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"bufio"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"io"
+	"time"
+)
+
+// FormatVersion is the snapshot format version written by Export.
+const FormatVersion = 1
+
+var (
+	// ErrUnsupportedVersion is returned when restoring a snapshot that was
+	// written using an unknown format version.
+	ErrUnsupportedVersion = xerrors.New("unsupported snapshot format version")
+
+	// ErrTruncated is returned when restoring a snapshot that does not end
+	// with a valid trailer.
+	ErrTruncated = xerrors.New("snapshot is truncated")
+
+	// ErrCheckpointMismatch is returned when the restore checkpoint was
+	// created for a different snapshot.
+	ErrCheckpointMismatch = xerrors.New("checkpoint belongs to a different snapshot")
+
+	// maxUUID and maxTime are used for selecting every link and edge from
+	// the graph.
+	maxUUID = uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff")
+	maxTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// Source is implemented by graphs that can be exported.
+type Source interface {
+	// Links returns an iterator for the set of links whose IDs belong to
+	// the [fromID, toID) range and were retrieved before the provided
+	// timestamp.
+	Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
+
+	// Edges returns an iterator for the set of edges whose source vertex
+	// IDs belong to the [fromID, toID) range and were updated before the
+	// provided timestamp.
+	Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error)
+}
+
+// Stats describes the number of records processed by an export or restore.
+type Stats struct {
+	Links int
+	Edges int
+
+	// Skipped is the number of records that were skipped while resuming a
+	// restore from a checkpoint.
+	Skipped int
+}
+
+type header struct {
+	Version   int       `json:"version"`
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type record struct {
+	Link *link    `json:"link,omitempty"`
+	Edge *edge    `json:"edge,omitempty"`
+	End  *trailer `json:"end,omitempty"`
+}
+
+type link struct {
+	ID           uuid.UUID `json:"id"`
+	URL          string    `json:"url"`
+	RetrievedAt  time.Time `json:"retrieved_at"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	ContentHash  string    `json:"content_hash,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FailureCount int       `json:"failure_count,omitempty"`
+	NextCrawlAt  time.Time `json:"next_crawl_at"`
+}
+
+type edge struct {
+	ID         uuid.UUID `json:"id"`
+	Src        uuid.UUID `json:"src"`
+	Dst        uuid.UUID `json:"dst"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	AnchorText string    `json:"anchor_text,omitempty"`
+	Rel        string    `json:"rel,omitempty"`
+}
+
+type trailer struct {
+	Links int `json:"links"`
+	Edges int `json:"edges"`
+}
+
+// Export writes a snapshot of all links and edges in src to w.
+func Export(ctx context.Context, w io.Writer, src Source) (*Stats, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(header{Version: FormatVersion, ID: uuid.New(), CreatedAt: time.Now().UTC()}); err != nil {
+		return nil, xerrors.Errorf("export: %w", err)
+	}
+
+	stats := new(Stats)
+	linkIt, err := src.Links(ctx, uuid.Nil, maxUUID, maxTime)
+	if err != nil {
+		return nil, xerrors.Errorf("export: %w", err)
+	}
+	for linkIt.Next() {
+		l := linkIt.Link()
+		if err = enc.Encode(record{Link: &link{
+			ID:           l.ID,
+			URL:          l.URL,
+			RetrievedAt:  l.RetrievedAt.UTC(),
+			StatusCode:   l.StatusCode,
+			ContentHash:  l.ContentHash,
+			ETag:         l.ETag,
+			LastModified: l.LastModified,
+			FailureCount: l.FailureCount,
+			NextCrawlAt:  l.NextCrawlAt.UTC(),
+		}}); err != nil {
+			break
+		}
+		stats.Links++
+	}
+	if err = closeIterator(linkIt, err); err != nil {
+		return nil, xerrors.Errorf("export: %w", err)
+	}
+
+	edgeIt, err := src.Edges(ctx, uuid.Nil, maxUUID, maxTime)
+	if err != nil {
+		return nil, xerrors.Errorf("export: %w", err)
+	}
+	for edgeIt.Next() {
+		e := edgeIt.Edge()
+		if err = enc.Encode(record{Edge: &edge{
+			ID:         e.ID,
+			Src:        e.Src,
+			Dst:        e.Dst,
+			UpdatedAt:  e.UpdatedAt.UTC(),
+			AnchorText: e.AnchorText,
+			Rel:        e.Rel,
+		}}); err != nil {
+			break
+		}
+		stats.Edges++
+	}
+	if err = closeIterator(edgeIt, err); err != nil {
+		return nil, xerrors.Errorf("export: %w", err)
+	}
+
+	if err = enc.Encode(record{End: &trailer{Links: stats.Links, Edges: stats.Edges}}); err != nil {
+		return nil, xerrors.Errorf("export: %w", err)
+	} else if err = bw.Flush(); err != nil {
+		return nil, xerrors.Errorf("export: %w", err)
+	}
+	return stats, nil
+}
+
+// closeIterator closes it and returns the first non-nil error out of err, the
+// iterator error and the error returned by Close.
+func closeIterator(it graph.Iterator, err error) error {
+	if err == nil {
+		err = it.Error()
+	}
+	if closeErr := it.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
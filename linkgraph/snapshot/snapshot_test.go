@@ -0,0 +1,167 @@
+package snapshot
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/store/memory"
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(SnapshotTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type SnapshotTestSuite struct {
+	src   *memory.InMemoryGraph
+	links []*graph.Link
+	edges []*graph.Edge
+}
+
+func (s *SnapshotTestSuite) SetUpTest(c *gc.C) {
+	s.src = memory.NewInMemoryGraph()
+	s.links = make([]*graph.Link, 10)
+	for i := range s.links {
+		s.links[i] = &graph.Link{
+			URL:         fmt.Sprintf("https://example.com/%d", i),
+			RetrievedAt: time.Now().Add(-time.Duration(i) * time.Hour).Truncate(time.Second).UTC(),
+			StatusCode:  200,
+			ContentHash: fmt.Sprint(i),
+		}
+	}
+	c.Assert(s.src.UpsertLinks(context.Background(), s.links), gc.IsNil)
+
+	s.edges = nil
+	for i := 1; i < len(s.links); i++ {
+		s.edges = append(s.edges, &graph.Edge{Src: s.links[0].ID, Dst: s.links[i].ID})
+	}
+	c.Assert(s.src.UpsertEdges(context.Background(), s.edges), gc.IsNil)
+}
+
+func (s *SnapshotTestSuite) TestExportAndRestore(c *gc.C) {
+	var buf bytes.Buffer
+	stats, err := Export(context.Background(), &buf, s.src)
+	c.Assert(err, gc.IsNil)
+	c.Assert(*stats, gc.Equals, Stats{Links: len(s.links), Edges: len(s.edges)})
+
+	dst := memory.NewInMemoryGraph()
+	stats, err = Restore(context.Background(), &buf, dst, RestoreOptions{BatchSize: 3})
+	c.Assert(err, gc.IsNil)
+	c.Assert(*stats, gc.Equals, Stats{Links: len(s.links), Edges: len(s.edges)})
+	s.assertRestored(c, dst)
+}
+
+func (s *SnapshotTestSuite) TestResumeRestore(c *gc.C) {
+	var buf bytes.Buffer
+	_, err := Export(context.Background(), &buf, s.src)
+	c.Assert(err, gc.IsNil)
+	snapshot := buf.Bytes()
+
+	// Abort the restore after a few batches have been applied.
+	checkpointPath := filepath.Join(c.MkDir(), "checkpoint")
+	dst := memory.NewInMemoryGraph()
+	failing := &failingRestorer{Restorer: dst, failAfter: 3}
+	_, err = Restore(context.Background(), bytes.NewReader(snapshot), failing, RestoreOptions{BatchSize: 4, CheckpointPath: checkpointPath})
+	c.Assert(xerrors.Is(err, errRestoreFailed), gc.Equals, true)
+	_, err = os.Stat(checkpointPath)
+	c.Assert(err, gc.IsNil, gc.Commentf("expected checkpoint file to be created"))
+
+	stats, err := Restore(context.Background(), bytes.NewReader(snapshot), dst, RestoreOptions{BatchSize: 4, CheckpointPath: checkpointPath})
+	c.Assert(err, gc.IsNil)
+	c.Assert(stats.Skipped, gc.Equals, len(s.links))
+	c.Assert(stats.Links+stats.Edges+stats.Skipped, gc.Equals, len(s.links)+len(s.edges))
+	s.assertRestored(c, dst)
+
+	_, err = os.Stat(checkpointPath)
+	c.Assert(os.IsNotExist(err), gc.Equals, true, gc.Commentf("expected checkpoint file to be removed"))
+}
+
+func (s *SnapshotTestSuite) TestCheckpointMismatch(c *gc.C) {
+	var buf bytes.Buffer
+	_, err := Export(context.Background(), &buf, s.src)
+	c.Assert(err, gc.IsNil)
+
+	checkpointPath := filepath.Join(c.MkDir(), "checkpoint")
+	data := fmt.Sprintf(`{"snapshot_id":%q,"records":1}`, uuid.New())
+	c.Assert(os.WriteFile(checkpointPath, []byte(data), 0644), gc.IsNil)
+
+	_, err = Restore(context.Background(), &buf, memory.NewInMemoryGraph(), RestoreOptions{CheckpointPath: checkpointPath})
+	c.Assert(xerrors.Is(err, ErrCheckpointMismatch), gc.Equals, true)
+}
+
+func (s *SnapshotTestSuite) TestTruncatedSnapshot(c *gc.C) {
+	var buf bytes.Buffer
+	_, err := Export(context.Background(), &buf, s.src)
+	c.Assert(err, gc.IsNil)
+
+	// Drop the trailer and the last record
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	truncated := bytes.Join(lines[:len(lines)-2], []byte("\n"))
+
+	_, err = Restore(context.Background(), bytes.NewReader(truncated), memory.NewInMemoryGraph(), RestoreOptions{})
+	c.Assert(xerrors.Is(err, ErrTruncated), gc.Equals, true)
+}
+
+func (s *SnapshotTestSuite) TestUnsupportedVersion(c *gc.C) {
+	snapshot := fmt.Sprintf(`{"version":%d,"id":%q}`, FormatVersion+1, uuid.New())
+	_, err := Restore(context.Background(), bytes.NewReader([]byte(snapshot)), memory.NewInMemoryGraph(), RestoreOptions{})
+	c.Assert(xerrors.Is(err, ErrUnsupportedVersion), gc.Equals, true)
+}
+
+func (s *SnapshotTestSuite) assertRestored(c *gc.C, dst *memory.InMemoryGraph) {
+	for _, link := range s.links {
+		restored, err := dst.FindLink(context.Background(), link.ID)
+		c.Assert(err, gc.IsNil)
+		c.Assert(restored, gc.DeepEquals, link)
+	}
+
+	it, err := dst.Edges(context.Background(), uuid.Nil, maxUUID, maxTime)
+	c.Assert(err, gc.IsNil)
+	var restored []*graph.Edge
+	for it.Next() {
+		restored = append(restored, it.Edge())
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+
+	sort.Slice(restored, func(l, r int) bool { return restored[l].ID.String() < restored[r].ID.String() })
+	exp := append([]*graph.Edge(nil), s.edges...)
+	sort.Slice(exp, func(l, r int) bool { return exp[l].ID.String() < exp[r].ID.String() })
+	c.Assert(restored, gc.HasLen, len(exp))
+	for i, edge := range restored {
+		c.Assert(edge.ID, gc.Equals, exp[i].ID)
+		c.Assert(edge.Src, gc.Equals, exp[i].Src)
+		c.Assert(edge.Dst, gc.Equals, exp[i].Dst)
+		c.Assert(edge.UpdatedAt.Equal(exp[i].UpdatedAt), gc.Equals, true)
+	}
+}
+
+var errRestoreFailed = xerrors.New("restore failed")
+
+// failingRestorer fails all restore calls after failAfter successful calls.
+type failingRestorer struct {
+	graph.Restorer
+	failAfter int
+}
+
+func (r *failingRestorer) RestoreLinks(ctx context.Context, links []*graph.Link) error {
+	if r.failAfter--; r.failAfter < 0 {
+		return errRestoreFailed
+	}
+	return r.Restorer.RestoreLinks(ctx, links)
+}
+
+func (r *failingRestorer) RestoreEdges(ctx context.Context, edges []*graph.Edge) error {
+	if r.failAfter--; r.failAfter < 0 {
+		return errRestoreFailed
+	}
+	return r.Restorer.RestoreEdges(ctx, edges)
+}
@@ -0,0 +1,752 @@
+package boltdb
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+	"sort"
+	"time"
+)
+
+// Compile-time checks for ensuring BoltGraph implements Graph, Restorer and
+// EdgeHistorian.
+var (
+	_ graph.Graph         = (*BoltGraph)(nil)
+	_ graph.Restorer      = (*BoltGraph)(nil)
+	_ graph.EdgeHistorian = (*BoltGraph)(nil)
+)
+
+var (
+	// linksBucket maps link IDs to JSON-encoded link entries.
+	linksBucket = []byte("links")
+	// linkURLsBucket maps link URLs to link IDs and enforces URL uniqueness.
+	linkURLsBucket = []byte("link_urls")
+	// edgesBucket maps (src, dst) link ID pairs to JSON-encoded edge entries.
+	// Keys are ordered by source ID so edges can be range-scanned by their
+	// origin link.
+	edgesBucket = []byte("edges")
+	// edgesByDstBucket indexes edges by their (dst, src) link ID pairs so
+	// that the edges pointing to a link can be looked up efficiently.
+	edgesByDstBucket = []byte("edges_by_dst")
+	// edgeIDsBucket maps edge IDs to the (src, dst) keys of their entries in
+	// the edges bucket.
+	edgeIDsBucket = []byte("edge_ids")
+	// tombstonesBucket maps the URLs of deleted links to their binary-encoded
+	// deletion time.
+	tombstonesBucket = []byte("tombstones")
+	// hostsBucket maps host names to JSON-encoded host entries.
+	hostsBucket = []byte("hosts")
+	// edgeHistoryBucket maps (src, dst, first-seen time) keys to
+	// JSON-encoded edge records.
+	edgeHistoryBucket = []byte("edge_history")
+
+	allBuckets = [][]byte{linksBucket, linkURLsBucket, edgesBucket, edgesByDstBucket, edgeIDsBucket, tombstonesBucket, hostsBucket, edgeHistoryBucket}
+)
+
+// BoltGraph implements a link graph that is persisted to an embedded bbolt
+// key/value file.
+type BoltGraph struct {
+	db     *bolt.DB
+	events graph.LinkEventBroadcaster
+
+	// edgeHistory enables the recording of edge history.
+	edgeHistory bool
+}
+
+// Option configures a BoltGraph.
+type Option func(*BoltGraph)
+
+// WithEdgeHistory configures the graph to keep a record of the edges that are
+// added to and removed from the graph. Edges that are modified while the
+// graph is opened without this option are not recorded.
+func WithEdgeHistory() Option {
+	return func(g *BoltGraph) { g.edgeHistory = true }
+}
+
+// NewBoltGraph opens (or creates) the bbolt database at path and returns a
+// link graph backed by it.
+func NewBoltGraph(path string, opts ...Option) (*BoltGraph, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, xerrors.Errorf("open bolt graph: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		// Graphs created before the edge ID index was introduced need to
+		// have it populated from their existing edges.
+		indexEdgeIDs := tx.Bucket(edgeIDsBucket) == nil
+		for _, name := range allBuckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		if indexEdgeIDs {
+			return buildEdgeIDIndex(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, xerrors.Errorf("open bolt graph: %w", err)
+	}
+
+	g := &BoltGraph{db: db}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g, nil
+}
+
+// Close releases the underlying database file.
+func (g *BoltGraph) Close() error {
+	return g.db.Close()
+}
+
+// UpsertLink creates a new link or updates an existing link.
+func (g *BoltGraph) UpsertLink(ctx context.Context, link *graph.Link) error {
+	var evType graph.LinkEventType
+	err := g.db.Update(func(tx *bolt.Tx) (err error) {
+		evType, err = upsertLink(tx, link)
+		return err
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert link: %w", err)
+	}
+	g.events.Publish(evType, link)
+	return nil
+}
+
+// UpsertLinks creates or updates a batch of links using a single transaction.
+// Links whose URL has been tombstoned are skipped and have their ID set to
+// uuid.Nil.
+func (g *BoltGraph) UpsertLinks(ctx context.Context, links []*graph.Link) error {
+	evTypes := make([]graph.LinkEventType, len(links))
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		for i, link := range links {
+			evType, err := upsertLink(tx, link)
+			if err != nil {
+				if xerrors.Is(err, graph.ErrLinkDeleted) {
+					link.ID = uuid.Nil
+					continue
+				}
+				return err
+			}
+			evTypes[i] = evType
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert links: %w", err)
+	}
+
+	for i, link := range links {
+		if evTypes[i] != 0 {
+			g.events.Publish(evTypes[i], link)
+		}
+	}
+	return nil
+}
+
+// upsertLink implements the upsert logic for a single link within tx and
+// returns the type of the change that was applied.
+func upsertLink(tx *bolt.Tx, link *graph.Link) (graph.LinkEventType, error) {
+	links := tx.Bucket(linksBucket)
+	urls := tx.Bucket(linkURLsBucket)
+
+	// Check if a link with the same URL already exists. If so, convert
+	// this into an update and point the link ID to the existing link.
+	if existingID := urls.Get([]byte(link.URL)); existingID != nil {
+		existing, err := decodeLink(links.Get(existingID))
+		if err != nil {
+			return 0, err
+		}
+
+		// Never overwrite the crawl details with older values or with
+		// the blank ones of a rediscovered link.
+		if !link.HasCrawlResult() || existing.RetrievedAt.After(link.RetrievedAt) {
+			*link = *existing
+			return graph.LinkUpdated, nil
+		}
+		link.ID = existing.ID
+		return graph.LinkUpdated, putLink(links, link)
+	}
+
+	// Refuse to recreate links that were recently deleted.
+	tombstones := tx.Bucket(tombstonesBucket)
+	if v := tombstones.Get([]byte(link.URL)); v != nil {
+		var deletedAt time.Time
+		if err := deletedAt.UnmarshalBinary(v); err != nil {
+			return 0, err
+		}
+		if time.Since(deletedAt) < graph.TombstoneTTL {
+			return 0, graph.ErrLinkDeleted
+		}
+		if err := tombstones.Delete([]byte(link.URL)); err != nil {
+			return 0, err
+		}
+	}
+
+	// Keep the caller-supplied ID, if any, or assign a new ID and insert
+	// the link.
+	if link.ID != uuid.Nil {
+		if links.Get(link.ID[:]) != nil {
+			return 0, graph.ErrIDConflict
+		}
+	} else {
+		for {
+			link.ID = uuid.New()
+			if links.Get(link.ID[:]) == nil {
+				break
+			}
+		}
+	}
+
+	if err := urls.Put([]byte(link.URL), link.ID[:]); err != nil {
+		return 0, err
+	}
+	return graph.LinkCreated, putLink(links, link)
+}
+
+// FindLink looks up a link by its ID.
+func (g *BoltGraph) FindLink(ctx context.Context, id uuid.UUID) (*graph.Link, error) {
+	var link *graph.Link
+	err := g.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(linksBucket).Get(id[:])
+		if v == nil {
+			return graph.ErrNotFound
+		}
+
+		var err error
+		link, err = decodeLink(v)
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("find link: %w", err)
+	}
+	return link, nil
+}
+
+// FindLinkByURL looks up a link by its URL.
+func (g *BoltGraph) FindLinkByURL(ctx context.Context, url string) (*graph.Link, error) {
+	var link *graph.Link
+	err := g.db.View(func(tx *bolt.Tx) error {
+		id := tx.Bucket(linkURLsBucket).Get([]byte(url))
+		if id == nil {
+			return graph.ErrNotFound
+		}
+
+		var err error
+		link, err = decodeLink(tx.Bucket(linksBucket).Get(id))
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("find link by URL: %w", err)
+	}
+	return link, nil
+}
+
+// DeleteLink removes a link together with all edges that originate from or
+// point to it and records a tombstone for the link's URL.
+func (g *BoltGraph) DeleteLink(ctx context.Context, id uuid.UUID) error {
+	var link *graph.Link
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		links := tx.Bucket(linksBucket)
+		v := links.Get(id[:])
+		if v == nil {
+			return graph.ErrNotFound
+		}
+		var err error
+		if link, err = decodeLink(v); err != nil {
+			return err
+		}
+
+		// Collect the (src, dst) pairs of all outgoing and incoming edges
+		// before deleting anything so that cursors are not invalidated.
+		var pairs [][2]uuid.UUID
+		c := tx.Bucket(edgesBucket).Cursor()
+		for k, _ := c.Seek(id[:]); k != nil && bytes.HasPrefix(k, id[:]); k, _ = c.Next() {
+			pairs = append(pairs, splitEdgeKey(k))
+		}
+		c = tx.Bucket(edgesByDstBucket).Cursor()
+		for k, _ := c.Seek(id[:]); k != nil && bytes.HasPrefix(k, id[:]); k, _ = c.Next() {
+			dstSrc := splitEdgeKey(k)
+			pairs = append(pairs, [2]uuid.UUID{dstSrc[1], dstSrc[0]})
+		}
+
+		now := time.Now()
+		for _, pair := range pairs {
+			if err := deleteEdge(tx, pair[0], pair[1]); err != nil {
+				return err
+			}
+			if g.edgeHistory {
+				if err := closeEdgeRecord(tx, pair[0], pair[1], now); err != nil {
+					return err
+				}
+			}
+		}
+
+		deletedAt, err := now.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(tombstonesBucket).Put([]byte(link.URL), deletedAt); err != nil {
+			return err
+		}
+		if err := tx.Bucket(linkURLsBucket).Delete([]byte(link.URL)); err != nil {
+			return err
+		}
+		return links.Delete(id[:])
+	})
+	if err != nil {
+		return xerrors.Errorf("delete link: %w", err)
+	}
+	g.events.Publish(graph.LinkDeleted, link)
+	return nil
+}
+
+// RestoreLinks inserts or replaces a batch of links keeping their IDs.
+func (g *BoltGraph) RestoreLinks(ctx context.Context, links []*graph.Link) error {
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		linksB, urls, tombstones := tx.Bucket(linksBucket), tx.Bucket(linkURLsBucket), tx.Bucket(tombstonesBucket)
+		for _, link := range links {
+			if existingID := urls.Get([]byte(link.URL)); existingID != nil && !bytes.Equal(existingID, link.ID[:]) {
+				return graph.ErrIDConflict
+			}
+			if v := linksB.Get(link.ID[:]); v != nil {
+				existing, err := decodeLink(v)
+				if err != nil {
+					return err
+				}
+				if err := urls.Delete([]byte(existing.URL)); err != nil {
+					return err
+				}
+			}
+
+			if err := urls.Put([]byte(link.URL), link.ID[:]); err != nil {
+				return err
+			}
+			if err := tombstones.Delete([]byte(link.URL)); err != nil {
+				return err
+			}
+			lCopy := *link
+			if err := putLink(linksB, &lCopy); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("restore links: %w", err)
+	}
+	return nil
+}
+
+// RestoreEdges inserts or replaces a batch of edges keeping their IDs and
+// update timestamps.
+func (g *BoltGraph) RestoreEdges(ctx context.Context, edges []*graph.Edge) error {
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		linksB, edgesB, edgeIDs := tx.Bucket(linksBucket), tx.Bucket(edgesBucket), tx.Bucket(edgeIDsBucket)
+		for _, edge := range edges {
+			if linksB.Get(edge.Src[:]) == nil || linksB.Get(edge.Dst[:]) == nil {
+				return graph.ErrUnknownEdgeLinks
+			}
+			key := edgeKey(edge.Src, edge.Dst)
+			v := edgesB.Get(key)
+			if v != nil {
+				existing, err := decodeEdge(v)
+				if err != nil {
+					return err
+				}
+				if existing.ID != edge.ID {
+					return graph.ErrIDConflict
+				}
+			} else if edgeIDs.Get(edge.ID[:]) != nil {
+				// The ID belongs to an edge between a different pair of links.
+				return graph.ErrIDConflict
+			} else if err := indexEdge(tx, edge); err != nil {
+				return err
+			}
+			eCopy := *edge
+			eCopy.UpdatedAt = eCopy.UpdatedAt.UTC()
+			if err := putEdge(edgesB, &eCopy); err != nil {
+				return err
+			}
+			if v == nil && g.edgeHistory {
+				if err := openEdgeRecord(tx, &eCopy); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("restore edges: %w", err)
+	}
+	return nil
+}
+
+// WatchLinks returns a watcher that receives an event for each link that is
+// created, updated or deleted through this graph instance after the call
+// returns.
+func (g *BoltGraph) WatchLinks(ctx context.Context) (graph.LinkWatcher, error) {
+	return g.events.Watch(ctx), nil
+}
+
+// Links returns an iterator for the set of links whose IDs belong to the
+// [fromID, toID) range and were retrieved before the provided timestamp.
+func (g *BoltGraph) Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error) {
+	return &linkIterator{
+		scanner: newRangeScanner(g.db, linksBucket, fromID, toID),
+		filter:  retrievedBefore,
+	}, nil
+}
+
+// UpsertEdge creates a new edge or updates an existing edge.
+func (g *BoltGraph) UpsertEdge(ctx context.Context, edge *graph.Edge) error {
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		return upsertEdge(tx, edge, g.edgeHistory)
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert edge: %w", err)
+	}
+	return nil
+}
+
+// UpsertEdges creates or updates a batch of edges using a single transaction.
+func (g *BoltGraph) UpsertEdges(ctx context.Context, edges []*graph.Edge) error {
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		for _, edge := range edges {
+			if err := upsertEdge(tx, edge, g.edgeHistory); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert edges: %w", err)
+	}
+	return nil
+}
+
+// upsertEdge implements the upsert logic for a single edge within tx. If
+// history is set, a history record is opened for new edges.
+func upsertEdge(tx *bolt.Tx, edge *graph.Edge, history bool) error {
+	links := tx.Bucket(linksBucket)
+	if links.Get(edge.Src[:]) == nil || links.Get(edge.Dst[:]) == nil {
+		return graph.ErrUnknownEdgeLinks
+	}
+
+	edges := tx.Bucket(edgesBucket)
+	key := edgeKey(edge.Src, edge.Dst)
+	v := edges.Get(key)
+	if v != nil {
+		existing, err := decodeEdge(v)
+		if err != nil {
+			return err
+		}
+		edge.ID = existing.ID
+	} else {
+		edge.ID = uuid.New()
+		if err := indexEdge(tx, edge); err != nil {
+			return err
+		}
+	}
+
+	edge.UpdatedAt = time.Now().UTC()
+	if err := putEdge(edges, edge); err != nil {
+		return err
+	}
+	if v == nil && history {
+		return openEdgeRecord(tx, edge)
+	}
+	return nil
+}
+
+// Edges returns an iterator for the set of edges whose source vertex IDs
+// belong to the [fromID, toID) range and were updated before the provided
+// timestamp.
+func (g *BoltGraph) Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
+	return &edgeIterator{
+		scanner:  newRangeScanner(g.db, edgesBucket, fromID, toID),
+		filter:   updatedBefore,
+		decodeFn: decodeEdgeEntry,
+	}, nil
+}
+
+// EdgesTo returns an iterator for the set of edges whose destination vertex is
+// the specified link ID.
+func (g *BoltGraph) EdgesTo(ctx context.Context, dstID uuid.UUID) (graph.EdgeIterator, error) {
+	return &edgeIterator{
+		scanner:  newPrefixScanner(g.db, edgesByDstBucket, dstID),
+		filter:   maxTime,
+		decodeFn: decodeEdgeByDstEntry,
+	}, nil
+}
+
+// InboundDegree returns the number of edges whose destination vertex is the
+// specified link ID.
+func (g *BoltGraph) InboundDegree(ctx context.Context, dstID uuid.UUID) (int, error) {
+	var count int
+	err := g.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(edgesByDstBucket).Cursor()
+		for k, _ := c.Seek(dstID[:]); k != nil && bytes.HasPrefix(k, dstID[:]); k, _ = c.Next() {
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, xerrors.Errorf("inbound degree: %w", err)
+	}
+	return count, nil
+}
+
+// RemoveStaleEdges removes any edge that originates from the specified link ID
+// and was updated before the specified timestamp.
+func (g *BoltGraph) RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time) error {
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		edges := tx.Bucket(edgesBucket)
+
+		// Collect the stale keys first; deleting while a cursor is
+		// positioned on the bucket may cause entries to be skipped.
+		var staleEdges []*graph.Edge
+		c := edges.Cursor()
+		for k, v := c.Seek(fromID[:]); k != nil && bytes.HasPrefix(k, fromID[:]); k, v = c.Next() {
+			edge, err := decodeEdge(v)
+			if err != nil {
+				return err
+			}
+			if edge.UpdatedAt.Before(updatedBefore) {
+				staleEdges = append(staleEdges, edge)
+			}
+		}
+
+		now := time.Now()
+		for _, edge := range staleEdges {
+			if err := deleteEdge(tx, edge.Src, edge.Dst); err != nil {
+				return err
+			}
+			if g.edgeHistory {
+				if err := closeEdgeRecord(tx, edge.Src, edge.Dst, now); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return xerrors.Errorf("remove stale edges: %w", err)
+	}
+	return nil
+}
+
+// EdgeTimeline returns the history of the edges originating from the specified
+// link ID ordered by their first-seen time.
+func (g *BoltGraph) EdgeTimeline(ctx context.Context, srcID uuid.UUID) ([]*graph.EdgeRecord, error) {
+	if !g.edgeHistory {
+		return nil, xerrors.Errorf("edge timeline: %w", graph.ErrEdgeHistoryDisabled)
+	}
+
+	records := []*graph.EdgeRecord{}
+	err := g.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(edgeHistoryBucket).Cursor()
+		for k, v := c.Seek(srcID[:]); k != nil && bytes.HasPrefix(k, srcID[:]); k, v = c.Next() {
+			rec, err := decodeEdgeRecord(v)
+			if err != nil {
+				return err
+			}
+			records = append(records, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("edge timeline: %w", err)
+	}
+
+	// Records are keyed by destination before their first-seen time.
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].FirstSeenAt.Before(records[j].FirstSeenAt)
+	})
+	return records, nil
+}
+
+// openEdgeRecord adds a history record for an edge that has been added to the
+// graph.
+func openEdgeRecord(tx *bolt.Tx, edge *graph.Edge) error {
+	return putEdgeRecord(tx.Bucket(edgeHistoryBucket), &graph.EdgeRecord{
+		Src:         edge.Src,
+		Dst:         edge.Dst,
+		FirstSeenAt: edge.UpdatedAt.UTC(),
+	})
+}
+
+// closeEdgeRecord marks the open history record for the edge between src and
+// dst as removed at removedAt.
+func closeEdgeRecord(tx *bolt.Tx, src, dst uuid.UUID, removedAt time.Time) error {
+	b := tx.Bucket(edgeHistoryBucket)
+	prefix := edgeKey(src, dst)
+
+	var open *graph.EdgeRecord
+	c := b.Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		rec, err := decodeEdgeRecord(v)
+		if err != nil {
+			return err
+		}
+		if rec.RemovedAt.IsZero() {
+			open = rec
+		}
+	}
+	if open == nil {
+		return nil
+	}
+
+	open.RemovedAt = removedAt.UTC()
+	return putEdgeRecord(b, open)
+}
+
+// edgeRecordKey returns the key for an edge history record. Keys are prefixed
+// with the edge key so that the records for the edges originating from the
+// same link are stored next to each other.
+func edgeRecordKey(rec *graph.EdgeRecord) []byte {
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(rec.FirstSeenAt.UnixNano()))
+	return append(edgeKey(rec.Src, rec.Dst), ts[:]...)
+}
+
+func putEdgeRecord(b *bolt.Bucket, rec *graph.EdgeRecord) error {
+	v, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return b.Put(edgeRecordKey(rec), v)
+}
+
+func decodeEdgeRecord(v []byte) (*graph.EdgeRecord, error) {
+	rec := new(graph.EdgeRecord)
+	if err := json.Unmarshal(v, rec); err != nil {
+		return nil, err
+	}
+	rec.FirstSeenAt = rec.FirstSeenAt.UTC()
+	rec.RemovedAt = rec.RemovedAt.UTC()
+	return rec, nil
+}
+
+// maxTime is used as the filter for iterators that should not exclude any
+// edges based on their update timestamp.
+var maxTime = time.Unix(1<<62, 0)
+
+// edgeKey returns the key for the edge between src and dst. Keys are prefixed
+// with the source ID so that edges originating from the same link are stored
+// next to each other.
+func edgeKey(src, dst uuid.UUID) []byte {
+	key := make([]byte, 0, len(src)+len(dst))
+	key = append(key, src[:]...)
+	return append(key, dst[:]...)
+}
+
+// splitEdgeKey splits an edge key into the two link IDs it is composed of.
+func splitEdgeKey(k []byte) [2]uuid.UUID {
+	var ids [2]uuid.UUID
+	copy(ids[0][:], k[:len(ids[0])])
+	copy(ids[1][:], k[len(ids[0]):])
+	return ids
+}
+
+func putLink(b *bolt.Bucket, link *graph.Link) error {
+	link.RetrievedAt = link.RetrievedAt.UTC()
+	link.NextCrawlAt = link.NextCrawlAt.UTC()
+	v, err := json.Marshal(link)
+	if err != nil {
+		return err
+	}
+	return b.Put(link.ID[:], v)
+}
+
+func decodeLink(v []byte) (*graph.Link, error) {
+	link := new(graph.Link)
+	if err := json.Unmarshal(v, link); err != nil {
+		return nil, err
+	}
+	link.RetrievedAt = link.RetrievedAt.UTC()
+	link.NextCrawlAt = link.NextCrawlAt.UTC()
+	return link, nil
+}
+
+// indexEdge adds a new edge to the edges-by-destination and edge ID indices.
+func indexEdge(tx *bolt.Tx, edge *graph.Edge) error {
+	if err := tx.Bucket(edgesByDstBucket).Put(edgeKey(edge.Dst, edge.Src), []byte{}); err != nil {
+		return err
+	}
+	return tx.Bucket(edgeIDsBucket).Put(edge.ID[:], edgeKey(edge.Src, edge.Dst))
+}
+
+// deleteEdge removes the edge between src and dst and its index entries.
+func deleteEdge(tx *bolt.Tx, src, dst uuid.UUID) error {
+	edges := tx.Bucket(edgesBucket)
+	key := edgeKey(src, dst)
+	if v := edges.Get(key); v != nil {
+		edge, err := decodeEdge(v)
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(edgeIDsBucket).Delete(edge.ID[:]); err != nil {
+			return err
+		}
+	}
+	if err := edges.Delete(key); err != nil {
+		return err
+	}
+	return tx.Bucket(edgesByDstBucket).Delete(edgeKey(dst, src))
+}
+
+// buildEdgeIDIndex populates the edge ID index from the edges bucket.
+func buildEdgeIDIndex(tx *bolt.Tx) error {
+	edgeIDs := tx.Bucket(edgeIDsBucket)
+	return tx.Bucket(edgesBucket).ForEach(func(k, v []byte) error {
+		edge, err := decodeEdge(v)
+		if err != nil {
+			return err
+		}
+		return edgeIDs.Put(edge.ID[:], k)
+	})
+}
+
+func putEdge(b *bolt.Bucket, edge *graph.Edge) error {
+	v, err := json.Marshal(edge)
+	if err != nil {
+		return err
+	}
+	return b.Put(edgeKey(edge.Src, edge.Dst), v)
+}
+
+// decodeEdgeEntry decodes an entry from the edges bucket.
+func decodeEdgeEntry(_ *bolt.Tx, _, v []byte) (*graph.Edge, error) {
+	return decodeEdge(v)
+}
+
+// decodeEdgeByDstEntry resolves an entry from the edges-by-destination index
+// to the edge it refers to.
+func decodeEdgeByDstEntry(tx *bolt.Tx, k, _ []byte) (*graph.Edge, error) {
+	ids := splitEdgeKey(k)
+	dst, src := ids[0], ids[1]
+
+	v := tx.Bucket(edgesBucket).Get(edgeKey(src, dst))
+	if v == nil {
+		return nil, xerrors.Errorf("edge index references unknown edge %s -> %s", src, dst)
+	}
+	return decodeEdge(v)
+}
+
+func decodeEdge(v []byte) (*graph.Edge, error) {
+	edge := new(graph.Edge)
+	if err := json.Unmarshal(v, edge); err != nil {
+		return nil, err
+	}
+	edge.UpdatedAt = edge.UpdatedAt.UTC()
+	return edge, nil
+}
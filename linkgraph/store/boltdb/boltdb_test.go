@@ -0,0 +1,76 @@
+package boltdb
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"context"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"path/filepath"
+	"testing"
+)
+
+var _ = gc.Suite(new(BoltGraphTestSuite))
+var _ = gc.Suite(new(BoltGraphWithHistoryTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type BoltGraphTestSuite struct {
+	graph.SuiteBase
+	g *BoltGraph
+}
+
+func (s *BoltGraphTestSuite) SetUpTest(c *gc.C) {
+	g, err := NewBoltGraph(filepath.Join(c.MkDir(), "graph.db"))
+	c.Assert(err, gc.IsNil)
+	s.SetGraph(g)
+	s.g = g
+}
+
+func (s *BoltGraphTestSuite) TearDownTest(c *gc.C) {
+	c.Assert(s.g.Close(), gc.IsNil)
+}
+
+// TestEdgeIDIndexBuiltOnOpen verifies that the edge ID index is populated
+// when opening a graph that was created without it.
+func (s *BoltGraphTestSuite) TestEdgeIDIndexBuiltOnOpen(c *gc.C) {
+	path := s.g.db.Path()
+	links := []*graph.Link{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}}
+	c.Assert(s.g.UpsertLinks(context.Background(), links), gc.IsNil)
+	edge := &graph.Edge{Src: links[0].ID, Dst: links[1].ID}
+	c.Assert(s.g.UpsertEdge(context.Background(), edge), gc.IsNil)
+
+	err := s.g.db.Update(func(tx *bolt.Tx) error { return tx.DeleteBucket(edgeIDsBucket) })
+	c.Assert(err, gc.IsNil)
+	c.Assert(s.g.Close(), gc.IsNil)
+	s.g, err = NewBoltGraph(path)
+	c.Assert(err, gc.IsNil)
+
+	err = s.g.RestoreEdges(context.Background(), []*graph.Edge{{ID: edge.ID, Src: links[1].ID, Dst: links[0].ID}})
+	c.Assert(xerrors.Is(err, graph.ErrIDConflict), gc.Equals, true)
+
+	// Deleting the source link of the edge releases its ID.
+	c.Assert(s.g.DeleteLink(context.Background(), links[0].ID), gc.IsNil)
+	other := &graph.Link{URL: "https://example.com/c"}
+	c.Assert(s.g.UpsertLink(context.Background(), other), gc.IsNil)
+	err = s.g.RestoreEdges(context.Background(), []*graph.Edge{{ID: edge.ID, Src: links[1].ID, Dst: other.ID}})
+	c.Assert(err, gc.IsNil)
+}
+
+// BoltGraphWithHistoryTestSuite runs the shared test suite against a graph
+// that keeps the edge history.
+type BoltGraphWithHistoryTestSuite struct {
+	graph.SuiteBase
+	g *BoltGraph
+}
+
+func (s *BoltGraphWithHistoryTestSuite) SetUpTest(c *gc.C) {
+	g, err := NewBoltGraph(filepath.Join(c.MkDir(), "graph.db"), WithEdgeHistory())
+	c.Assert(err, gc.IsNil)
+	s.SetGraph(g)
+	s.g = g
+}
+
+func (s *BoltGraphWithHistoryTestSuite) TearDownTest(c *gc.C) {
+	c.Assert(s.g.Close(), gc.IsNil)
+}
@@ -0,0 +1,98 @@
+package boltdb
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"context"
+	"encoding/json"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+	"time"
+)
+
+// UpsertHost creates a new host or updates the crawl settings of an existing
+// host.
+func (g *BoltGraph) UpsertHost(ctx context.Context, host *graph.Host) error {
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(hostsBucket)
+		if v := b.Get([]byte(host.Name)); v != nil {
+			existing, err := decodeHost(v)
+			if err != nil {
+				return err
+			}
+			host.LastContactedAt = existing.LastContactedAt
+			host.FetchCount = existing.FetchCount
+			host.ErrorCount = existing.ErrorCount
+		}
+		return putHost(b, host)
+	})
+	if err != nil {
+		return xerrors.Errorf("upsert host: %w", err)
+	}
+	return nil
+}
+
+// FindHost looks up a host by its name.
+func (g *BoltGraph) FindHost(ctx context.Context, name string) (*graph.Host, error) {
+	var host *graph.Host
+	err := g.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(hostsBucket).Get([]byte(name))
+		if v == nil {
+			return graph.ErrNotFound
+		}
+
+		var err error
+		host, err = decodeHost(v)
+		return err
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("find host: %w", err)
+	}
+	return host, nil
+}
+
+// RecordHostFetch records a fetch attempt for the specified host.
+func (g *BoltGraph) RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error {
+	err := g.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(hostsBucket)
+		host := &graph.Host{Name: name}
+		if v := b.Get([]byte(name)); v != nil {
+			var err error
+			if host, err = decodeHost(v); err != nil {
+				return err
+			}
+		}
+
+		if fetchedAt.After(host.LastContactedAt) {
+			host.LastContactedAt = fetchedAt
+		}
+		host.FetchCount++
+		if failed {
+			host.ErrorCount++
+		}
+		return putHost(b, host)
+	})
+	if err != nil {
+		return xerrors.Errorf("record host fetch: %w", err)
+	}
+	return nil
+}
+
+func putHost(b *bolt.Bucket, host *graph.Host) error {
+	host.RobotsFetchedAt = host.RobotsFetchedAt.UTC()
+	host.LastContactedAt = host.LastContactedAt.UTC()
+	v, err := json.Marshal(host)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(host.Name), v)
+}
+
+func decodeHost(v []byte) (*graph.Host, error) {
+	host := new(graph.Host)
+	if err := json.Unmarshal(v, host); err != nil {
+		return nil, err
+	}
+	host.RobotsFetchedAt = host.RobotsFetchedAt.UTC()
+	host.LastContactedAt = host.LastContactedAt.UTC()
+	return host, nil
+}
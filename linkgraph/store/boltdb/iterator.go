@@ -0,0 +1,206 @@
+package boltdb
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"bytes"
+	"github.com/google/uuid"
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/xerrors"
+	"time"
+)
+
+// The number of bucket entries that are scanned by each read-only transaction
+// opened by an iterator. Keeping transactions short prevents long-running
+// iterations from blocking the database file from growing.
+const scanBatchSize = 1000
+
+// rangeScanner walks the keys of a bucket that either fall within a [from, to)
+// range or share a common prefix in batches, using a separate read-only
+// transaction for each batch.
+type rangeScanner struct {
+	db     *bolt.DB
+	bucket []byte
+
+	nextKey []byte
+	toKey   []byte
+	prefix  []byte
+	done    bool
+}
+
+func newRangeScanner(db *bolt.DB, bucket []byte, fromID, toID uuid.UUID) *rangeScanner {
+	return &rangeScanner{
+		db:      db,
+		bucket:  bucket,
+		nextKey: append([]byte(nil), fromID[:]...),
+		toKey:   append([]byte(nil), toID[:]...),
+	}
+}
+
+func newPrefixScanner(db *bolt.DB, bucket []byte, id uuid.UUID) *rangeScanner {
+	return &rangeScanner{
+		db:      db,
+		bucket:  bucket,
+		nextKey: append([]byte(nil), id[:]...),
+		prefix:  append([]byte(nil), id[:]...),
+	}
+}
+
+// inRange returns true if k belongs to the range covered by the scanner.
+func (s *rangeScanner) inRange(k []byte) bool {
+	if s.prefix != nil {
+		return bytes.HasPrefix(k, s.prefix)
+	}
+	return bytes.Compare(k, s.toKey) < 0
+}
+
+// scanBatch invokes visitFn for each of the next scanBatchSize entries in
+// the range. It returns false once the end of the range has been reached.
+func (s *rangeScanner) scanBatch(visitFn func(tx *bolt.Tx, k, v []byte) error) (bool, error) {
+	if s.done {
+		return false, nil
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(s.bucket).Cursor()
+		scanned := 0
+		for k, v := c.Seek(s.nextKey); ; k, v = c.Next() {
+			if k == nil || !s.inRange(k) {
+				s.done = true
+				return nil
+			}
+			if scanned == scanBatchSize {
+				s.nextKey = append(s.nextKey[:0], k...)
+				return nil
+			}
+			if err := visitFn(tx, k, v); err != nil {
+				return err
+			}
+			scanned++
+		}
+	})
+	if err != nil {
+		s.done = true
+		return false, err
+	}
+	return true, nil
+}
+
+// linkIterator is a graph.LinkIterator implementation for the bolt graph.
+type linkIterator struct {
+	scanner *rangeScanner
+	filter  time.Time
+
+	links       []*graph.Link
+	curIndex    int
+	latchedLink *graph.Link
+	lastErr     error
+}
+
+// Next implements graph.LinkIterator.
+func (i *linkIterator) Next() bool {
+	for i.curIndex >= len(i.links) {
+		if i.lastErr != nil {
+			return false
+		}
+
+		i.links, i.curIndex = i.links[:0], 0
+		more, err := i.scanner.scanBatch(func(_ *bolt.Tx, _, v []byte) error {
+			link, err := decodeLink(v)
+			if err != nil {
+				return err
+			}
+			if link.RetrievedAt.Before(i.filter) {
+				i.links = append(i.links, link)
+			}
+			return nil
+		})
+		if err != nil {
+			i.lastErr = xerrors.Errorf("link iterator: %w", err)
+			return false
+		} else if !more {
+			return false
+		}
+	}
+
+	i.latchedLink = i.links[i.curIndex]
+	i.curIndex++
+	return true
+}
+
+// Error implements graph.LinkIterator.
+func (i *linkIterator) Error() error {
+	return i.lastErr
+}
+
+// Close implements graph.LinkIterator.
+func (i *linkIterator) Close() error {
+	i.scanner.done = true
+	i.links = nil
+	return nil
+}
+
+// Link implements graph.LinkIterator.
+func (i *linkIterator) Link() *graph.Link {
+	return i.latchedLink
+}
+
+// edgeIterator is a graph.EdgeIterator implementation for the bolt graph.
+type edgeIterator struct {
+	scanner *rangeScanner
+	filter  time.Time
+	// decodeFn maps a scanned bucket entry to an edge.
+	decodeFn func(tx *bolt.Tx, k, v []byte) (*graph.Edge, error)
+
+	edges       []*graph.Edge
+	curIndex    int
+	latchedEdge *graph.Edge
+	lastErr     error
+}
+
+// Next implements graph.EdgeIterator.
+func (i *edgeIterator) Next() bool {
+	for i.curIndex >= len(i.edges) {
+		if i.lastErr != nil {
+			return false
+		}
+
+		i.edges, i.curIndex = i.edges[:0], 0
+		more, err := i.scanner.scanBatch(func(tx *bolt.Tx, k, v []byte) error {
+			edge, err := i.decodeFn(tx, k, v)
+			if err != nil {
+				return err
+			}
+			if edge.UpdatedAt.Before(i.filter) {
+				i.edges = append(i.edges, edge)
+			}
+			return nil
+		})
+		if err != nil {
+			i.lastErr = xerrors.Errorf("edge iterator: %w", err)
+			return false
+		} else if !more {
+			return false
+		}
+	}
+
+	i.latchedEdge = i.edges[i.curIndex]
+	i.curIndex++
+	return true
+}
+
+// Error implements graph.EdgeIterator.
+func (i *edgeIterator) Error() error {
+	return i.lastErr
+}
+
+// Close implements graph.EdgeIterator.
+func (i *edgeIterator) Close() error {
+	i.scanner.done = true
+	i.edges = nil
+	return nil
+}
+
+// Edge implements graph.EdgeIterator.
+func (i *edgeIterator) Edge() *graph.Edge {
+	return i.latchedEdge
+}
@@ -2,59 +2,431 @@ package cockroachdb
 
 import (
 	"Search_Engine/linkgraph/graph"
+	"context"
 	"database/sql"
+	"fmt"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"golang.org/x/xerrors"
+	"strings"
 	"time"
 )
 
+// The maximum number of rows that are upserted by a single statement when
+// processing link or edge batches.
+const upsertBatchSize = 500
+
 var (
-	upsertLinkQuery = `INSERT INTO links (url, retrieved_at) VALUES ($1, $2)
-ON CONFLICT (url) DO UPDATE SET retrieved_at = GREATEST(links.retrieved_at, $2)
-RETURNING id, retrieved_at`
+	// hasNewerCrawlResult mirrors graph.Link.HasCrawlResult and holds for
+	// upserts that carry a crawl result which is not older than the stored
+	// one. Failed crawl attempts leave retrieved_at at the zero time.
+	hasNewerCrawlResult = `(excluded.retrieved_at >= links.retrieved_at AND
+    (excluded.retrieved_at > '0001-01-01' OR excluded.status_code <> 0 OR excluded.failure_count <> 0 OR excluded.next_crawl_at > '0001-01-01'))`
+
+	upsertLinkConflictClause = `ON CONFLICT (url) DO UPDATE SET
+  status_code = IF(` + hasNewerCrawlResult + `, excluded.status_code, links.status_code),
+  content_hash = IF(` + hasNewerCrawlResult + `, excluded.content_hash, links.content_hash),
+  etag = IF(` + hasNewerCrawlResult + `, excluded.etag, links.etag),
+  last_modified = IF(` + hasNewerCrawlResult + `, excluded.last_modified, links.last_modified),
+  failure_count = IF(` + hasNewerCrawlResult + `, excluded.failure_count, links.failure_count),
+  next_crawl_at = IF(` + hasNewerCrawlResult + `, excluded.next_crawl_at, links.next_crawl_at),
+  retrieved_at = GREATEST(links.retrieved_at, excluded.retrieved_at)`
+
+	// Links whose URL has a tombstone that was recorded after $9 are not
+	// inserted; the query returns no rows in that case. New links are
+	// assigned the ID bound to $10 unless it is NULL.
+	upsertLinkQuery = `INSERT INTO links (id, url, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at)
+SELECT COALESCE($10::UUID, gen_random_uuid()), $1::STRING, $2::TIMESTAMP, $3::INT, $4::STRING, $5::STRING, $6::STRING, $7::INT, $8::TIMESTAMP
+WHERE NOT EXISTS (SELECT 1 FROM link_tombstones WHERE url = $1 AND deleted_at > $9)
+` + upsertLinkConflictClause + `
+RETURNING id, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at`
+
+	// The VALUES list of upsertLinksQuery is populated by buildUpsertLinksQuery;
+	// $1 holds the tombstone cutoff.
+	upsertLinksQuery = `INSERT INTO links (id, url, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at)
+SELECT COALESCE(v.id, gen_random_uuid()), v.url, v.retrieved_at, v.status_code, v.content_hash, v.etag, v.last_modified, v.failure_count, v.next_crawl_at
+FROM (VALUES %s) AS v (id, url, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at)
+WHERE NOT EXISTS (SELECT 1 FROM link_tombstones t WHERE t.url = v.url AND t.deleted_at > $1)
+` + upsertLinkConflictClause + `
+RETURNING id, url, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at`
 
-	upsertEdgeQuery = `INSERT INTO edges (src, dst, updated_at) VALUES ($1, $2, NOW()) 
-ON CONFLICT (src, dst) DO UPDATE SET updated_at=NOW() 
+	deleteLinkQuery = `WITH deleted AS (DELETE FROM links WHERE id = $1 RETURNING url)
+UPSERT INTO link_tombstones (url, deleted_at) SELECT url, NOW() FROM deleted
+RETURNING url`
+
+	upsertEdgeConflictClause = `ON CONFLICT (src, dst) DO UPDATE SET
+  updated_at = NOW(),
+  anchor_text = excluded.anchor_text,
+  rel = excluded.rel`
+
+	upsertEdgeQuery = `INSERT INTO edges (src, dst, updated_at, anchor_text, rel) VALUES ($1, $2, NOW(), $3, $4)
+` + upsertEdgeConflictClause + `
 RETURNING id, updated_at`
 
-	findLinkQuery = `SELECT url, retrieved_at FROM links WHERE id=$1`
+	// The VALUES list of upsertEdgesQuery is populated by buildUpsertEdgesQuery.
+	upsertEdgesQuery = `INSERT INTO edges (src, dst, updated_at, anchor_text, rel) VALUES %s
+` + upsertEdgeConflictClause + `
+RETURNING id, src, dst, updated_at, anchor_text, rel`
+
+	findLinkQuery = `SELECT url, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at FROM links WHERE id=$1`
+
+	// The VALUES lists of the restore queries are populated by
+	// buildRestoreLinksQuery and buildRestoreEdgesQuery.
+	restoreLinksQuery = `UPSERT INTO links (id, url, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at) VALUES %s`
+
+	restoreEdgesQuery = `UPSERT INTO edges (id, src, dst, updated_at, anchor_text, rel) VALUES %s`
+
+	deleteTombstonesQuery = `DELETE FROM link_tombstones WHERE url = ANY($1)`
+
+	existingLinkURLsQuery = `SELECT url FROM links WHERE url = ANY($1)`
+
+	findLinkByURLQuery = `SELECT id, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at FROM links WHERE url=$1`
+
+	// The paginated queries below come in pairs: the first query fetches the
+	// first page of results while the second one resumes after the key of
+	// the last row of the previous page. The page size is always bound to the
+	// last placeholder.
+	linksInPartitionQuery = `SELECT id, url, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at FROM links
+WHERE id >= $1 AND id < $2 AND retrieved_at < $3 ORDER BY id LIMIT $4`
+
+	linksInPartitionAfterQuery = `SELECT id, url, retrieved_at, status_code, content_hash, etag, last_modified, failure_count, next_crawl_at FROM links
+WHERE id >= $1 AND id < $2 AND retrieved_at < $3 AND id > $4 ORDER BY id LIMIT $5`
+
+	edgesInPartitionQuery = `SELECT id, src, dst, updated_at, anchor_text, rel FROM edges
+WHERE src >= $1 AND src < $2 AND updated_at < $3 ORDER BY src, dst LIMIT $4`
 
-	linksInPartitionQuery = `SELECT id, url, retrieved_at FROM links WHERE id >= $1 AND id < $2 AND retrieved_at < $3`
+	edgesInPartitionAfterQuery = `SELECT id, src, dst, updated_at, anchor_text, rel FROM edges
+WHERE src >= $1 AND src < $2 AND updated_at < $3 AND (src, dst) > ($4, $5) ORDER BY src, dst LIMIT $6`
 
-	edgesInPartitionQuery = `SELECT id, src, dst, updated_at FROM edges WHERE src >= $1 AND src < $2 AND updated_at < $3`
+	edgesToQuery = `SELECT id, src, dst, updated_at, anchor_text, rel FROM edges WHERE dst = $1 ORDER BY id LIMIT $2`
+
+	edgesToAfterQuery = `SELECT id, src, dst, updated_at, anchor_text, rel FROM edges WHERE dst = $1 AND id > $2 ORDER BY id LIMIT $3`
+
+	inboundDegreeQuery = `SELECT COUNT(*) FROM edges WHERE dst = $1`
 
 	removeStaleEdgesQuery = `DELETE FROM edges WHERE src =$1 and updated_at < $2`
+
+	// The following queries maintain the edge history and are only used if
+	// it has been enabled. Open records exist only for edges that are
+	// present in the edges table so a record is opened for each upserted
+	// edge that does not have one yet.
+	openEdgeRecordsQuery = `INSERT INTO edge_history (src, dst, first_seen_at)
+SELECT e.src, e.dst, e.updated_at FROM edges e
+JOIN (SELECT unnest($1::UUID[]) AS src, unnest($2::UUID[]) AS dst) AS v ON e.src = v.src AND e.dst = v.dst
+WHERE NOT EXISTS (SELECT 1 FROM edge_history h WHERE h.src = e.src AND h.dst = e.dst AND h.removed_at IS NULL)`
+
+	removeStaleEdgesWithHistoryQuery = `WITH removed AS (DELETE FROM edges WHERE src = $1 AND updated_at < $2 RETURNING src, dst)
+UPDATE edge_history SET removed_at = NOW() FROM removed
+WHERE edge_history.src = removed.src AND edge_history.dst = removed.dst AND edge_history.removed_at IS NULL`
+
+	closeSrcEdgeRecordsQuery = `UPDATE edge_history SET removed_at = NOW() WHERE src = $1 AND removed_at IS NULL`
+
+	closeDstEdgeRecordsQuery = `UPDATE edge_history SET removed_at = NOW() WHERE dst = $1 AND removed_at IS NULL`
+
+	edgeTimelineQuery = `SELECT dst, first_seen_at, removed_at FROM edge_history WHERE src = $1 ORDER BY first_seen_at, id`
+)
+
+// Compile-time checks for ensuring CockroachDBGraph implements Graph, Restorer
+// and EdgeHistorian.
+var (
+	_ graph.Graph         = (*CockroachDBGraph)(nil)
+	_ graph.Restorer      = (*CockroachDBGraph)(nil)
+	_ graph.EdgeHistorian = (*CockroachDBGraph)(nil)
 )
 
 type CockroachDBGraph struct {
 	db *sql.DB
+
+	// Prepared statements for the queries that are executed for each
+	// crawled link.
+	upsertLinkStmt      *lazyStmt
+	upsertEdgeStmt      *lazyStmt
+	upsertHostStmt      *lazyStmt
+	recordHostFetchStmt *lazyStmt
+
+	// pageSize is the number of rows fetched by each query issued by the
+	// link and edge iterators.
+	pageSize int
+
+	// urlDerivedIDs causes new links to be assigned graph.LinkIDForURL
+	// instead of a random ID.
+	urlDerivedIDs bool
+
+	// edgeHistory enables the recording of edge history.
+	edgeHistory bool
+
+	// events only reports the changes applied through this graph instance.
+	events graph.LinkEventBroadcaster
 }
 
+// NewCockroachDBGraph returns a CockroachDBGraph instance that connects to the
+// database at dsn. Besides the parameters understood by the postgres driver,
+// URI-style DSNs may specify the following query parameters:
+//   - page_size: the number of rows fetched by each iterator query
+//     (default: 1000).
+//   - link_ids: how IDs are assigned to new links that do not specify one;
+//     either random (default) or url for IDs derived from the link URL via
+//     graph.LinkIDForURL.
+//   - edge_history: if true, the store records when edges are added and
+//     removed in the edge_history table so that they can be queried via
+//     EdgeTimeline (default: false). Edges that are modified while the
+//     history is disabled are not recorded.
+//   - max_open_conns, max_idle_conns: the maximum number of open and idle
+//     connections in the pool.
+//   - conn_max_lifetime, conn_max_idle_time: the maximum amount of time a
+//     connection may be reused or remain idle, as a Go duration (e.g. 5m).
 func NewCockroachDBGraph(dsn string) (*CockroachDBGraph, error) {
+	dsn, opts, err := parseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, err
 	}
-	return &CockroachDBGraph{db: db}, nil
+
+	g, err := newCockroachDBGraph(db, opts)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return g, nil
+}
+
+// newCockroachDBGraph configures the connection pool of db and creates the
+// statements used by the graph. The statements are prepared on first use so
+// that the database does not need to be reachable yet.
+func newCockroachDBGraph(db *sql.DB, opts graphOptions) (*CockroachDBGraph, error) {
+	if opts.maxOpenConns > 0 {
+		db.SetMaxOpenConns(opts.maxOpenConns)
+	}
+	if opts.maxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.maxIdleConns)
+	}
+	if opts.connMaxLifetime > 0 {
+		db.SetConnMaxLifetime(opts.connMaxLifetime)
+	}
+	if opts.connMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(opts.connMaxIdleTime)
+	}
+
+	return &CockroachDBGraph{
+		db:                  db,
+		upsertLinkStmt:      newLazyStmt(db, upsertLinkQuery),
+		upsertEdgeStmt:      newLazyStmt(db, upsertEdgeQuery),
+		upsertHostStmt:      newLazyStmt(db, upsertHostQuery),
+		recordHostFetchStmt: newLazyStmt(db, recordHostFetchQuery),
+		pageSize:            opts.pageSize,
+		urlDerivedIDs:       opts.urlDerivedIDs,
+		edgeHistory:         opts.edgeHistory,
+	}, nil
 }
 
 func (c *CockroachDBGraph) Close() error {
-	return c.db.Close()
+	err := c.closeStmts()
+	if dbErr := c.db.Close(); err == nil {
+		err = dbErr
+	}
+	return err
+}
+
+func (c *CockroachDBGraph) closeStmts() error {
+	var err error
+	for _, stmt := range []*lazyStmt{c.upsertLinkStmt, c.upsertEdgeStmt, c.upsertHostStmt, c.recordHostFetchStmt} {
+		if closeErr := stmt.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
 }
 
-func (c *CockroachDBGraph) UpsertLink(link *graph.Link) error {
-	row := c.db.QueryRow(upsertLinkQuery, link.URL, link.RetrievedAt.UTC())
-	if err := row.Scan(&link.ID, &link.RetrievedAt); err != nil {
+func (c *CockroachDBGraph) UpsertLink(ctx context.Context, link *graph.Link) error {
+	existing, err := c.existingLinkURLs(ctx, []*graph.Link{link})
+	if err != nil {
+		return xerrors.Errorf("upsert link: %w", err)
+	}
+
+	args := []interface{}{
+		link.URL,
+		link.RetrievedAt.UTC(),
+		link.StatusCode,
+		link.ContentHash,
+		link.ETag,
+		link.LastModified,
+		link.FailureCount,
+		link.NextCrawlAt.UTC(),
+		time.Now().Add(-graph.TombstoneTTL).UTC(),
+		c.newLinkID(link),
+	}
+	if err := withRetries(ctx, func() error {
+		return c.upsertLinkStmt.QueryRowContext(ctx, c.db, args...).Scan(
+			&link.ID, &link.RetrievedAt, &link.StatusCode, &link.ContentHash,
+			&link.ETag, &link.LastModified, &link.FailureCount, &link.NextCrawlAt,
+		)
+	}); err != nil {
+		if err == sql.ErrNoRows {
+			err = graph.ErrLinkDeleted
+		} else if isUniqueViolationError(err) {
+			err = graph.ErrIDConflict
+		}
 		return xerrors.Errorf("upsert link: %w", err)
 	}
 	link.RetrievedAt = link.RetrievedAt.UTC()
+	link.NextCrawlAt = link.NextCrawlAt.UTC()
+	c.publishUpsert(link, existing)
 	return nil
 }
 
-func (c *CockroachDBGraph) UpsertEdge(edge *graph.Edge) error {
-	row := c.db.QueryRow(upsertEdgeQuery, edge.Src, edge.Dst)
-	if err := row.Scan(&edge.ID, &edge.UpdatedAt); err != nil {
+// UpsertLinks creates or updates a batch of links using multi-row upserts.
+// Links whose URL has been tombstoned are skipped and have their ID set to
+// uuid.Nil.
+func (c *CockroachDBGraph) UpsertLinks(ctx context.Context, links []*graph.Link) error {
+	// A single statement cannot upsert the same row twice so collapse links
+	// that share a URL, keeping the last one with the most recent crawl
+	// result like consecutive single upserts would.
+	var (
+		unique   []*graph.Link
+		byURL    = make(map[string][]*graph.Link, len(links))
+		urlIndex = make(map[string]int, len(links))
+	)
+	for _, link := range links {
+		if i, found := urlIndex[link.URL]; !found {
+			urlIndex[link.URL] = len(unique)
+			unique = append(unique, link)
+		} else if link.HasCrawlResult() && !link.RetrievedAt.Before(unique[i].RetrievedAt) {
+			unique[i] = link
+		}
+		byURL[link.URL] = append(byURL[link.URL], link)
+	}
+
+	tombstoneCutoff := time.Now().Add(-graph.TombstoneTTL).UTC()
+	for len(unique) != 0 {
+		batchSize := upsertBatchSize
+		if batchSize > len(unique) {
+			batchSize = len(unique)
+		}
+
+		if err := c.upsertLinkBatch(ctx, unique[:batchSize], byURL, tombstoneCutoff); err != nil {
+			return xerrors.Errorf("upsert links: %w", err)
+		}
+		unique = unique[batchSize:]
+	}
+	return nil
+}
+
+func (c *CockroachDBGraph) upsertLinkBatch(ctx context.Context, batch []*graph.Link, byURL map[string][]*graph.Link, tombstoneCutoff time.Time) error {
+	existing, err := c.existingLinkURLs(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	args := []interface{}{tombstoneCutoff}
+	for _, link := range batch {
+		args = append(args,
+			c.newLinkID(link), link.URL, link.RetrievedAt.UTC(), link.StatusCode, link.ContentHash,
+			link.ETag, link.LastModified, link.FailureCount, link.NextCrawlAt.UTC(),
+		)
+	}
+
+	var storedLinks []*graph.Link
+	err = withRetries(ctx, func() error {
+		storedLinks = storedLinks[:0]
+		rows, err := c.db.QueryContext(ctx, buildUpsertLinksQuery(len(batch)), args...)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			stored := new(graph.Link)
+			if err := rows.Scan(
+				&stored.ID, &stored.URL, &stored.RetrievedAt, &stored.StatusCode, &stored.ContentHash,
+				&stored.ETag, &stored.LastModified, &stored.FailureCount, &stored.NextCrawlAt,
+			); err != nil {
+				return err
+			}
+			stored.RetrievedAt = stored.RetrievedAt.UTC()
+			stored.NextCrawlAt = stored.NextCrawlAt.UTC()
+			storedLinks = append(storedLinks, stored)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		if isUniqueViolationError(err) {
+			err = graph.ErrIDConflict
+		}
+		return err
+	}
+
+	// No rows are returned for tombstoned links so their IDs remain nil.
+	for _, link := range batch {
+		for _, dup := range byURL[link.URL] {
+			dup.ID = uuid.Nil
+		}
+	}
+	for _, stored := range storedLinks {
+		for _, link := range byURL[stored.URL] {
+			*link = *stored
+		}
+		c.publishUpsert(stored, existing)
+	}
+	return nil
+}
+
+// existingLinkURLs returns the set of URLs from links that are already present
+// in the graph. As the result is only needed for classifying change events,
+// the lookup is skipped when there are no active watchers.
+func (c *CockroachDBGraph) existingLinkURLs(ctx context.Context, links []*graph.Link) (map[string]bool, error) {
+	if !c.events.HasWatchers() {
+		return nil, nil
+	}
+
+	urls := make([]string, len(links))
+	for i, link := range links {
+		urls[i] = link.URL
+	}
+
+	var existing map[string]bool
+	err := withRetries(ctx, func() error {
+		existing = make(map[string]bool)
+		rows, err := c.db.QueryContext(ctx, existingLinkURLsQuery, pq.Array(urls))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var url string
+			if err := rows.Scan(&url); err != nil {
+				return err
+			}
+			existing[url] = true
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// publishUpsert emits a change event for an upserted link.
+func (c *CockroachDBGraph) publishUpsert(link *graph.Link, existing map[string]bool) {
+	if existing[link.URL] {
+		c.events.Publish(graph.LinkUpdated, link)
+	} else {
+		c.events.Publish(graph.LinkCreated, link)
+	}
+}
+
+func (c *CockroachDBGraph) UpsertEdge(ctx context.Context, edge *graph.Edge) error {
+	if err := c.withEdgeHistoryTx(ctx, func(tx *sql.Tx) error {
+		if err := c.upsertEdgeStmt.QueryRowContext(ctx, c.conn(tx), edge.Src, edge.Dst, edge.AnchorText, edge.Rel).Scan(&edge.ID, &edge.UpdatedAt); err != nil {
+			return err
+		}
+		return c.openEdgeRecords(ctx, tx, []*graph.Edge{edge})
+	}); err != nil {
 		if isForeignKeyViolationError(err) {
 			err = graph.ErrUnknownEdgeLinks
 		}
@@ -64,48 +436,459 @@ func (c *CockroachDBGraph) UpsertEdge(edge *graph.Edge) error {
 	return nil
 }
 
-func (c *CockroachDBGraph) FindLink(id uuid.UUID) (*graph.Link, error) {
-	row := c.db.QueryRow(findLinkQuery, id)
-	link := &graph.Link{ID: id}
+// UpsertEdges creates or updates a batch of edges using multi-row upserts.
+func (c *CockroachDBGraph) UpsertEdges(ctx context.Context, edges []*graph.Edge) error {
+	// A single statement cannot upsert the same row twice so collapse edges
+	// that connect the same pair of links.
+	type edgeKey struct{ src, dst uuid.UUID }
+	byKey := make(map[edgeKey][]*graph.Edge, len(edges))
+	var unique []*graph.Edge
+	for _, edge := range edges {
+		key := edgeKey{src: edge.Src, dst: edge.Dst}
+		if len(byKey[key]) == 0 {
+			unique = append(unique, edge)
+		}
+		byKey[key] = append(byKey[key], edge)
+	}
+
+	for len(unique) != 0 {
+		batchSize := upsertBatchSize
+		if batchSize > len(unique) {
+			batchSize = len(unique)
+		}
+
+		args := make([]interface{}, 0, 4*batchSize)
+		for _, edge := range unique[:batchSize] {
+			args = append(args, edge.Src, edge.Dst, edge.AnchorText, edge.Rel)
+		}
+
+		var storedEdges []*graph.Edge
+		err := c.withEdgeHistoryTx(ctx, func(tx *sql.Tx) error {
+			var err error
+			if storedEdges, err = queryEdges(ctx, c.conn(tx), buildUpsertEdgesQuery(batchSize), args); err != nil {
+				return err
+			}
+			return c.openEdgeRecords(ctx, tx, unique[:batchSize])
+		})
+		if err != nil {
+			if isForeignKeyViolationError(err) {
+				err = graph.ErrUnknownEdgeLinks
+			}
+			return xerrors.Errorf("upsert edges: %w", err)
+		}
+
+		for _, stored := range storedEdges {
+			for _, edge := range byKey[edgeKey{src: stored.Src, dst: stored.Dst}] {
+				*edge = *stored
+			}
+		}
 
-	if err := row.Scan(&link.URL, &link.RetrievedAt); err != nil {
+		unique = unique[batchSize:]
+	}
+	return nil
+}
+
+func (c *CockroachDBGraph) FindLink(ctx context.Context, id uuid.UUID) (*graph.Link, error) {
+	link := &graph.Link{ID: id}
+	if err := withRetries(ctx, func() error {
+		return c.db.QueryRowContext(ctx, findLinkQuery, id).Scan(
+			&link.URL, &link.RetrievedAt, &link.StatusCode, &link.ContentHash,
+			&link.ETag, &link.LastModified, &link.FailureCount, &link.NextCrawlAt,
+		)
+	}); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, xerrors.Errorf("find link: %w", graph.ErrNotFound)
 		}
 		return nil, xerrors.Errorf("find link: %w", err)
 	}
 	link.RetrievedAt = link.RetrievedAt.UTC()
+	link.NextCrawlAt = link.NextCrawlAt.UTC()
 	return link, nil
 }
 
-func (c *CockroachDBGraph) Links(fromID, toID uuid.UUID, accessedBefore time.Time) (graph.LinkIterator, error) {
-	rows, err := c.db.Query(linksInPartitionQuery, fromID, toID, accessedBefore.UTC())
-	if err != nil {
+// DeleteLink removes a link and records a tombstone for its URL. The edges
+// that originate from or point to the link are removed by the ON DELETE
+// CASCADE constraints of the edges table; if edge history is enabled, their
+// history records are closed in the same transaction.
+func (c *CockroachDBGraph) DeleteLink(ctx context.Context, id uuid.UUID) error {
+	var url string
+	if err := c.withEdgeHistoryTx(ctx, func(tx *sql.Tx) error {
+		if err := c.conn(tx).QueryRowContext(ctx, deleteLinkQuery, id).Scan(&url); err != nil || tx == nil {
+			return err
+		}
+		for _, query := range []string{closeSrcEdgeRecordsQuery, closeDstEdgeRecordsQuery} {
+			if _, err := tx.ExecContext(ctx, query, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		if err == sql.ErrNoRows {
+			return xerrors.Errorf("delete link: %w", graph.ErrNotFound)
+		}
+		return xerrors.Errorf("delete link: %w", err)
+	}
+	c.events.Publish(graph.LinkDeleted, &graph.Link{ID: id, URL: url})
+	return nil
+}
+
+// WatchLinks returns a watcher that receives an event for each link that is
+// created, updated or deleted through this graph instance after the call
+// returns.
+func (c *CockroachDBGraph) WatchLinks(ctx context.Context) (graph.LinkWatcher, error) {
+	return c.events.Watch(ctx), nil
+}
+
+func (c *CockroachDBGraph) FindLinkByURL(ctx context.Context, url string) (*graph.Link, error) {
+	link := &graph.Link{URL: url}
+	if err := withRetries(ctx, func() error {
+		return c.db.QueryRowContext(ctx, findLinkByURLQuery, url).Scan(
+			&link.ID, &link.RetrievedAt, &link.StatusCode, &link.ContentHash,
+			&link.ETag, &link.LastModified, &link.FailureCount, &link.NextCrawlAt,
+		)
+	}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, xerrors.Errorf("find link by URL: %w", graph.ErrNotFound)
+		}
+		return nil, xerrors.Errorf("find link by URL: %w", err)
+	}
+	link.RetrievedAt = link.RetrievedAt.UTC()
+	link.NextCrawlAt = link.NextCrawlAt.UTC()
+	return link, nil
+}
+
+// Links returns an iterator for the set of links whose IDs belong to the
+// [fromID, toID) range and were last accessed before the provided value.
+// Results are fetched in pages so no query stays open between calls to Next.
+func (c *CockroachDBGraph) Links(ctx context.Context, fromID, toID uuid.UUID, accessedBefore time.Time) (graph.LinkIterator, error) {
+	it := &linkIterator{
+		pager: c.newPager(ctx, linksInPartitionQuery, linksInPartitionAfterQuery, fromID, toID, accessedBefore.UTC()),
+	}
+	if err := it.fetchPage(); err != nil {
 		return nil, xerrors.Errorf("links: %w", err)
 	}
-	return &linkIterator{rows: rows}, nil
+	return it, nil
 }
 
-func (c *CockroachDBGraph) Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
-	rows, err := c.db.Query(edgesInPartitionQuery, fromID, toID, updatedBefore.UTC())
-	if err != nil {
+// Edges returns an iterator for the set of edges whose source vertex IDs
+// belong to the [fromID, toID) range and were last updated before the
+// provided value. Results are fetched in pages so no query stays open between
+// calls to Next.
+func (c *CockroachDBGraph) Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
+	it := &edgeIterator{
+		pager: c.newPager(ctx, edgesInPartitionQuery, edgesInPartitionAfterQuery, fromID, toID, updatedBefore.UTC()),
+		pageKey: func(e *graph.Edge) []interface{} {
+			return []interface{}{e.Src, e.Dst}
+		},
+	}
+	if err := it.fetchPage(); err != nil {
 		return nil, xerrors.Errorf("edges: %w", err)
 	}
-	return &edgeIterator{rows: rows}, nil
+	return it, nil
 }
 
-func (c *CockroachDBGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error {
-	_, err := c.db.Exec(removeStaleEdgesQuery, fromID, updatedBefore.UTC())
-	if err != nil {
+func (c *CockroachDBGraph) EdgesTo(ctx context.Context, dstID uuid.UUID) (graph.EdgeIterator, error) {
+	it := &edgeIterator{
+		pager: c.newPager(ctx, edgesToQuery, edgesToAfterQuery, dstID),
+		pageKey: func(e *graph.Edge) []interface{} {
+			return []interface{}{e.ID}
+		},
+	}
+	if err := it.fetchPage(); err != nil {
+		return nil, xerrors.Errorf("edges to: %w", err)
+	}
+	return it, nil
+}
+
+func (c *CockroachDBGraph) InboundDegree(ctx context.Context, dstID uuid.UUID) (int, error) {
+	var count int
+	if err := withRetries(ctx, func() error {
+		return c.db.QueryRowContext(ctx, inboundDegreeQuery, dstID).Scan(&count)
+	}); err != nil {
+		return 0, xerrors.Errorf("inbound degree: %w", err)
+	}
+	return count, nil
+}
+
+func (c *CockroachDBGraph) RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time) error {
+	query := removeStaleEdgesQuery
+	if c.edgeHistory {
+		query = removeStaleEdgesWithHistoryQuery
+	}
+	if err := withRetries(ctx, func() error {
+		_, err := c.db.ExecContext(ctx, query, fromID, updatedBefore.UTC())
+		return err
+	}); err != nil {
 		return xerrors.Errorf("remove stale edges: %w", err)
 	}
 	return nil
 }
 
+// RestoreLinks inserts or replaces a batch of links keeping their IDs.
+func (c *CockroachDBGraph) RestoreLinks(ctx context.Context, links []*graph.Link) error {
+	for len(links) != 0 {
+		batchSize := upsertBatchSize
+		if batchSize > len(links) {
+			batchSize = len(links)
+		}
+
+		urls := make([]string, 0, batchSize)
+		args := make([]interface{}, 0, 9*batchSize)
+		for _, link := range links[:batchSize] {
+			urls = append(urls, link.URL)
+			args = append(args,
+				link.ID, link.URL, link.RetrievedAt.UTC(), link.StatusCode, link.ContentHash,
+				link.ETag, link.LastModified, link.FailureCount, link.NextCrawlAt.UTC(),
+			)
+		}
+
+		query := buildRestoreLinksQuery(batchSize)
+		if err := withRetries(ctx, func() error {
+			_, err := c.db.ExecContext(ctx, query, args...)
+			return err
+		}); err != nil {
+			if isUniqueViolationError(err) {
+				err = graph.ErrIDConflict
+			}
+			return xerrors.Errorf("restore links: %w", err)
+		}
+		if err := withRetries(ctx, func() error {
+			_, err := c.db.ExecContext(ctx, deleteTombstonesQuery, pq.Array(urls))
+			return err
+		}); err != nil {
+			return xerrors.Errorf("restore links: %w", err)
+		}
+
+		links = links[batchSize:]
+	}
+	return nil
+}
+
+// RestoreEdges inserts or replaces a batch of edges keeping their IDs and
+// update timestamps.
+func (c *CockroachDBGraph) RestoreEdges(ctx context.Context, edges []*graph.Edge) error {
+	for len(edges) != 0 {
+		batchSize := upsertBatchSize
+		if batchSize > len(edges) {
+			batchSize = len(edges)
+		}
+
+		args := make([]interface{}, 0, 6*batchSize)
+		for _, edge := range edges[:batchSize] {
+			args = append(args, edge.ID, edge.Src, edge.Dst, edge.UpdatedAt.UTC(), edge.AnchorText, edge.Rel)
+		}
+
+		query := buildRestoreEdgesQuery(batchSize)
+		if err := c.withEdgeHistoryTx(ctx, func(tx *sql.Tx) error {
+			if _, err := c.conn(tx).ExecContext(ctx, query, args...); err != nil {
+				return err
+			}
+			return c.openEdgeRecords(ctx, tx, edges[:batchSize])
+		}); err != nil {
+			if isForeignKeyViolationError(err) {
+				err = graph.ErrUnknownEdgeLinks
+			} else if isUniqueViolationError(err) {
+				err = graph.ErrIDConflict
+			}
+			return xerrors.Errorf("restore edges: %w", err)
+		}
+
+		edges = edges[batchSize:]
+	}
+	return nil
+}
+
+// EdgeTimeline returns the history of the edges originating from the specified
+// link ID ordered by their first-seen time.
+func (c *CockroachDBGraph) EdgeTimeline(ctx context.Context, srcID uuid.UUID) ([]*graph.EdgeRecord, error) {
+	if !c.edgeHistory {
+		return nil, xerrors.Errorf("edge timeline: %w", graph.ErrEdgeHistoryDisabled)
+	}
+
+	var records []*graph.EdgeRecord
+	err := withRetries(ctx, func() error {
+		records = []*graph.EdgeRecord{}
+		rows, err := c.db.QueryContext(ctx, edgeTimelineQuery, srcID)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var removedAt sql.NullTime
+			rec := &graph.EdgeRecord{Src: srcID}
+			if err := rows.Scan(&rec.Dst, &rec.FirstSeenAt, &removedAt); err != nil {
+				return err
+			}
+			rec.FirstSeenAt = rec.FirstSeenAt.UTC()
+			if removedAt.Valid {
+				rec.RemovedAt = removedAt.Time.UTC()
+			}
+			records = append(records, rec)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, xerrors.Errorf("edge timeline: %w", err)
+	}
+	return records, nil
+}
+
+// openEdgeRecords opens a history record within tx for each of the specified
+// edges that does not already have one. It is a no-op if edge history is
+// disabled.
+func (c *CockroachDBGraph) openEdgeRecords(ctx context.Context, tx *sql.Tx, edges []*graph.Edge) error {
+	if !c.edgeHistory || len(edges) == 0 {
+		return nil
+	}
+
+	srcs := make([]string, len(edges))
+	dsts := make([]string, len(edges))
+	for i, edge := range edges {
+		srcs[i], dsts[i] = edge.Src.String(), edge.Dst.String()
+	}
+	_, err := tx.ExecContext(ctx, openEdgeRecordsQuery, pq.Array(srcs), pq.Array(dsts))
+	return err
+}
+
+// dbConn is implemented by both sql.DB and sql.Tx.
+type dbConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns tx or the database if tx is nil.
+func (c *CockroachDBGraph) conn(tx *sql.Tx) dbConn {
+	if tx == nil {
+		return c.db
+	}
+	return tx
+}
+
+// withEdgeHistoryTx invokes fn with a transaction if edge history is enabled
+// so that changes to the edges and their history records are committed
+// atomically. Otherwise, fn is invoked with a nil transaction. In both cases,
+// fn is retried as a whole if it fails with a retryable error.
+func (c *CockroachDBGraph) withEdgeHistoryTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if !c.edgeHistory {
+		return withRetries(ctx, func() error { return fn(nil) })
+	}
+	return withRetries(ctx, func() error {
+		tx, err := c.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if err = fn(tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	})
+}
+
+// queryEdges runs a query that returns edge rows and scans them.
+func queryEdges(ctx context.Context, conn dbConn, query string, args []interface{}) ([]*graph.Edge, error) {
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var edges []*graph.Edge
+	for rows.Next() {
+		edge := new(graph.Edge)
+		if err := rows.Scan(&edge.ID, &edge.Src, &edge.Dst, &edge.UpdatedAt, &edge.AnchorText, &edge.Rel); err != nil {
+			return nil, err
+		}
+		edge.UpdatedAt = edge.UpdatedAt.UTC()
+		edges = append(edges, edge)
+	}
+	return edges, rows.Err()
+}
+
+// buildRestoreLinksQuery returns a restoreLinksQuery for numLinks links.
+func buildRestoreLinksQuery(numLinks int) string {
+	return fmt.Sprintf(restoreLinksQuery, placeholderRows(numLinks, 9))
+}
+
+// buildRestoreEdgesQuery returns a restoreEdgesQuery for numEdges edges.
+func buildRestoreEdgesQuery(numEdges int) string {
+	return fmt.Sprintf(restoreEdgesQuery, placeholderRows(numEdges, 6))
+}
+
+// placeholderRows returns a list of numRows parenthesized rows with
+// numCols sequentially numbered placeholders each.
+func placeholderRows(numRows, numCols int) string {
+	var b strings.Builder
+	for row := 0; row < numRows; row++ {
+		if row != 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for col := 0; col < numCols; col++ {
+			if col != 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "$%d", row*numCols+col+1)
+		}
+		b.WriteByte(')')
+	}
+	return b.String()
+}
+
+// buildUpsertLinksQuery returns an upsertLinksQuery for upserting numLinks
+// links. The tombstone cutoff is bound to $1 and each link occupies the next 9
+// placeholders.
+func buildUpsertLinksQuery(numLinks int) string {
+	values := make([]string, numLinks)
+	for i := range values {
+		base := 2 + 9*i
+		values[i] = fmt.Sprintf(
+			"($%d::UUID, $%d::STRING, $%d::TIMESTAMP, $%d::INT, $%d::STRING, $%d::STRING, $%d::STRING, $%d::INT, $%d::TIMESTAMP)",
+			base, base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8,
+		)
+	}
+	return fmt.Sprintf(upsertLinksQuery, strings.Join(values, ", "))
+}
+
+// buildUpsertEdgesQuery returns an upsertEdgesQuery for upserting numEdges
+// edges. Each edge occupies four placeholders for its source, destination,
+// anchor text and rel attribute.
+func buildUpsertEdgesQuery(numEdges int) string {
+	values := make([]string, numEdges)
+	for i := range values {
+		base := 1 + 4*i
+		values[i] = fmt.Sprintf("($%d::UUID, $%d::UUID, NOW(), $%d::STRING, $%d::STRING)", base, base+1, base+2, base+3)
+	}
+	return fmt.Sprintf(upsertEdgesQuery, strings.Join(values, ", "))
+}
+
+// newLinkID returns the value to bind as the ID of link in case it gets
+// inserted. It returns NULL if the database should assign a random ID.
+func (c *CockroachDBGraph) newLinkID(link *graph.Link) interface{} {
+	switch {
+	case link.ID != uuid.Nil:
+		return link.ID
+	case c.urlDerivedIDs:
+		return graph.LinkIDForURL(link.URL)
+	default:
+		return nil
+	}
+}
+
+func isUniqueViolationError(err error) bool {
+	pqErr, valid := err.(*pq.Error)
+	if !valid {
+		return false
+	}
+	return pqErr.Code.Name() == "unique_violation"
+}
+
 func isForeignKeyViolationError(err error) bool {
 	pqErr, valid := err.(*pq.Error)
 	if !valid {
 		return false
 	}
-	return pqErr.Code.Name() == "foreign_key_valuation"
+	return pqErr.Code.Name() == "foreign_key_violation"
 }
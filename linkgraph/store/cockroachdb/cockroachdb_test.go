@@ -0,0 +1,232 @@
+package cockroachdb
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"time"
+)
+
+var _ = gc.Suite(new(CockroachDBGraphTestSuite))
+
+// CockroachDBGraphTestSuite exercises the retry and pagination logic of the
+// store against a fake database driver.
+type CockroachDBGraphTestSuite struct {
+	d *fakeDriver
+	g *CockroachDBGraph
+}
+
+func (s *CockroachDBGraphTestSuite) SetUpTest(c *gc.C) {
+	s.d = new(fakeDriver)
+	g, err := newCockroachDBGraph(sql.OpenDB(s.d), graphOptions{pageSize: 2})
+	c.Assert(err, gc.IsNil)
+	s.g = g
+}
+
+func (s *CockroachDBGraphTestSuite) TearDownTest(c *gc.C) {
+	c.Assert(s.g.Close(), gc.IsNil)
+}
+
+func (s *CockroachDBGraphTestSuite) TestPrepareStatementsLazily(c *gc.C) {
+	c.Assert(s.d.prepared, gc.HasLen, 0)
+	s.d.handler = func(string, []driver.Value) (driver.Rows, error) {
+		return &fakeRows{
+			cols: []string{"id", "updated_at"},
+			rows: [][]driver.Value{{uuid.New().String(), time.Now()}},
+		}, nil
+	}
+
+	// Statements that cannot be prepared are executed unprepared.
+	s.d.prepareErr = xerrors.New("connection refused")
+	c.Assert(s.g.UpsertEdge(context.Background(), &graph.Edge{Src: uuid.New(), Dst: uuid.New()}), gc.IsNil)
+	c.Assert(s.d.executedCount(upsertEdgeQuery), gc.Equals, 1)
+
+	// The preparation is retried on the next use and the prepared
+	// statement is reused afterwards.
+	s.d.prepareErr = nil
+	for i := 0; i < 2; i++ {
+		c.Assert(s.g.UpsertEdge(context.Background(), &graph.Edge{Src: uuid.New(), Dst: uuid.New()}), gc.IsNil)
+	}
+	c.Assert(s.d.executedCount(upsertEdgeQuery), gc.Equals, 3)
+	c.Assert(s.d.prepared, gc.DeepEquals, []string{upsertEdgeQuery, upsertEdgeQuery})
+}
+
+func (s *CockroachDBGraphTestSuite) TestRetrySerializationFailure(c *gc.C) {
+	linkID := uuid.New()
+	failures := 2
+	s.d.handler = func(query string, _ []driver.Value) (driver.Rows, error) {
+		if failures > 0 {
+			failures--
+			return nil, &pq.Error{Code: "40001"}
+		}
+		return &fakeRows{
+			cols: []string{"url", "retrieved_at", "status_code", "content_hash", "etag", "last_modified", "failure_count", "next_crawl_at"},
+			rows: [][]driver.Value{{"http://example.com", time.Time{}, int64(200), "", "", "", int64(0), time.Time{}}},
+		}, nil
+	}
+
+	link, err := s.g.FindLink(context.Background(), linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(link.URL, gc.Equals, "http://example.com")
+	c.Assert(s.d.executedCount(findLinkQuery), gc.Equals, 3)
+}
+
+func (s *CockroachDBGraphTestSuite) TestRetriesExhausted(c *gc.C) {
+	s.d.handler = func(string, []driver.Value) (driver.Rows, error) {
+		return nil, &pq.Error{Code: "40001"}
+	}
+
+	err := s.g.UpsertEdge(context.Background(), &graph.Edge{Src: uuid.New(), Dst: uuid.New()})
+	c.Assert(isRetryableError(xerrors.Unwrap(err)), gc.Equals, true)
+	c.Assert(s.d.executedCount(upsertEdgeQuery), gc.Equals, maxRetries+1)
+}
+
+func (s *CockroachDBGraphTestSuite) TestNonRetryableError(c *gc.C) {
+	s.d.handler = func(string, []driver.Value) (driver.Rows, error) {
+		return nil, &pq.Error{Code: "23503"}
+	}
+
+	err := s.g.UpsertEdge(context.Background(), &graph.Edge{Src: uuid.New(), Dst: uuid.New()})
+	c.Assert(xerrors.Is(err, graph.ErrUnknownEdgeLinks), gc.Equals, true)
+	c.Assert(s.d.executedCount(upsertEdgeQuery), gc.Equals, 1)
+}
+
+func (s *CockroachDBGraphTestSuite) TestRetryHonorsContext(c *gc.C) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.d.handler = func(string, []driver.Value) (driver.Rows, error) {
+		cancel()
+		return nil, &pq.Error{Code: "40001"}
+	}
+
+	err := s.g.RemoveStaleEdges(ctx, uuid.New(), time.Now())
+	c.Assert(err, gc.NotNil)
+	c.Assert(s.d.executedCount(removeStaleEdgesQuery), gc.Equals, 1)
+}
+
+func (s *CockroachDBGraphTestSuite) TestLinksPagination(c *gc.C) {
+	ids := make([]uuid.UUID, 5)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	failedOnce := false
+	s.d.handler = func(query string, args []driver.Value) (driver.Rows, error) {
+		from := 0
+		if query == linksInPartitionAfterQuery {
+			// Fail the first attempt to fetch the second page.
+			if !failedOnce {
+				failedOnce = true
+				return nil, xerrors.New("connection reset")
+			}
+			lastID := uuid.MustParse(args[3].(string))
+			for ids[from] != lastID {
+				from++
+			}
+			from++
+		}
+
+		rows := &fakeRows{cols: []string{"id", "url", "retrieved_at", "status_code", "content_hash", "etag", "last_modified", "failure_count", "next_crawl_at"}}
+		for i := from; i < len(ids) && i < from+int(args[len(args)-1].(int64)); i++ {
+			rows.rows = append(rows.rows, []driver.Value{ids[i].String(), "", time.Time{}, int64(0), "", "", "", int64(0), time.Time{}})
+		}
+		return rows, nil
+	}
+
+	it, err := s.g.Links(context.Background(), uuid.Nil, uuid.MustParse("ffffffff-ffff-ffff-ffff-ffffffffffff"), time.Now())
+	c.Assert(err, gc.IsNil)
+
+	var seen []uuid.UUID
+	for it.Next() {
+		seen = append(seen, it.Link().ID)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(seen, gc.DeepEquals, ids)
+	c.Assert(s.d.executedCount(linksInPartitionQuery), gc.Equals, 1)
+	c.Assert(s.d.executedCount(linksInPartitionAfterQuery), gc.Equals, 3)
+}
+
+func (s *CockroachDBGraphTestSuite) TestURLDerivedIDs(c *gc.C) {
+	g, err := newCockroachDBGraph(sql.OpenDB(s.d), graphOptions{urlDerivedIDs: true})
+	c.Assert(err, gc.IsNil)
+	defer func() { _ = g.Close() }()
+
+	var boundID driver.Value
+	s.d.handler = func(query string, args []driver.Value) (driver.Rows, error) {
+		boundID = args[9]
+		return &fakeRows{
+			cols: []string{"id", "retrieved_at", "status_code", "content_hash", "etag", "last_modified", "failure_count", "next_crawl_at"},
+			rows: [][]driver.Value{{args[9], time.Time{}, int64(0), "", "", "", int64(0), time.Time{}}},
+		}, nil
+	}
+
+	link := &graph.Link{URL: "https://example.com"}
+	c.Assert(g.UpsertLink(context.Background(), link), gc.IsNil)
+	c.Assert(boundID, gc.Equals, graph.LinkIDForURL(link.URL).String())
+	c.Assert(link.ID, gc.Equals, graph.LinkIDForURL(link.URL))
+
+	// Caller-supplied IDs take precedence.
+	link = &graph.Link{ID: uuid.New(), URL: "https://example.com/a"}
+	c.Assert(g.UpsertLink(context.Background(), link), gc.IsNil)
+	c.Assert(boundID, gc.Equals, link.ID.String())
+}
+
+func (s *CockroachDBGraphTestSuite) TestEdgeHistory(c *gc.C) {
+	_, err := s.g.EdgeTimeline(context.Background(), uuid.New())
+	c.Assert(xerrors.Is(err, graph.ErrEdgeHistoryDisabled), gc.Equals, true)
+
+	g, err := newCockroachDBGraph(sql.OpenDB(s.d), graphOptions{edgeHistory: true})
+	c.Assert(err, gc.IsNil)
+	defer func() { _ = g.Close() }()
+
+	src, dst := uuid.New(), uuid.New()
+	firstSeenAt := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	removedAt := firstSeenAt.Add(time.Minute)
+	s.d.handler = func(query string, _ []driver.Value) (driver.Rows, error) {
+		switch query {
+		case upsertEdgeQuery:
+			return &fakeRows{
+				cols: []string{"id", "updated_at"},
+				rows: [][]driver.Value{{uuid.New().String(), firstSeenAt}},
+			}, nil
+		case deleteLinkQuery:
+			return &fakeRows{cols: []string{"url"}, rows: [][]driver.Value{{"http://example.com"}}}, nil
+		case edgeTimelineQuery:
+			return &fakeRows{
+				cols: []string{"dst", "first_seen_at", "removed_at"},
+				rows: [][]driver.Value{
+					{dst.String(), firstSeenAt, removedAt},
+					{dst.String(), firstSeenAt.Add(time.Hour), nil},
+				},
+			}, nil
+		}
+		return &fakeRows{}, nil
+	}
+
+	// Upserted edges get a history record in the same transaction and
+	// removals close it.
+	c.Assert(g.UpsertEdge(context.Background(), &graph.Edge{Src: src, Dst: dst}), gc.IsNil)
+	c.Assert(s.d.executedCount(openEdgeRecordsQuery), gc.Equals, 1)
+	c.Assert(s.d.committed, gc.Equals, 1)
+	c.Assert(g.RemoveStaleEdges(context.Background(), src, time.Now()), gc.IsNil)
+	c.Assert(s.d.executedCount(removeStaleEdgesWithHistoryQuery), gc.Equals, 1)
+	c.Assert(s.d.executedCount(removeStaleEdgesQuery), gc.Equals, 0)
+
+	// Deleting a link closes the records of its edges in both directions.
+	c.Assert(g.DeleteLink(context.Background(), dst), gc.IsNil)
+	c.Assert(s.d.executedCount(closeSrcEdgeRecordsQuery), gc.Equals, 1)
+	c.Assert(s.d.executedCount(closeDstEdgeRecordsQuery), gc.Equals, 1)
+	c.Assert(s.d.committed, gc.Equals, 2)
+
+	records, err := g.EdgeTimeline(context.Background(), src)
+	c.Assert(err, gc.IsNil)
+	c.Assert(records, gc.DeepEquals, []*graph.EdgeRecord{
+		{Src: src, Dst: dst, FirstSeenAt: firstSeenAt, RemovedAt: removedAt},
+		{Src: src, Dst: dst, FirstSeenAt: firstSeenAt.Add(time.Hour)},
+	})
+}
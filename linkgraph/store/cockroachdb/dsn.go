@@ -0,0 +1,119 @@
+package cockroachdb
+
+import (
+	"golang.org/x/xerrors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// The number of rows fetched by each iterator query unless overridden by the
+// page_size DSN parameter.
+const defaultPageSize = 1000
+
+// graphOptions holds the store settings that are specified as DSN query
+// parameters.
+type graphOptions struct {
+	pageSize int
+
+	// urlDerivedIDs is set when new links are assigned IDs derived from
+	// their URL.
+	urlDerivedIDs bool
+
+	// edgeHistory is set when the store records edge history.
+	edgeHistory bool
+
+	// The connection pool settings. Zero values retain the defaults of the
+	// database/sql package.
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+	connMaxIdleTime time.Duration
+}
+
+// parseDSN extracts the store-specific query parameters from a URI-style DSN
+// and returns the DSN that should be passed to the postgres driver. DSNs in
+// the key=value format are returned unchanged.
+func parseDSN(dsn string) (string, graphOptions, error) {
+	opts := graphOptions{pageSize: defaultPageSize}
+
+	u, err := url.Parse(dsn)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") {
+		return dsn, opts, nil
+	}
+
+	params := u.Query()
+	for _, p := range []struct {
+		name  string
+		parse func(string) error
+	}{
+		{"page_size", positiveIntParam(&opts.pageSize)},
+		{"link_ids", linkIDsParam(&opts.urlDerivedIDs)},
+		{"edge_history", boolParam(&opts.edgeHistory)},
+		{"max_open_conns", positiveIntParam(&opts.maxOpenConns)},
+		{"max_idle_conns", positiveIntParam(&opts.maxIdleConns)},
+		{"conn_max_lifetime", durationParam(&opts.connMaxLifetime)},
+		{"conn_max_idle_time", durationParam(&opts.connMaxIdleTime)},
+	} {
+		if v := params.Get(p.name); v != "" {
+			if err := p.parse(v); err != nil {
+				return "", opts, xerrors.Errorf("parse DSN: invalid %s value %q", p.name, v)
+			}
+		}
+		params.Del(p.name)
+	}
+
+	u.RawQuery = params.Encode()
+	return u.String(), opts, nil
+}
+
+func positiveIntParam(dst *int) func(string) error {
+	return func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return err
+		} else if n <= 0 {
+			return xerrors.New("value must be positive")
+		}
+		*dst = n
+		return nil
+	}
+}
+
+func linkIDsParam(urlDerivedIDs *bool) func(string) error {
+	return func(v string) error {
+		switch v {
+		case "random":
+			*urlDerivedIDs = false
+		case "url":
+			*urlDerivedIDs = true
+		default:
+			return xerrors.New("value must be either random or url")
+		}
+		return nil
+	}
+}
+
+func boolParam(dst *bool) func(string) error {
+	return func(v string) error {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return err
+		}
+		*dst = b
+		return nil
+	}
+}
+
+func durationParam(dst *time.Duration) func(string) error {
+	return func(v string) error {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return err
+		} else if d <= 0 {
+			return xerrors.New("value must be positive")
+		}
+		*dst = d
+		return nil
+	}
+}
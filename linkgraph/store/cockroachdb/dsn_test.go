@@ -0,0 +1,53 @@
+package cockroachdb
+
+import (
+	gc "gopkg.in/check.v1"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(DSNTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type DSNTestSuite struct{}
+
+func (s *DSNTestSuite) TestParseDSN(c *gc.C) {
+	dsn, opts, err := parseDSN("postgresql://root@localhost:26257/linkgraph?sslmode=disable&page_size=50")
+	c.Assert(err, gc.IsNil)
+	c.Assert(dsn, gc.Equals, "postgresql://root@localhost:26257/linkgraph?sslmode=disable")
+	c.Assert(opts.pageSize, gc.Equals, 50)
+
+	dsn, opts, err = parseDSN("host=localhost dbname=linkgraph")
+	c.Assert(err, gc.IsNil)
+	c.Assert(dsn, gc.Equals, "host=localhost dbname=linkgraph")
+	c.Assert(opts.pageSize, gc.Equals, defaultPageSize)
+
+	dsn, opts, err = parseDSN("postgresql://localhost/linkgraph?max_open_conns=20&max_idle_conns=5&conn_max_lifetime=5m&conn_max_idle_time=30s")
+	c.Assert(err, gc.IsNil)
+	c.Assert(dsn, gc.Equals, "postgresql://localhost/linkgraph")
+	c.Assert(opts, gc.DeepEquals, graphOptions{
+		pageSize:        defaultPageSize,
+		maxOpenConns:    20,
+		maxIdleConns:    5,
+		connMaxLifetime: 5 * time.Minute,
+		connMaxIdleTime: 30 * time.Second,
+	})
+
+	_, opts, err = parseDSN("postgresql://localhost/linkgraph?link_ids=url")
+	c.Assert(err, gc.IsNil)
+	c.Assert(opts.urlDerivedIDs, gc.Equals, true)
+
+	_, opts, err = parseDSN("postgresql://localhost/linkgraph?edge_history=true")
+	c.Assert(err, gc.IsNil)
+	c.Assert(opts.edgeHistory, gc.Equals, true)
+
+	_, _, err = parseDSN("postgresql://localhost/linkgraph?link_ids=sequential")
+	c.Assert(err, gc.ErrorMatches, ".*invalid link_ids.*")
+
+	_, _, err = parseDSN("postgresql://localhost/linkgraph?conn_max_lifetime=forever")
+	c.Assert(err, gc.ErrorMatches, ".*invalid conn_max_lifetime.*")
+
+	_, _, err = parseDSN("postgresql://localhost/linkgraph?page_size=0")
+	c.Assert(err, gc.ErrorMatches, ".*invalid page_size.*")
+}
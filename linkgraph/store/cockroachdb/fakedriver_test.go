@@ -0,0 +1,139 @@
+package cockroachdb
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"sync"
+)
+
+// fakeDriver is a database/sql driver whose queries are answered by a handler
+// function. It records the queries that are prepared and executed and the
+// number of committed transactions. Queries that are not explicitly prepared
+// are executed without a prepared statement.
+type fakeDriver struct {
+	handler func(query string, args []driver.Value) (driver.Rows, error)
+
+	// prepareErr, if set, is returned when preparing statements.
+	prepareErr error
+
+	mu        sync.Mutex
+	prepared  []string
+	executed  []string
+	committed int
+}
+
+// Connect implements driver.Connector.
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+// Driver implements driver.Connector.
+func (d *fakeDriver) Driver() driver.Driver { return nil }
+
+// executedCount returns the number of times query has been executed.
+func (d *fakeDriver) executedCount(query string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var count int
+	for _, q := range d.executed {
+		if q == query {
+			count++
+		}
+	}
+	return count
+}
+
+func (d *fakeDriver) run(query string, args []driver.Value) (driver.Rows, error) {
+	d.mu.Lock()
+	d.executed = append(d.executed, query)
+	d.mu.Unlock()
+	if d.handler == nil {
+		return &fakeRows{}, nil
+	}
+	return d.handler(query, args)
+}
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.d.mu.Lock()
+	c.d.prepared = append(c.d.prepared, query)
+	err := c.d.prepareErr
+	c.d.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return &fakeStmt{d: c.d, query: query}, nil
+}
+
+// QueryContext implements driver.QueryerContext.
+func (c *fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.d.run(query, namedValues(args))
+}
+
+// ExecContext implements driver.ExecerContext.
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return (&fakeStmt{d: c.d, query: query}).Exec(namedValues(args))
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{d: c.d}, nil }
+
+type fakeTx struct{ d *fakeDriver }
+
+func (tx *fakeTx) Commit() error {
+	tx.d.mu.Lock()
+	tx.d.committed++
+	tx.d.mu.Unlock()
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	d     *fakeDriver
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	rows, err := s.d.run(s.query, args)
+	if err != nil {
+		return nil, err
+	}
+	_ = rows.Close()
+	return driver.RowsAffected(0), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.d.run(s.query, args)
+}
+
+// fakeRows is a driver.Rows implementation that returns a fixed set of rows.
+type fakeRows struct {
+	cols []string
+	rows [][]driver.Value
+	next int
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next == len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.next])
+	r.next++
+	return nil
+}
+
+func namedValues(args []driver.NamedValue) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	return values
+}
@@ -0,0 +1,88 @@
+package cockroachdb
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"context"
+	"database/sql"
+	"golang.org/x/xerrors"
+	"time"
+)
+
+var (
+	upsertHostQuery = `INSERT INTO hosts (name, robots_txt, robots_fetched_at, crawl_delay_ms, blocked, block_reason)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (name) DO UPDATE SET
+  robots_txt = excluded.robots_txt,
+  robots_fetched_at = excluded.robots_fetched_at,
+  crawl_delay_ms = excluded.crawl_delay_ms,
+  blocked = excluded.blocked,
+  block_reason = excluded.block_reason
+RETURNING last_contacted_at, fetch_count, error_count`
+
+	findHostQuery = `SELECT robots_txt, robots_fetched_at, crawl_delay_ms, blocked, block_reason, last_contacted_at, fetch_count, error_count FROM hosts WHERE name = $1`
+
+	recordHostFetchQuery = `INSERT INTO hosts (name, last_contacted_at, fetch_count, error_count) VALUES ($1, $2, 1, $3)
+ON CONFLICT (name) DO UPDATE SET
+  last_contacted_at = GREATEST(hosts.last_contacted_at, excluded.last_contacted_at),
+  fetch_count = hosts.fetch_count + 1,
+  error_count = hosts.error_count + excluded.error_count`
+)
+
+// UpsertHost creates a new host or updates the crawl settings of an existing
+// host. Crawl delays are stored with millisecond precision.
+func (c *CockroachDBGraph) UpsertHost(ctx context.Context, host *graph.Host) error {
+	if err := withRetries(ctx, func() error {
+		return c.upsertHostStmt.QueryRowContext(
+			ctx,
+			c.db,
+			host.Name,
+			host.RobotsTxt,
+			host.RobotsFetchedAt.UTC(),
+			host.CrawlDelay.Milliseconds(),
+			host.Blocked,
+			host.BlockReason,
+		).Scan(&host.LastContactedAt, &host.FetchCount, &host.ErrorCount)
+	}); err != nil {
+		return xerrors.Errorf("upsert host: %w", err)
+	}
+	host.RobotsFetchedAt = host.RobotsFetchedAt.UTC()
+	host.LastContactedAt = host.LastContactedAt.UTC()
+	host.CrawlDelay = host.CrawlDelay.Truncate(time.Millisecond)
+	return nil
+}
+
+// FindHost looks up a host by its name.
+func (c *CockroachDBGraph) FindHost(ctx context.Context, name string) (*graph.Host, error) {
+	var crawlDelayMs int64
+	host := &graph.Host{Name: name}
+	if err := withRetries(ctx, func() error {
+		return c.db.QueryRowContext(ctx, findHostQuery, name).Scan(
+			&host.RobotsTxt, &host.RobotsFetchedAt, &crawlDelayMs, &host.Blocked,
+			&host.BlockReason, &host.LastContactedAt, &host.FetchCount, &host.ErrorCount,
+		)
+	}); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, xerrors.Errorf("find host: %w", graph.ErrNotFound)
+		}
+		return nil, xerrors.Errorf("find host: %w", err)
+	}
+	host.CrawlDelay = time.Duration(crawlDelayMs) * time.Millisecond
+	host.RobotsFetchedAt = host.RobotsFetchedAt.UTC()
+	host.LastContactedAt = host.LastContactedAt.UTC()
+	return host, nil
+}
+
+// RecordHostFetch records a fetch attempt for the specified host.
+func (c *CockroachDBGraph) RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error {
+	var errorCount int
+	if failed {
+		errorCount = 1
+	}
+	if err := withRetries(ctx, func() error {
+		_, err := c.recordHostFetchStmt.ExecContext(ctx, c.db, name, fetchedAt.UTC(), errorCount)
+		return err
+	}); err != nil {
+		return xerrors.Errorf("record host fetch: %w", err)
+	}
+	return nil
+}
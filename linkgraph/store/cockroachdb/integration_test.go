@@ -0,0 +1,91 @@
+package cockroachdb
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"database/sql"
+	gc "gopkg.in/check.v1"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+var _ = gc.Suite(new(CockroachDBGraphIntegrationTestSuite))
+var _ = gc.Suite(&CockroachDBGraphWithHistoryTestSuite{integrationSuiteBase{edgeHistory: true}})
+
+// CockroachDBGraphIntegrationTestSuite runs the shared test suite against the
+// CockroachDB database specified by the CDB_DSN environment variable.
+type CockroachDBGraphIntegrationTestSuite struct {
+	integrationSuiteBase
+}
+
+// CockroachDBGraphWithHistoryTestSuite runs the shared test suite against a
+// CockroachDB-backed graph that keeps the edge history.
+type CockroachDBGraphWithHistoryTestSuite struct {
+	integrationSuiteBase
+}
+
+// integrationSuiteBase sets up the graph for the integration test suites. The
+// tests are skipped unless CDB_DSN is set to a URI-style DSN, e.g.
+// postgresql://root@localhost:26257/linkgraph_test?sslmode=disable. The
+// schema migrations are applied to the database before running the tests and
+// all of its tables are truncated before each test.
+type integrationSuiteBase struct {
+	graph.SuiteBase
+	edgeHistory bool
+
+	dsn string
+	db  *sql.DB
+	g   *CockroachDBGraph
+}
+
+func (s *integrationSuiteBase) SetUpSuite(c *gc.C) {
+	dsn := os.Getenv("CDB_DSN")
+	if dsn == "" {
+		c.Skip("Missing CDB_DSN envvar; skipping cockroachdb-backed graph test suite")
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	c.Assert(err, gc.IsNil)
+	s.db = db
+
+	migrations, err := filepath.Glob(filepath.Join("migrations", "*.up.sql"))
+	c.Assert(err, gc.IsNil)
+	for _, migration := range migrations {
+		stmts, err := ioutil.ReadFile(migration)
+		c.Assert(err, gc.IsNil)
+		_, err = s.db.Exec(string(stmts))
+		c.Assert(err, gc.IsNil, gc.Commentf("applying %s", migration))
+	}
+
+	u, err := url.Parse(dsn)
+	c.Assert(err, gc.IsNil)
+	if s.edgeHistory {
+		q := u.Query()
+		q.Set("edge_history", "true")
+		u.RawQuery = q.Encode()
+	}
+	s.dsn = u.String()
+}
+
+func (s *integrationSuiteBase) SetUpTest(c *gc.C) {
+	_, err := s.db.Exec("TRUNCATE links, edges, edge_history, link_tombstones, hosts CASCADE")
+	c.Assert(err, gc.IsNil)
+
+	s.g, err = NewCockroachDBGraph(s.dsn)
+	c.Assert(err, gc.IsNil)
+	s.SetGraph(s.g)
+}
+
+func (s *integrationSuiteBase) TearDownTest(c *gc.C) {
+	if s.g != nil {
+		c.Assert(s.g.Close(), gc.IsNil)
+		s.g = nil
+	}
+}
+
+func (s *integrationSuiteBase) TearDownSuite(c *gc.C) {
+	if s.db != nil {
+		c.Assert(s.db.Close(), gc.IsNil)
+	}
+}
@@ -2,41 +2,168 @@ package cockroachdb
 
 import (
 	"Search_Engine/linkgraph/graph"
+	"context"
 	"database/sql"
 	"golang.org/x/xerrors"
 )
 
+// The number of times a failed page query is retried before the error is
+// reported by the iterator.
+const maxPageRetries = 3
+
+// pager fetches the results of a query in pages using keyset pagination. The
+// first page is fetched by firstQuery; subsequent pages are fetched by
+// afterQuery which expects the key of the last row of the previous page to
+// be bound to the placeholders that follow args.
+type pager struct {
+	db  *sql.DB
+	ctx context.Context
+
+	firstQuery string
+	afterQuery string
+	args       []interface{}
+	pageSize   int
+
+	lastKey []interface{}
+	done    bool
+}
+
+func (c *CockroachDBGraph) newPager(ctx context.Context, firstQuery, afterQuery string, args ...interface{}) *pager {
+	pageSize := c.pageSize
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	return &pager{
+		db:         c.db,
+		ctx:        ctx,
+		firstQuery: firstQuery,
+		afterQuery: afterQuery,
+		args:       args,
+		pageSize:   pageSize,
+	}
+}
+
+// nextPage runs the query for the next page of results and passes the
+// returned rows to scanPage which must return the number of scanned rows and
+// the key of the last one. Failed attempts are retried with an exponential
+// backoff; as each page is fetched by a separate query, a retry never yields
+// rows that have already been scanned.
+func (p *pager) nextPage(scanPage func(*sql.Rows) (int, []interface{}, error)) error {
+	if p.done {
+		return nil
+	}
+
+	query, args := p.firstQuery, append([]interface{}(nil), p.args...)
+	if p.lastKey != nil {
+		query = p.afterQuery
+		args = append(args, p.lastKey...)
+	}
+	args = append(args, p.pageSize)
+
+	var (
+		count   int
+		lastKey []interface{}
+	)
+	err := retry(p.ctx, maxPageRetries, retryAnyError, func() error {
+		var err error
+		count, lastKey, err = p.runPage(query, args, scanPage)
+		return err
+	})
+	if err != nil {
+		p.done = true
+		return err
+	}
+
+	p.done = count < p.pageSize
+	p.lastKey = lastKey
+	return nil
+}
+
+// retryAnyError allows page queries to be retried regardless of the error
+// they failed with.
+func retryAnyError(error) bool { return true }
+
+func (p *pager) runPage(query string, args []interface{}, scanPage func(*sql.Rows) (int, []interface{}, error)) (int, []interface{}, error) {
+	rows, err := p.db.QueryContext(p.ctx, query, args...)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	count, lastKey, err := scanPage(rows)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err = rows.Err(); err != nil {
+		return 0, nil, err
+	}
+	return count, lastKey, nil
+}
+
 // linkIterator is a graph.LinkIterator implementation for the cdb graph
 type linkIterator struct {
-	rows        *sql.Rows
+	pager *pager
+
+	links       []*graph.Link
+	curIndex    int
 	lastErr     error
 	latchedLink *graph.Link
 }
 
 func (i *linkIterator) Next() bool {
-	if i.lastErr != nil || !i.rows.Next() {
+	if i.lastErr != nil {
 		return false
 	}
 
-	l := new(graph.Link)
-	i.lastErr = i.rows.Scan(&l.ID, &l.URL, &l.RetrievedAt)
-	if i.lastErr != nil {
-		return false
+	if i.curIndex >= len(i.links) {
+		if i.lastErr = i.fetchPage(); i.lastErr != nil || len(i.links) == 0 {
+			return false
+		}
 	}
-	l.RetrievedAt = l.RetrievedAt.UTC()
-	i.latchedLink = l
+
+	i.latchedLink = i.links[i.curIndex]
+	i.curIndex++
 	return true
 }
 
+// fetchPage replaces the buffered links with the next page of results.
+func (i *linkIterator) fetchPage() error {
+	i.links, i.curIndex = nil, 0
+	err := i.pager.nextPage(func(rows *sql.Rows) (int, []interface{}, error) {
+		var page []*graph.Link
+		for rows.Next() {
+			l := new(graph.Link)
+			if err := rows.Scan(
+				&l.ID, &l.URL, &l.RetrievedAt, &l.StatusCode, &l.ContentHash,
+				&l.ETag, &l.LastModified, &l.FailureCount, &l.NextCrawlAt,
+			); err != nil {
+				return 0, nil, err
+			}
+			l.RetrievedAt = l.RetrievedAt.UTC()
+			l.NextCrawlAt = l.NextCrawlAt.UTC()
+			page = append(page, l)
+		}
+
+		i.links = page
+		if len(page) == 0 {
+			return 0, nil, nil
+		}
+		return len(page), []interface{}{page[len(page)-1].ID}, nil
+	})
+	if err != nil {
+		i.links = nil
+		return xerrors.Errorf("link iterator: %w", err)
+	}
+	return nil
+}
+
 func (i *linkIterator) Error() error {
 	return i.lastErr
 }
 
 func (i *linkIterator) Close() error {
-	err := i.rows.Close()
-	if err != nil {
-		return xerrors.Errorf("link iterator: %w", err)
-	}
+	i.pager.done = true
+	i.links = nil
 	return nil
 }
 
@@ -46,39 +173,70 @@ func (i *linkIterator) Link() *graph.Link {
 
 // edgeIterator is a graph.EdgeIterator implementation for the cdb graph.
 type edgeIterator struct {
-	rows        *sql.Rows
+	pager *pager
+
+	// pageKey returns the values of the pagination key for an edge.
+	pageKey func(*graph.Edge) []interface{}
+
+	edges       []*graph.Edge
+	curIndex    int
 	lastErr     error
 	latchedEdge *graph.Edge
 }
 
-func (i edgeIterator) Next() bool {
-	if i.lastErr != nil || !i.rows.Next() {
+func (i *edgeIterator) Next() bool {
+	if i.lastErr != nil {
 		return false
 	}
 
-	e := new(graph.Edge)
-	i.lastErr = i.rows.Scan(&e.ID, &e.Src, &e.Dst, &e.UpdatedAt)
-	if i.lastErr != nil {
-		return false
+	if i.curIndex >= len(i.edges) {
+		if i.lastErr = i.fetchPage(); i.lastErr != nil || len(i.edges) == 0 {
+			return false
+		}
 	}
-	e.UpdatedAt = e.UpdatedAt.UTC()
 
-	i.latchedEdge = e
+	i.latchedEdge = i.edges[i.curIndex]
+	i.curIndex++
 	return true
 }
 
-func (i edgeIterator) Error() error {
-	return i.lastErr
-}
+// fetchPage replaces the buffered edges with the next page of results.
+func (i *edgeIterator) fetchPage() error {
+	i.edges, i.curIndex = nil, 0
+	err := i.pager.nextPage(func(rows *sql.Rows) (int, []interface{}, error) {
+		var page []*graph.Edge
+		for rows.Next() {
+			e := new(graph.Edge)
+			if err := rows.Scan(&e.ID, &e.Src, &e.Dst, &e.UpdatedAt, &e.AnchorText, &e.Rel); err != nil {
+				return 0, nil, err
+			}
+			e.UpdatedAt = e.UpdatedAt.UTC()
+			page = append(page, e)
+		}
 
-func (i edgeIterator) Close() error {
-	err := i.rows.Close()
+		i.edges = page
+		if len(page) == 0 {
+			return 0, nil, nil
+		}
+		return len(page), i.pageKey(page[len(page)-1]), nil
+	})
 	if err != nil {
+		i.edges = nil
 		return xerrors.Errorf("edge iterator: %w", err)
 	}
 	return nil
 }
 
-func (i edgeIterator) Edge() *graph.Edge {
+func (i *edgeIterator) Error() error {
+	return i.lastErr
+}
+
+func (i *edgeIterator) Close() error {
+	i.pager.done = true
+	i.edges = nil
+	return nil
+}
+
+func (i *edgeIterator) Edge() *graph.Edge {
 	return i.latchedEdge
 }
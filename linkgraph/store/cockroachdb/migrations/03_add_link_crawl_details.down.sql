@@ -0,0 +1,7 @@
+ALTER TABLE links
+    DROP COLUMN IF EXISTS status_code,
+    DROP COLUMN IF EXISTS content_hash,
+    DROP COLUMN IF EXISTS etag,
+    DROP COLUMN IF EXISTS last_modified,
+    DROP COLUMN IF EXISTS failure_count,
+    DROP COLUMN IF EXISTS next_crawl_at;
@@ -0,0 +1,7 @@
+ALTER TABLE links
+    ADD COLUMN IF NOT EXISTS status_code INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS content_hash STRING NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS etag STRING NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_modified STRING NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS failure_count INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS next_crawl_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00';
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS edges_dst_idx ON edges (dst);
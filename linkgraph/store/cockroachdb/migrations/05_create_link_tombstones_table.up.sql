@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS link_tombstones (
+    url STRING PRIMARY KEY,
+    deleted_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS hosts (
+    name STRING PRIMARY KEY,
+    robots_txt STRING NOT NULL DEFAULT '',
+    robots_fetched_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00',
+    crawl_delay_ms INT NOT NULL DEFAULT 0,
+    blocked BOOL NOT NULL DEFAULT false,
+    block_reason STRING NOT NULL DEFAULT '',
+    last_contacted_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00',
+    fetch_count INT NOT NULL DEFAULT 0,
+    error_count INT NOT NULL DEFAULT 0
+);
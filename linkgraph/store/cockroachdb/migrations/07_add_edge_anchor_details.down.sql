@@ -0,0 +1,3 @@
+ALTER TABLE edges
+    DROP COLUMN IF EXISTS anchor_text,
+    DROP COLUMN IF EXISTS rel;
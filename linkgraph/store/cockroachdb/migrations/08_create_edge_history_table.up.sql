@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS edge_history (
+    id UUID NOT NULL DEFAULT gen_random_uuid() PRIMARY KEY,
+    src UUID NOT NULL,
+    dst UUID NOT NULL,
+    first_seen_at TIMESTAMP NOT NULL,
+    removed_at TIMESTAMP,
+    INDEX edge_history_src_idx (src, first_seen_at)
+);
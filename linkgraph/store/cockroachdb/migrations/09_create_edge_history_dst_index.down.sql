@@ -0,0 +1 @@
+DROP INDEX IF EXISTS edge_history@edge_history_dst_idx;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS edge_history_dst_idx ON edge_history (dst);
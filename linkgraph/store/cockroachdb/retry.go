@@ -0,0 +1,58 @@
+package cockroachdb
+
+import (
+	"context"
+	"github.com/lib/pq"
+	"time"
+)
+
+const (
+	// The number of times a store operation that failed with a retryable
+	// error is retried before the error is returned to the caller.
+	maxRetries = 5
+
+	// The delay before the first retry of a failed operation. The delay
+	// doubles after each subsequent attempt up to maxRetryBackoff.
+	retryBackoff    = 10 * time.Millisecond
+	maxRetryBackoff = time.Second
+)
+
+// withRetries invokes fn until it succeeds or returns an error that is not
+// retryable, as reported by isRetryableError. Callers must make sure that fn
+// has no side-effects besides the database changes that are rolled back when
+// the statement fails.
+func withRetries(ctx context.Context, fn func() error) error {
+	return retry(ctx, maxRetries, isRetryableError, fn)
+}
+
+// retry invokes fn up to maxRetries+1 times with an exponential backoff
+// between attempts for as long as it returns an error for which shouldRetry
+// returns true.
+func retry(ctx context.Context, maxRetries int, shouldRetry func(error) bool, fn func() error) error {
+	backoff := retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt == maxRetries || !shouldRetry(err) || ctx.Err() != nil {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if backoff *= 2; backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// isRetryableError returns true if err is a transaction serialization failure
+// that CockroachDB expects clients to retry.
+func isRetryableError(err error) bool {
+	pqErr, valid := err.(*pq.Error)
+	if !valid {
+		return false
+	}
+	return pqErr.Code == "40001"
+}
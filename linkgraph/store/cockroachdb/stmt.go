@@ -0,0 +1,72 @@
+package cockroachdb
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// lazyStmt is a statement that is prepared the first time it is used so that
+// the graph can be created while the database is unreachable. If preparing
+// the statement fails, the query is executed without a prepared statement and
+// the preparation is retried the next time the statement is used.
+type lazyStmt struct {
+	db    *sql.DB
+	query string
+
+	mu   sync.Mutex
+	stmt *sql.Stmt
+}
+
+func newLazyStmt(db *sql.DB, query string) *lazyStmt {
+	return &lazyStmt{db: db, query: query}
+}
+
+// prepared returns the prepared statement or nil if it cannot be prepared.
+func (s *lazyStmt) prepared(ctx context.Context) *sql.Stmt {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stmt == nil {
+		s.stmt, _ = s.db.PrepareContext(ctx, s.query)
+	}
+	return s.stmt
+}
+
+// QueryRowContext executes the statement against conn, which is either the
+// database the statement is prepared for or a transaction on it, and returns
+// the resulting row.
+func (s *lazyStmt) QueryRowContext(ctx context.Context, conn dbConn, args ...interface{}) *sql.Row {
+	stmt := s.prepared(ctx)
+	if stmt == nil {
+		return conn.QueryRowContext(ctx, s.query, args...)
+	}
+	if tx, ok := conn.(*sql.Tx); ok {
+		stmt = tx.StmtContext(ctx, stmt)
+	}
+	return stmt.QueryRowContext(ctx, args...)
+}
+
+// ExecContext executes the statement against conn, which is either the
+// database the statement is prepared for or a transaction on it.
+func (s *lazyStmt) ExecContext(ctx context.Context, conn dbConn, args ...interface{}) (sql.Result, error) {
+	stmt := s.prepared(ctx)
+	if stmt == nil {
+		return conn.ExecContext(ctx, s.query, args...)
+	}
+	if tx, ok := conn.(*sql.Tx); ok {
+		stmt = tx.StmtContext(ctx, stmt)
+	}
+	return stmt.ExecContext(ctx, args...)
+}
+
+// Close closes the statement if it has been prepared.
+func (s *lazyStmt) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stmt == nil {
+		return nil
+	}
+	err := s.stmt.Close()
+	s.stmt = nil
+	return err
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"Search_Engine/linkgraph/graph"
+	"context"
+	"golang.org/x/xerrors"
+	"time"
+)
+
+// UpsertHost creates a new host or updates the crawl settings of an existing
+// host.
+func (s *InMemoryGraph) UpsertHost(ctx context.Context, host *graph.Host) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing := s.hosts[host.Name]; existing != nil {
+		host.LastContactedAt = existing.LastContactedAt
+		host.FetchCount = existing.FetchCount
+		host.ErrorCount = existing.ErrorCount
+	}
+
+	hCopy := new(graph.Host)
+	*hCopy = *host
+	s.hosts[hCopy.Name] = hCopy
+	return nil
+}
+
+// FindHost looks up a host by its name.
+func (s *InMemoryGraph) FindHost(ctx context.Context, name string) (*graph.Host, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	host := s.hosts[name]
+	if host == nil {
+		return nil, xerrors.Errorf("find host: %w", graph.ErrNotFound)
+	}
+
+	hCopy := new(graph.Host)
+	*hCopy = *host
+	return hCopy, nil
+}
+
+// RecordHostFetch records a fetch attempt for the specified host.
+func (s *InMemoryGraph) RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	host := s.hosts[name]
+	if host == nil {
+		host = &graph.Host{Name: name}
+		s.hosts[name] = host
+	}
+
+	if fetchedAt.After(host.LastContactedAt) {
+		host.LastContactedAt = fetchedAt
+	}
+	host.FetchCount++
+	if failed {
+		host.ErrorCount++
+	}
+	return nil
+}
@@ -2,16 +2,24 @@ package memory
 
 import (
 	"Search_Engine/linkgraph/graph"
+	"context"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
+	"sort"
 	"sync"
 	"time"
 )
 
-// Compile-time check for ensuring InMemoryGraph implements Graph.
-var _ graph.Graph = (*InMemoryGraph)(nil)
+// Compile-time checks for ensuring InMemoryGraph implements Graph, Restorer
+// and EdgeHistorian.
+var (
+	_ graph.Graph         = (*InMemoryGraph)(nil)
+	_ graph.Restorer      = (*InMemoryGraph)(nil)
+	_ graph.EdgeHistorian = (*InMemoryGraph)(nil)
+)
 
-// edgeList contains the slice of edge UUIDs that originate from a link in the graph.
+// edgeList contains the slice of edge UUIDs that originate from or point to a
+// link in the graph.
 type edgeList []uuid.UUID
 
 // InMemoryGraph implements an in-memory link graph that can be concurrently
@@ -22,42 +30,127 @@ type InMemoryGraph struct {
 	links map[uuid.UUID]*graph.Link
 	edges map[uuid.UUID]*graph.Edge
 
-	linkURLIndex map[string]*graph.Link
-	linkEdgeMap  map[uuid.UUID]edgeList
+	linkURLIndex  map[string]*graph.Link
+	linkEdgeMap   map[uuid.UUID]edgeList
+	linkInEdgeMap map[uuid.UUID]edgeList
+
+	// tombstones maps the URLs of deleted links to their deletion time.
+	tombstones map[string]time.Time
+
+	hosts map[string]*graph.Host
+
+	events graph.LinkEventBroadcaster
+
+	// urlDerivedIDs causes new links to be assigned graph.LinkIDForURL
+	// instead of a random ID.
+	urlDerivedIDs bool
+
+	// edgeHistory maps source link IDs to the history of their outbound
+	// edges. It is nil unless edge history has been enabled.
+	edgeHistory map[uuid.UUID][]*graph.EdgeRecord
+}
+
+// Option configures an InMemoryGraph.
+type Option func(*InMemoryGraph)
+
+// WithURLDerivedIDs configures the graph to assign IDs derived from the link
+// URL via graph.LinkIDForURL to new links that do not specify an ID.
+func WithURLDerivedIDs() Option {
+	return func(s *InMemoryGraph) { s.urlDerivedIDs = true }
+}
+
+// WithEdgeHistory configures the graph to keep a record of the edges that are
+// added to and removed from the graph.
+func WithEdgeHistory() Option {
+	return func(s *InMemoryGraph) { s.edgeHistory = make(map[uuid.UUID][]*graph.EdgeRecord) }
 }
 
 // NewInMemoryGraph creates a new in-memory link graph.
-func NewInMemoryGraph() *InMemoryGraph {
-	return &InMemoryGraph{
-		links:        make(map[uuid.UUID]*graph.Link),
-		edges:        make(map[uuid.UUID]*graph.Edge),
-		linkURLIndex: make(map[string]*graph.Link),
-		linkEdgeMap:  make(map[uuid.UUID]edgeList),
+func NewInMemoryGraph(opts ...Option) *InMemoryGraph {
+	s := &InMemoryGraph{
+		links:         make(map[uuid.UUID]*graph.Link),
+		edges:         make(map[uuid.UUID]*graph.Edge),
+		linkURLIndex:  make(map[string]*graph.Link),
+		linkEdgeMap:   make(map[uuid.UUID]edgeList),
+		linkInEdgeMap: make(map[uuid.UUID]edgeList),
+		tombstones:    make(map[string]time.Time),
+		hosts:         make(map[string]*graph.Host),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
 // UpsertLink creates a new link or updates an existing link.
-func (s *InMemoryGraph) UpsertLink(link *graph.Link) error {
+func (s *InMemoryGraph) UpsertLink(ctx context.Context, link *graph.Link) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.upsertLink(link); err != nil {
+		return xerrors.Errorf("upsert link: %w", err)
+	}
+	return nil
+}
+
+// UpsertLinks creates or updates a batch of links. Links whose URL has been
+// tombstoned are skipped and have their ID set to uuid.Nil.
+func (s *InMemoryGraph) UpsertLinks(ctx context.Context, links []*graph.Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range links {
+		if err := s.upsertLink(link); err != nil {
+			if xerrors.Is(err, graph.ErrLinkDeleted) {
+				link.ID = uuid.Nil
+				continue
+			}
+			return xerrors.Errorf("upsert links: %w", err)
+		}
+	}
+	return nil
+}
+
+// upsertLink implements the upsert logic for a single link. Callers must hold
+// the write lock.
+func (s *InMemoryGraph) upsertLink(link *graph.Link) error {
 	// Check if a link with the same URL already exists. If so, convert
 	// this into an update and point the link ID to the existing link.
 	if existing := s.linkURLIndex[link.URL]; existing != nil {
 		link.ID = existing.ID
-		origTs := existing.RetrievedAt
-		*existing = *link
-		if origTs.After(existing.RetrievedAt) {
-			existing.RetrievedAt = origTs
+		// Never overwrite the crawl details with older values or with
+		// the blank ones of a rediscovered link.
+		if link.HasCrawlResult() && !existing.RetrievedAt.After(link.RetrievedAt) {
+			*existing = *link
 		}
+		*link = *existing
+		s.events.Publish(graph.LinkUpdated, link)
 		return nil
 	}
 
-	// Assign new ID and insert link
-	for {
-		link.ID = uuid.New()
-		if s.links[link.ID] == nil {
-			break
+	// Refuse to recreate links that were recently deleted.
+	if deletedAt, found := s.tombstones[link.URL]; found {
+		if time.Since(deletedAt) < graph.TombstoneTTL {
+			return graph.ErrLinkDeleted
+		}
+		delete(s.tombstones, link.URL)
+	}
+
+	// Keep the caller-supplied ID, if any, or assign a new ID and insert
+	// the link.
+	if link.ID == uuid.Nil && s.urlDerivedIDs {
+		link.ID = graph.LinkIDForURL(link.URL)
+	}
+	if link.ID != uuid.Nil {
+		if s.links[link.ID] != nil {
+			return graph.ErrIDConflict
+		}
+	} else {
+		for {
+			link.ID = uuid.New()
+			if s.links[link.ID] == nil {
+				break
+			}
 		}
 	}
 
@@ -65,11 +158,12 @@ func (s *InMemoryGraph) UpsertLink(link *graph.Link) error {
 	*lCopy = *link
 	s.linkURLIndex[lCopy.URL] = lCopy
 	s.links[lCopy.ID] = lCopy
+	s.events.Publish(graph.LinkCreated, lCopy)
 	return nil
 }
 
 // FindLink looks up a link by its ID.
-func (s *InMemoryGraph) FindLink(id uuid.UUID) (*graph.Link, error) {
+func (s *InMemoryGraph) FindLink(ctx context.Context, id uuid.UUID) (*graph.Link, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
@@ -83,9 +177,58 @@ func (s *InMemoryGraph) FindLink(id uuid.UUID) (*graph.Link, error) {
 	return lCopy, nil
 }
 
+// FindLinkByURL looks up a link by its URL.
+func (s *InMemoryGraph) FindLinkByURL(ctx context.Context, url string) (*graph.Link, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	link := s.linkURLIndex[url]
+	if link == nil {
+		return nil, xerrors.Errorf("find link by URL: %w", graph.ErrNotFound)
+	}
+
+	lCopy := new(graph.Link)
+	*lCopy = *link
+	return lCopy, nil
+}
+
+// DeleteLink removes a link together with all edges that originate from or
+// point to it and records a tombstone for the link's URL.
+func (s *InMemoryGraph) DeleteLink(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link := s.links[id]
+	if link == nil {
+		return xerrors.Errorf("delete link: %w", graph.ErrNotFound)
+	}
+
+	now := time.Now()
+	for _, edgeID := range s.linkEdgeMap[id] {
+		edge := s.edges[edgeID]
+		delete(s.edges, edgeID)
+		s.removeInEdge(edge.Dst, edgeID)
+		s.closeEdgeRecord(edge, now)
+	}
+	for _, edgeID := range s.linkInEdgeMap[id] {
+		edge := s.edges[edgeID]
+		delete(s.edges, edgeID)
+		s.removeOutEdge(edge.Src, edgeID)
+		s.closeEdgeRecord(edge, now)
+	}
+
+	delete(s.linkEdgeMap, id)
+	delete(s.linkInEdgeMap, id)
+	delete(s.linkURLIndex, link.URL)
+	delete(s.links, id)
+	s.tombstones[link.URL] = time.Now()
+	s.events.Publish(graph.LinkDeleted, link)
+	return nil
+}
+
 // Links returns an iterator for the set of links whose IDs belong to the
 // [fromID, toID) range and were retrieved before the provided timestamp.
-func (s *InMemoryGraph) Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error) {
+func (s *InMemoryGraph) Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error) {
 	from, to := fromID.String(), toID.String()
 
 	s.mu.RLock()
@@ -101,14 +244,36 @@ func (s *InMemoryGraph) Links(fromID, toID uuid.UUID, retrievedBefore time.Time)
 }
 
 // UpsertEdge creates a new edge or updates an existing edge.
-func (s *InMemoryGraph) UpsertEdge(edge *graph.Edge) error {
+func (s *InMemoryGraph) UpsertEdge(ctx context.Context, edge *graph.Edge) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.upsertEdge(edge); err != nil {
+		return xerrors.Errorf("upsert edge: %w", err)
+	}
+	return nil
+}
+
+// UpsertEdges creates or updates a batch of edges.
+func (s *InMemoryGraph) UpsertEdges(ctx context.Context, edges []*graph.Edge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, edge := range edges {
+		if err := s.upsertEdge(edge); err != nil {
+			return xerrors.Errorf("upsert edges: %w", err)
+		}
+	}
+	return nil
+}
+
+// upsertEdge implements the upsert logic for a single edge. Callers must hold
+// the write lock.
+func (s *InMemoryGraph) upsertEdge(edge *graph.Edge) error {
 	_, srcExists := s.links[edge.Src]
 	_, dstExists := s.links[edge.Dst]
 	if !srcExists || !dstExists {
-		return xerrors.Errorf("upsert edge: %w", graph.ErrUnknownEdgeLinks)
+		return graph.ErrUnknownEdgeLinks
 	}
 
 	// Scan edge list from source
@@ -116,6 +281,8 @@ func (s *InMemoryGraph) UpsertEdge(edge *graph.Edge) error {
 		existingEdge := s.edges[edgeID]
 		if existingEdge.Src == edge.Src && existingEdge.Dst == edge.Dst {
 			existingEdge.UpdatedAt = time.Now()
+			existingEdge.AnchorText = edge.AnchorText
+			existingEdge.Rel = edge.Rel
 			*edge = *existingEdge
 			return nil
 		}
@@ -137,13 +304,17 @@ func (s *InMemoryGraph) UpsertEdge(edge *graph.Edge) error {
 	// Append the edge ID to the list of edges originating from the
 	// edge's source link.
 	s.linkEdgeMap[edge.Src] = append(s.linkEdgeMap[edge.Src], eCopy.ID)
+	// Also index the edge by its destination link so backlink lookups do
+	// not need to scan the whole graph.
+	s.linkInEdgeMap[edge.Dst] = append(s.linkInEdgeMap[edge.Dst], eCopy.ID)
+	s.openEdgeRecord(eCopy)
 	return nil
 }
 
 // Edges returns an iterator for the set of edges whose source vertex IDs
 // belong to the [fromID, toID) range and were updated before the provided
 // timestamp.
-func (s *InMemoryGraph) Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
+func (s *InMemoryGraph) Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error) {
 	from, to := fromID.String(), toID.String()
 
 	s.mu.RLock()
@@ -164,17 +335,109 @@ func (s *InMemoryGraph) Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (
 	return &edgeIterator{s: s, edges: list}, nil
 }
 
+// EdgesTo returns an iterator for the set of edges whose destination vertex is
+// the specified link ID.
+func (s *InMemoryGraph) EdgesTo(ctx context.Context, dstID uuid.UUID) (graph.EdgeIterator, error) {
+	s.mu.RLock()
+	list := make([]*graph.Edge, 0, len(s.linkInEdgeMap[dstID]))
+	for _, edgeID := range s.linkInEdgeMap[dstID] {
+		list = append(list, s.edges[edgeID])
+	}
+	s.mu.RUnlock()
+
+	return &edgeIterator{s: s, edges: list}, nil
+}
+
+// InboundDegree returns the number of edges whose destination vertex is the
+// specified link ID.
+func (s *InMemoryGraph) InboundDegree(ctx context.Context, dstID uuid.UUID) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.linkInEdgeMap[dstID]), nil
+}
+
+// RestoreLinks inserts or replaces a batch of links keeping their IDs.
+func (s *InMemoryGraph) RestoreLinks(ctx context.Context, links []*graph.Link) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, link := range links {
+		if existing := s.linkURLIndex[link.URL]; existing != nil && existing.ID != link.ID {
+			return xerrors.Errorf("restore links: %w", graph.ErrIDConflict)
+		}
+		if existing := s.links[link.ID]; existing != nil {
+			delete(s.linkURLIndex, existing.URL)
+		}
+
+		lCopy := new(graph.Link)
+		*lCopy = *link
+		s.linkURLIndex[lCopy.URL] = lCopy
+		s.links[lCopy.ID] = lCopy
+		delete(s.tombstones, lCopy.URL)
+	}
+	return nil
+}
+
+// RestoreEdges inserts or replaces a batch of edges keeping their IDs and
+// update timestamps.
+func (s *InMemoryGraph) RestoreEdges(ctx context.Context, edges []*graph.Edge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, edge := range edges {
+		_, srcExists := s.links[edge.Src]
+		_, dstExists := s.links[edge.Dst]
+		if !srcExists || !dstExists {
+			return xerrors.Errorf("restore edges: %w", graph.ErrUnknownEdgeLinks)
+		}
+
+		if existing := s.edges[edge.ID]; existing != nil {
+			if existing.Src != edge.Src || existing.Dst != edge.Dst {
+				return xerrors.Errorf("restore edges: %w", graph.ErrIDConflict)
+			}
+			existing.UpdatedAt = edge.UpdatedAt
+			existing.AnchorText = edge.AnchorText
+			existing.Rel = edge.Rel
+			continue
+		}
+		for _, edgeID := range s.linkEdgeMap[edge.Src] {
+			if s.edges[edgeID].Dst == edge.Dst {
+				return xerrors.Errorf("restore edges: %w", graph.ErrIDConflict)
+			}
+		}
+
+		eCopy := new(graph.Edge)
+		*eCopy = *edge
+		s.edges[eCopy.ID] = eCopy
+		s.linkEdgeMap[eCopy.Src] = append(s.linkEdgeMap[eCopy.Src], eCopy.ID)
+		s.linkInEdgeMap[eCopy.Dst] = append(s.linkInEdgeMap[eCopy.Dst], eCopy.ID)
+		s.openEdgeRecord(eCopy)
+	}
+	return nil
+}
+
+// WatchLinks returns a watcher that receives an event for each link that is
+// created, updated or deleted after the call returns.
+func (s *InMemoryGraph) WatchLinks(ctx context.Context) (graph.LinkWatcher, error) {
+	return s.events.Watch(ctx), nil
+}
+
 // RemoveStaleEdges removes any edge that originates from the specified link ID
 // and was updated before the specified timestamp.
-func (s *InMemoryGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error {
+func (s *InMemoryGraph) RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var newEdgeList edgeList
+	var (
+		newEdgeList edgeList
+		now         = time.Now()
+	)
 	for _, edgeID := range s.linkEdgeMap[fromID] {
 		edge := s.edges[edgeID]
 		if edge.UpdatedAt.Before(updatedBefore) {
 			delete(s.edges, edgeID)
+			s.removeInEdge(edge.Dst, edgeID)
+			s.closeEdgeRecord(edge, now)
 			continue
 		}
 
@@ -185,3 +448,86 @@ func (s *InMemoryGraph) RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Ti
 	s.linkEdgeMap[fromID] = newEdgeList
 	return nil
 }
+
+// EdgeTimeline returns the history of the edges originating from the specified
+// link ID ordered by their first-seen time.
+func (s *InMemoryGraph) EdgeTimeline(ctx context.Context, srcID uuid.UUID) ([]*graph.EdgeRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.edgeHistory == nil {
+		return nil, xerrors.Errorf("edge timeline: %w", graph.ErrEdgeHistoryDisabled)
+	}
+
+	records := make([]*graph.EdgeRecord, 0, len(s.edgeHistory[srcID]))
+	for _, rec := range s.edgeHistory[srcID] {
+		rCopy := new(graph.EdgeRecord)
+		*rCopy = *rec
+		records = append(records, rCopy)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].FirstSeenAt.Before(records[j].FirstSeenAt)
+	})
+	return records, nil
+}
+
+// openEdgeRecord records that edge has been added to the graph. Callers must
+// hold the write lock.
+func (s *InMemoryGraph) openEdgeRecord(edge *graph.Edge) {
+	if s.edgeHistory == nil {
+		return
+	}
+	s.edgeHistory[edge.Src] = append(s.edgeHistory[edge.Src], &graph.EdgeRecord{
+		Src:         edge.Src,
+		Dst:         edge.Dst,
+		FirstSeenAt: edge.UpdatedAt,
+	})
+}
+
+// closeEdgeRecord records that edge was removed from the graph at removedAt.
+// Callers must hold the write lock.
+func (s *InMemoryGraph) closeEdgeRecord(edge *graph.Edge, removedAt time.Time) {
+	if s.edgeHistory == nil {
+		return
+	}
+	for _, rec := range s.edgeHistory[edge.Src] {
+		if rec.Dst == edge.Dst && rec.RemovedAt.IsZero() {
+			rec.RemovedAt = removedAt
+			return
+		}
+	}
+}
+
+// removeInEdge removes edgeID from the list of edges pointing to dstID.
+func (s *InMemoryGraph) removeInEdge(dstID, edgeID uuid.UUID) {
+	inEdges := s.linkInEdgeMap[dstID]
+	for i, id := range inEdges {
+		if id == edgeID {
+			inEdges = append(inEdges[:i], inEdges[i+1:]...)
+			break
+		}
+	}
+
+	if len(inEdges) == 0 {
+		delete(s.linkInEdgeMap, dstID)
+		return
+	}
+	s.linkInEdgeMap[dstID] = inEdges
+}
+
+// removeOutEdge removes edgeID from the list of edges originating from srcID.
+func (s *InMemoryGraph) removeOutEdge(srcID, edgeID uuid.UUID) {
+	outEdges := s.linkEdgeMap[srcID]
+	for i, id := range outEdges {
+		if id == edgeID {
+			outEdges = append(outEdges[:i], outEdges[i+1:]...)
+			break
+		}
+	}
+
+	if len(outEdges) == 0 {
+		delete(s.linkEdgeMap, srcID)
+		return
+	}
+	s.linkEdgeMap[srcID] = outEdges
+}
@@ -2,6 +2,8 @@ package memory
 
 import (
 	"Search_Engine/linkgraph/graph"
+	"context"
+	"golang.org/x/xerrors"
 	_ "gopkg.in/check.v1"
 	gc "gopkg.in/check.v1"
 	"testing"
@@ -12,13 +14,44 @@ type InMemoryTestSuite struct {
 }
 
 var _ = gc.Suite(new(InMemoryGraphTestSuite))
+var _ = gc.Suite(new(InMemoryGraphWithHistoryTestSuite))
 
 func Test(t *testing.T) { gc.TestingT(t) }
 
 type InMemoryGraphTestSuite struct {
-	graphtest.SuiteBase
+	graph.SuiteBase
 }
 
 func (s *InMemoryGraphTestSuite) SetUpTest(c *gc.C) {
 	s.SetGraph(NewInMemoryGraph())
 }
+
+// InMemoryGraphWithHistoryTestSuite runs the shared test suite against a
+// graph that keeps the edge history.
+type InMemoryGraphWithHistoryTestSuite struct {
+	graph.SuiteBase
+}
+
+func (s *InMemoryGraphWithHistoryTestSuite) SetUpTest(c *gc.C) {
+	s.SetGraph(NewInMemoryGraph(WithEdgeHistory()))
+}
+
+func (s *InMemoryGraphTestSuite) TestURLDerivedIDs(c *gc.C) {
+	g := NewInMemoryGraph(WithURLDerivedIDs())
+
+	link := &graph.Link{URL: "https://example.com"}
+	c.Assert(g.UpsertLink(context.Background(), link), gc.IsNil)
+	c.Assert(link.ID, gc.Equals, graph.LinkIDForURL(link.URL))
+
+	links := []*graph.Link{{URL: "https://example.com/a"}, {URL: "https://example.com"}}
+	c.Assert(g.UpsertLinks(context.Background(), links), gc.IsNil)
+	c.Assert(links[0].ID, gc.Equals, graph.LinkIDForURL(links[0].URL))
+	c.Assert(links[1].ID, gc.Equals, link.ID)
+}
+
+func (s *InMemoryGraphTestSuite) TestEdgeTimelineDisabled(c *gc.C) {
+	g := NewInMemoryGraph()
+
+	_, err := g.EdgeTimeline(context.Background(), graph.LinkIDForURL("https://example.com"))
+	c.Assert(xerrors.Is(err, graph.ErrEdgeHistoryDisabled), gc.Equals, true)
+}
@@ -5,11 +5,15 @@ import (
 	"Search_Engine/agneta/partition"
 	"Search_Engine/agneta/service"
 	"Search_Engine/agneta/service/crawler"
+	"Search_Engine/agneta/service/gc"
+	"Search_Engine/agneta/service/graphapi"
 	"Search_Engine/agneta/service/pagerank"
 	"Search_Engine/linkgraph/graph"
+	"Search_Engine/linkgraph/store/boltdb"
 	"Search_Engine/linkgraph/store/cockroachdb"
 	"Search_Engine/linkgraph/store/memory"
 	"Search_Engine/textindexer/index"
+	"Search_Engine/textindexer/store/bleveindex"
 	"Search_Engine/textindexer/store/elastic"
 	"Search_Engine/textindexer/store/memindex"
 	"context"
@@ -17,10 +21,12 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
+	"io"
 	"net/url"
 	"os"
 	"os/signal"
 	"runtime"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -48,7 +54,16 @@ func main() {
 }
 
 func runMain(logger *logrus.Entry) error {
-	svcGroup, err := setupServices(logger)
+	svcGroup, closers, err := setupServices(logger)
+	// Release the link graph and text indexer once the services have
+	// stopped so that, e.g., bolt and bleve flush and unlock their files.
+	defer func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			if closeErr := closers[i].Close(); closeErr != nil {
+				logger.WithField("err", closeErr).Error("could not close store")
+			}
+		}
+	}()
 	if err != nil {
 		return err
 	}
@@ -70,46 +85,73 @@ func runMain(logger *logrus.Entry) error {
 	return svcGroup.Run(ctx)
 }
 
-func setupServices(logger *logrus.Entry) (service.Group, error) {
+// setupServices configures the services to run. It also returns the stores
+// used by the services that need to be closed once the services stop, even
+// if it fails.
+func setupServices(logger *logrus.Entry) (service.Group, []io.Closer, error) {
 	var (
 		frontendCfg frontend.Config
 		crawlerCfg  crawler.Config
 		pageRankCfg pagerank.Config
+		gcCfg       gc.Config
+		graphAPICfg graphapi.Config
 	)
 
 	flag.StringVar(&frontendCfg.ListenAddr, "frontend-listen-addr", ":8080", "The address to listen for incoming front-end requests")
 	flag.IntVar(&frontendCfg.ResultsPerPage, "frontend-results-per-page", 10, "The number of entries for each search result page")
 	flag.IntVar(&frontendCfg.MaxSummaryLength, "frontend-max-summary-length", 256, "The maximum length of the summary for each matched document in characters")
+	flag.IntVar(&frontendCfg.SuggestionThreshold, "frontend-suggestion-threshold", 3, "Suggest spelling corrections for queries that match fewer than this many documents")
+	flag.IntVar(&frontendCfg.MaxCompletions, "frontend-max-completions", 8, "The maximum number of query completions to offer while typing a search query")
+	flag.IntVar(&frontendCfg.MinCompletionQueryCount, "frontend-min-completion-query-count", 5, "The number of distinct clients that must have searched for a past query before it is offered as a completion to other users")
 
 	flag.IntVar(&crawlerCfg.FetchWorkers, "crawler-num-workers", runtime.NumCPU(), "The number of workers to use for crawling web-pages (defaults to number of CPUs)")
+	flag.IntVar(&crawlerCfg.IndexBatchSize, "crawler-index-batch-size", 100, "The maximum number of crawled documents to send to the text indexer as a single batch")
+	flag.DurationVar(&crawlerCfg.IndexFlushInterval, "crawler-index-flush-interval", 10*time.Second, "The maximum amount of time to accumulate crawled documents before sending a partial batch to the text indexer (0 = only when the crawler run completes)")
 	flag.DurationVar(&crawlerCfg.UpdateInterval, "crawler-update-interval", 5*time.Minute, "The time between subsequent crawler runs")
 	flag.DurationVar(&crawlerCfg.ReIndexThreshold, "crawler-reindex-threshold", 7*24*time.Hour, "The minimum amount of time before re-indexing an already-crawled link")
+	flag.IntVar(&crawlerCfg.Frontier.MaxLinksPerPass, "crawler-max-links-per-pass", 10000, "The maximum number of links to crawl in each crawler run; links are crawled in priority order (0 = no limit)")
 
 	flag.IntVar(&pageRankCfg.ComputeWorkers, "pagerank-num-workers", runtime.NumCPU(), "The number of workers to use for calculating PageRank scores (defaults to number of CPUs)")
 	flag.DurationVar(&pageRankCfg.UpdateInterval, "pagerank-update-interval", time.Hour, "The time between subsequent PageRank score updates")
 	flag.DurationVar(&pageRankCfg.ReIndexThreshold, "pagerank-reindex-threshold", 5*time.Hour, "The time between subsequent PageRank score updates")
 
-	linkGraphURI := flag.String("link-graph-uri", "in-memindex://", "The URI for connecting to the link-graph (supported URIs: in-memindex://, postgresql://user@host:26257/linkgraph?sslmode=disable)")
-	textIndexerURI := flag.String("text-indexer-uri", "in-memindex://", "The URI for connecting to the text indexer (supported URIs: in-memindex://, es://node1:9200,...,nodeN:9200)")
+	flag.DurationVar(&gcCfg.UpdateInterval, "gc-update-interval", 24*time.Hour, "The time between subsequent link garbage collection passes")
+	flag.IntVar(&gcCfg.Rules.MinFailureCount, "gc-min-failure-count", 10, "Collect links whose last N crawl attempts failed (0 = disabled)")
+	flag.DurationVar(&gcCfg.Rules.MaxAge, "gc-max-age", 90*24*time.Hour, "Collect links that have not been retrieved within this amount of time (0 = disabled)")
+	flag.DurationVar(&gcCfg.Rules.OrphanGracePeriod, "gc-orphan-grace-period", 7*24*time.Hour, "Collect never-retrieved links without inbound edges after they have been orphaned for this amount of time (0 = disabled)")
+	flag.DurationVar(&gcCfg.DocumentTTL, "gc-document-ttl", 0, "Delete index documents that have not been re-indexed within this amount of time (0 = disabled)")
+	flag.BoolVar(&gcCfg.DryRun, "gc-dry-run", true, "Only report the links that would be garbage-collected without deleting them")
+
+	flag.StringVar(&graphAPICfg.ListenAddr, "link-graph-api-listen-addr", "", "The address to listen for incoming link graph gRPC requests, e.g. from linkgraph-snapshot (disabled if empty)")
+
+	linkGraphURI := flag.String("link-graph-uri", "in-memindex://", "The URI for connecting to the link-graph (supported URIs: in-memindex://, bolt:///path/to/graph.db, postgresql://user@host:26257/linkgraph?sslmode=disable); append link_ids=url to the in-memindex or postgresql URI query to derive link IDs from URLs and edge_history=true to any URI query to record edge history")
+	textIndexerURI := flag.String("text-indexer-uri", "in-memindex://", "The URI for connecting to the text indexer (supported URIs: in-memindex://, bleve:///path/to/index, es://node1:9200,...,nodeN:9200)")
 
 	partitionDetMode := flag.String("partition-detection-mode", "single", "The partition detection mode to use. Supported values are 'dns=HEADLESS_SERVICE_NAME' (k8s) and 'single' (local dev mode)")
 	flag.Parse()
 
 	// Retrieve a suitable link graph and text indexer implementation and
 	// plug it into the service configurations.
+	var closers []io.Closer
 	linkGraph, err := getLinkGraph(*linkGraphURI, logger)
 	if err != nil {
-		return nil, err
+		return nil, closers, err
+	}
+	if closer, ok := linkGraph.(io.Closer); ok {
+		closers = append(closers, closer)
 	}
 	textIndexer, err := getTextIndexer(*textIndexerURI, logger)
 	if err != nil {
-		return nil, err
+		return nil, closers, err
+	}
+	if closer, ok := textIndexer.(io.Closer); ok {
+		closers = append(closers, closer)
 	}
 
 	// Create a helper for detecting the partition assigned to this instance.
 	partDet, err := getPartitionDetector(*partitionDetMode)
 	if err != nil {
-		return nil, err
+		return nil, closers, err
 	}
 
 	var svc service.Service
@@ -121,17 +163,19 @@ func setupServices(logger *logrus.Entry) (service.Group, error) {
 	if svc, err = frontend.NewService(frontendCfg); err == nil {
 		svcGroup = append(svcGroup, svc)
 	} else {
-		return nil, err
+		return nil, closers, err
 	}
 
 	crawlerCfg.GraphAPI = linkGraph
 	crawlerCfg.IndexAPI = textIndexer
+	crawlerCfg.PageRankAPI = textIndexer
 	crawlerCfg.PartitionDetector = partDet
+	crawlerCfg.URLDerivedLinkIDs = usesURLDerivedLinkIDs(*linkGraphURI)
 	crawlerCfg.Logger = logger.WithField("service", "crawler")
 	if svc, err = crawler.NewService(crawlerCfg); err == nil {
 		svcGroup = append(svcGroup, svc)
 	} else {
-		return nil, err
+		return nil, closers, err
 	}
 
 	pageRankCfg.GraphAPI = linkGraph
@@ -141,18 +185,51 @@ func setupServices(logger *logrus.Entry) (service.Group, error) {
 	if svc, err = pagerank.NewService(pageRankCfg); err == nil {
 		svcGroup = append(svcGroup, svc)
 	} else {
-		return nil, err
+		return nil, closers, err
+	}
+
+	gcCfg.GraphAPI = linkGraph
+	gcCfg.IndexAPI = textIndexer
+	gcCfg.PartitionDetector = partDet
+	gcCfg.Logger = logger.WithField("service", "gc")
+	if svc, err = gc.NewService(gcCfg); err == nil {
+		svcGroup = append(svcGroup, svc)
+	} else {
+		return nil, closers, err
+	}
+
+	if graphAPICfg.ListenAddr != "" {
+		g, ok := linkGraph.(graph.Graph)
+		if !ok {
+			return nil, closers, xerrors.Errorf("link graph cannot be exposed through the link graph API")
+		}
+		graphAPICfg.Graph = g
+		graphAPICfg.Logger = logger.WithField("service", "link-graph-api")
+		if svc, err = graphapi.NewService(graphAPICfg); err == nil {
+			svcGroup = append(svcGroup, svc)
+		} else {
+			return nil, closers, err
+		}
 	}
 
-	return svcGroup, nil
+	return svcGroup, closers, nil
 }
 
 type linkGraph interface {
-	UpsertLink(link *graph.Link) error
-	UpsertEdge(edge *graph.Edge) error
-	RemoveStaleEdges(fromID uuid.UUID, updatedBefore time.Time) error
-	Links(fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
-	Edges(fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error)
+	UpsertLink(ctx context.Context, link *graph.Link) error
+	FindLinkByURL(ctx context.Context, url string) (*graph.Link, error)
+	UpsertLinks(ctx context.Context, links []*graph.Link) error
+	UpsertEdge(ctx context.Context, edge *graph.Edge) error
+	UpsertEdges(ctx context.Context, edges []*graph.Edge) error
+	RemoveStaleEdges(ctx context.Context, fromID uuid.UUID, updatedBefore time.Time) error
+	Links(ctx context.Context, fromID, toID uuid.UUID, retrievedBefore time.Time) (graph.LinkIterator, error)
+	Edges(ctx context.Context, fromID, toID uuid.UUID, updatedBefore time.Time) (graph.EdgeIterator, error)
+	InboundDegree(ctx context.Context, dstID uuid.UUID) (int, error)
+	DeleteLink(ctx context.Context, id uuid.UUID) error
+	WatchLinks(ctx context.Context) (graph.LinkWatcher, error)
+	UpsertHost(ctx context.Context, host *graph.Host) error
+	FindHost(ctx context.Context, name string) (*graph.Host, error)
+	RecordHostFetch(ctx context.Context, name string, fetchedAt time.Time, failed bool) error
 }
 
 func getLinkGraph(linkGraphURI string, logger *logrus.Entry) (linkGraph, error) {
@@ -168,7 +245,32 @@ func getLinkGraph(linkGraphURI string, logger *logrus.Entry) (linkGraph, error)
 	switch uri.Scheme {
 	case "in-memindex":
 		logger.Info("using in-memindex graph")
-		return memory.NewInMemoryGraph(), nil
+		var opts []memory.Option
+		if edgeHistory, err := usesEdgeHistory(uri); err != nil {
+			return nil, err
+		} else if edgeHistory {
+			opts = append(opts, memory.WithEdgeHistory())
+		}
+		switch linkIDs := uri.Query().Get("link_ids"); linkIDs {
+		case "", "random":
+		case "url":
+			opts = append(opts, memory.WithURLDerivedIDs())
+		default:
+			return nil, xerrors.Errorf("unsupported link_ids value for in-memindex graph: %q", linkIDs)
+		}
+		return memory.NewInMemoryGraph(opts...), nil
+	case "bolt":
+		logger.Info("using bolt graph")
+		if usesURLDerivedLinkIDs(linkGraphURI) {
+			return nil, xerrors.Errorf("URL-derived link IDs are not supported by the bolt graph")
+		}
+		var opts []boltdb.Option
+		if edgeHistory, err := usesEdgeHistory(uri); err != nil {
+			return nil, err
+		} else if edgeHistory {
+			opts = append(opts, boltdb.WithEdgeHistory())
+		}
+		return boltdb.NewBoltGraph(uri.Path, opts...)
 	case "postgresql":
 		logger.Info("using CDB graph")
 		return cockroachdb.NewCockroachDBGraph(linkGraphURI)
@@ -177,10 +279,39 @@ func getLinkGraph(linkGraphURI string, logger *logrus.Entry) (linkGraph, error)
 	}
 }
 
+// usesURLDerivedLinkIDs returns true if the link graph URI enables URL-derived
+// link IDs via the link_ids query parameter.
+func usesURLDerivedLinkIDs(linkGraphURI string) bool {
+	uri, err := url.Parse(linkGraphURI)
+	return err == nil && uri.Query().Get("link_ids") == "url"
+}
+
+// usesEdgeHistory returns true if the link graph URI enables the recording of
+// edge history via the edge_history query parameter.
+func usesEdgeHistory(uri *url.URL) (bool, error) {
+	v := uri.Query().Get("edge_history")
+	if v == "" {
+		return false, nil
+	}
+	edgeHistory, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, xerrors.Errorf("invalid edge_history value: %q", v)
+	}
+	return edgeHistory, nil
+}
+
 type textIndexer interface {
-	Index(text *index.Document) error
-	UpdateScore(linkID uuid.UUID, score float64) error
-	Search(query index.Query) (index.Iterator, error)
+	Index(ctx context.Context, text *index.Document) error
+	IndexBatch(ctx context.Context, docs []*index.Document) error
+	FindByID(ctx context.Context, linkID uuid.UUID) (*index.Document, error)
+	FindByIDs(ctx context.Context, linkIDs []uuid.UUID) ([]*index.Document, error)
+	UpdateAnchorText(ctx context.Context, linkID uuid.UUID, anchorText string) error
+	UpdateScore(ctx context.Context, linkID uuid.UUID, score float64) error
+	Search(ctx context.Context, query index.Query) (index.Iterator, error)
+	Suggest(ctx context.Context, term string, n int) ([]string, error)
+	Complete(ctx context.Context, prefix string, n int) ([]string, error)
+	Delete(ctx context.Context, linkID uuid.UUID) error
+	DeleteStale(ctx context.Context, indexedBefore time.Time) (int, error)
 }
 
 func getTextIndexer(textIndexerURI string, logger *logrus.Entry) (textIndexer, error) {
@@ -197,13 +328,19 @@ func getTextIndexer(textIndexerURI string, logger *logrus.Entry) (textIndexer, e
 	case "in-memindex":
 		logger.Info("using in-memindex indexer")
 		return memindex.NewInMemoryBleveIndexer()
+	case "bleve":
+		if uri.Path == "" {
+			return nil, xerrors.Errorf("bleve indexer URI must specify the path to the index")
+		}
+		logger.Info("using on-disk bleve indexer")
+		return bleveindex.NewBleveIndexer(uri.Path)
 	case "es":
 		nodes := strings.Split(uri.Host, ",")
 		for i := 0; i < len(nodes); i++ {
 			nodes[i] = "http://" + nodes[i]
 		}
 		logger.Info("using ES indexer")
-		return elastic.NewElasticSearchIndexer(nodes, false)
+		return elastic.NewElasticSearchIndexer(nodes, false, logger.WithField("text_indexer", "es"))
 	default:
 		return nil, xerrors.Errorf("unsupported link graph URI scheme: %q", uri.Scheme)
 	}
@@ -1,6 +1,11 @@
 package index
 
-import "golang.org/x/xerrors"
+import (
+	"fmt"
+	"golang.org/x/xerrors"
+	"sort"
+	"strings"
+)
 
 var (
 	// ErrNotFound is returned by the indexer when attempting to look up
@@ -10,4 +15,31 @@ var (
 	// ErrMissingLinkID is returned when attempting to index a document
 	// that does not specify a valid link ID.
 	ErrMissingLinkID = xerrors.New("document does not provide a valid linkID")
+
+	// ErrInvalidQuery is returned by ParseQuery when the query expression
+	// is not syntactically valid.
+	ErrInvalidQuery = xerrors.New("invalid query")
 )
+
+// BatchError is returned by IndexBatch when some of the documents in a batch
+// could not be indexed. The remaining documents have been indexed.
+type BatchError struct {
+	// Errors maps the position of each failed document in the batch to
+	// the error that occurred while indexing it.
+	Errors map[int]error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	positions := make([]int, 0, len(e.Errors))
+	for pos := range e.Errors {
+		positions = append(positions, pos)
+	}
+	sort.Ints(positions)
+
+	msgs := make([]string, len(positions))
+	for i, pos := range positions {
+		msgs[i] = fmt.Sprintf("document %d: %v", pos, e.Errors[pos])
+	}
+	return fmt.Sprintf("%d document(s) could not be indexed: %s", len(e.Errors), strings.Join(msgs, "; "))
+}
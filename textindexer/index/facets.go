@@ -0,0 +1,67 @@
+package index
+
+import (
+	"time"
+)
+
+// MaxHostFacets is the maximum number of hosts for which facet counts are
+// returned.
+const MaxHostFacets = 10
+
+// Filter restricts the documents returned by a search. The zero value does not
+// filter out any documents.
+type Filter struct {
+	// Sites restricts the results to documents whose URL host is equal to
+	// or is a subdomain of any of the listed sites.
+	Sites []string
+
+	// IndexedFrom and IndexedTo restrict the results to documents indexed
+	// within the [IndexedFrom, IndexedTo) range. A zero value for either
+	// end leaves the range open on that side.
+	IndexedFrom time.Time
+	IndexedTo   time.Time
+
+	// MinPageRank restricts the results to documents with a PageRank score
+	// greater than or equal to its value.
+	MinPageRank float64
+}
+
+// Facets contains facet counts for the results of a search.
+type Facets struct {
+	// Hosts contains the counts for the hosts with the most results in
+	// descending count order.
+	Hosts []HostFacet
+
+	// IndexedAt contains the counts for each of the ranges returned by
+	// DateFacetRanges.
+	IndexedAt []DateFacet
+}
+
+// HostFacet is the number of results for documents hosted on Host.
+type HostFacet struct {
+	Host  string
+	Count uint64
+}
+
+// DateFacet is the number of results for documents indexed within the
+// [From, To) range. A zero value for either end leaves the range open on that
+// side.
+type DateFacet struct {
+	Name  string
+	From  time.Time
+	To    time.Time
+	Count uint64
+}
+
+// DateFacetRanges returns the IndexedAt ranges for which facet counts are
+// computed with their counts set to zero. The ranges are relative to now and
+// overlap; each of them covers the period between a point in the past and
+// now.
+func DateFacetRanges(now time.Time) []DateFacet {
+	return []DateFacet{
+		{Name: "day", From: now.AddDate(0, 0, -1)},
+		{Name: "week", From: now.AddDate(0, 0, -7)},
+		{Name: "month", From: now.AddDate(0, -1, 0)},
+		{Name: "year", From: now.AddDate(-1, 0, 0)},
+	}
+}
@@ -1,6 +1,7 @@
 package index
 
 import (
+	"context"
 	"github.com/google/uuid"
 	"time"
 )
@@ -12,10 +13,26 @@ const (
 
 type QueryType uint8
 
+// AnchorTextBoost is the relevance boost that indexers apply to matches
+// against the AnchorText field of a document.
+const AnchorTextBoost = 2.0
+
 type Query struct {
 	Type       QueryType
 	Expression string
 	Offset     uint64
+
+	// Root is the root node of a structured query, typically obtained via
+	// ParseQuery. If set, Type and Expression are ignored.
+	Root QueryNode
+
+	// Filter restricts the documents that match the query.
+	Filter Filter
+
+	// Facets requests facet counts for the matching documents to be
+	// computed. They are made available via the Facets method of the
+	// returned iterator.
+	Facets bool
 }
 
 type Iterator interface {
@@ -30,6 +47,9 @@ type Iterator interface {
 	Document() *Document
 	// TotalCount returns the approximate number of search results
 	TotalCount() uint64
+	// Facets returns the facet counts for the search results or nil if
+	// the query did not request them.
+	Facets() *Facets
 }
 
 type Document struct {
@@ -39,13 +59,48 @@ type Document struct {
 	Title   string
 	Content string
 
+	// AnchorText contains the aggregated text of the anchors that link to
+	// the document. It is searched as a separate, boosted field. Indexers
+	// keep the existing anchor text of a document when it is re-indexed
+	// without one; it is refreshed via UpdateAnchorText.
+	AnchorText string
+
 	IndexedAt time.Time
 	PageRank  float64
 }
 
+// Indexer is implemented by text indexer stores. Cancelling the context passed
+// to a method aborts any request to the underlying store that is still in
+// flight, including the requests issued by iterators to fetch further results.
 type Indexer interface {
-	Index(doc *Document) error
-	FindByID(linkID uuid.UUID) (*Document, error)
-	Search(query Query) (Iterator, error)
-	UpdateScore(linkID uuid.UUID, score float64) error
+	Index(ctx context.Context, doc *Document) error
+	// IndexBatch inserts or updates a batch of documents with the same
+	// semantics as Index. If some of the documents cannot be indexed, a
+	// *BatchError describing the failures is returned and the remaining
+	// documents are indexed.
+	IndexBatch(ctx context.Context, docs []*Document) error
+	FindByID(ctx context.Context, linkID uuid.UUID) (*Document, error)
+	// FindByIDs looks up the documents with the specified link IDs in a
+	// single request. Link IDs that do not have a document are skipped.
+	FindByIDs(ctx context.Context, linkIDs []uuid.UUID) ([]*Document, error)
+	Search(ctx context.Context, query Query) (Iterator, error)
+	// Suggest returns up to n terms from the title and content of the
+	// indexed documents that are within MaxSuggestionEdits(term) edits of
+	// term, best matches first. No suggestions are returned if term is
+	// itself indexed.
+	Suggest(ctx context.Context, term string, n int) ([]string, error)
+	// Complete returns up to n distinct titles of indexed documents that
+	// start with prefix, ignoring case, best matches first.
+	Complete(ctx context.Context, prefix string, n int) ([]string, error)
+	UpdateScore(ctx context.Context, linkID uuid.UUID, score float64) error
+	// UpdateAnchorText replaces the anchor text of the document with the
+	// specified link ID. It returns ErrNotFound if the link has not been
+	// indexed yet.
+	UpdateAnchorText(ctx context.Context, linkID uuid.UUID, anchorText string) error
+	Delete(ctx context.Context, linkID uuid.UUID) error
+	// DeleteStale removes the documents that were last indexed before the
+	// specified time and returns the number of deleted documents. The
+	// placeholder documents created by UpdateScore for documents that have
+	// never been indexed are not affected.
+	DeleteStale(ctx context.Context, indexedBefore time.Time) (int, error)
 }
@@ -0,0 +1,299 @@
+package index
+
+import (
+	"golang.org/x/xerrors"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// dateLayout is the layout of the dates accepted by the date: field scope.
+const dateLayout = "2006-01-02"
+
+// queryFields maps the field scope prefixes recognized by ParseQuery to the
+// fields they select.
+var queryFields = map[string]QueryField{
+	"title":   FieldTitle,
+	"content": FieldContent,
+	"url":     FieldURL,
+	"site":    FieldSite,
+}
+
+// ParseQuery parses a search expression and returns the root node of the
+// resulting query. The following syntax is supported:
+//
+//   - Terms separated by whitespace or AND must all match; OR separates
+//     alternatives and binds looser than AND.
+//   - NOT or a leading minus (e.g. -term) excludes the documents matching the
+//     term, phrase or group that follows.
+//   - Parentheses group sub-expressions.
+//   - Double quotes enclose phrases that must match exactly.
+//   - title:, content:, url: and site: restrict a term, phrase or group to
+//     a single field (e.g. title:"go modules" or site:example.com).
+//   - date:FROM..TO restricts the results to documents indexed between two
+//     dates in YYYY-MM-DD format, inclusive. Either end may be omitted and a
+//     single date matches the documents indexed on that day.
+//
+// The operators are case-sensitive; lowercase "and", "or" and "not" are
+// treated as regular terms.
+func ParseQuery(expr string) (QueryNode, error) {
+	toks, err := lexQuery(expr)
+	if err != nil {
+		return nil, xerrors.Errorf("parse query: %w", err)
+	}
+	if len(toks) == 0 {
+		return nil, xerrors.Errorf("parse query: empty query: %w", ErrInvalidQuery)
+	}
+
+	p := &queryParser{toks: toks}
+	root, err := p.parseOr(FieldAny)
+	if err != nil {
+		return nil, xerrors.Errorf("parse query: %w", err)
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, xerrors.Errorf("parse query: unexpected %q: %w", tok.text, ErrInvalidQuery)
+	}
+	return root, nil
+}
+
+type tokenKind uint8
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokPhrase
+	tokLParen
+	tokRParen
+	tokMinus
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	// The offset of the first rune of the token in the expression.
+	pos int
+}
+
+// lexQuery splits a search expression into tokens.
+func lexQuery(expr string) ([]token, error) {
+	var (
+		toks  []token
+		runes = []rune(expr)
+	)
+	for i := 0; i < len(runes); {
+		switch r := runes[i]; {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			toks = append(toks, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, xerrors.Errorf("unterminated phrase: %w", ErrInvalidQuery)
+			}
+			toks = append(toks, token{kind: tokPhrase, text: string(runes[i+1 : end]), pos: i})
+			i = end + 1
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			toks = append(toks, token{kind: tokMinus, text: "-", pos: i})
+			i++
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune(`()"`, runes[end]) {
+				end++
+			}
+			toks = append(toks, token{kind: tokWord, text: string(runes[i:end]), pos: i})
+			i = end
+		}
+	}
+	return toks, nil
+}
+
+// queryParser is a recursive-descent parser for search expressions.
+type queryParser struct {
+	toks []token
+	pos  int
+}
+
+func (p *queryParser) peek() token {
+	if p.pos >= len(p.toks) {
+		return token{kind: tokEOF}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *queryParser) next() token {
+	tok := p.peek()
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func isOperator(tok token, op string) bool {
+	return tok.kind == tokWord && tok.text == op
+}
+
+// parseOr parses a list of AND expressions separated by OR.
+func (p *queryParser) parseOr(field QueryField) (QueryNode, error) {
+	var children []QueryNode
+	for {
+		child, err := p.parseAnd(field)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+
+		if !isOperator(p.peek(), "OR") {
+			break
+		}
+		p.next()
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &OrNode{Children: children}, nil
+}
+
+// parseAnd parses a list of unary expressions that are optionally separated
+// by AND.
+func (p *queryParser) parseAnd(field QueryField) (QueryNode, error) {
+	var children []QueryNode
+	for {
+		child, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+
+		tok := p.peek()
+		if isOperator(tok, "AND") {
+			p.next()
+			continue
+		} else if tok.kind == tokEOF || tok.kind == tokRParen || isOperator(tok, "OR") {
+			break
+		}
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &AndNode{Children: children}, nil
+}
+
+// parseUnary parses an optionally negated primary expression.
+func (p *queryParser) parseUnary(field QueryField) (QueryNode, error) {
+	if tok := p.peek(); tok.kind == tokMinus || isOperator(tok, "NOT") {
+		p.next()
+		child, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Child: child}, nil
+	}
+	return p.parsePrimary(field)
+}
+
+// parsePrimary parses a term, phrase or parenthesized group, each of which may
+// be prefixed by a field scope.
+func (p *queryParser) parsePrimary(field QueryField) (QueryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokLParen:
+		node, err := p.parseOr(field)
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokRParen {
+			return nil, xerrors.Errorf("missing closing parenthesis: %w", ErrInvalidQuery)
+		}
+		return node, nil
+	case tokPhrase:
+		return &PhraseNode{Field: field, Text: normalizeFieldText(field, tok.text)}, nil
+	case tokWord:
+		if isOperator(tok, "AND") || isOperator(tok, "OR") {
+			return nil, xerrors.Errorf("unexpected %s operator: %w", tok.text, ErrInvalidQuery)
+		}
+		return p.parseWord(field, tok.text)
+	case tokEOF:
+		return nil, xerrors.Errorf("unexpected end of query: %w", ErrInvalidQuery)
+	default:
+		return nil, xerrors.Errorf("unexpected %q: %w", tok.text, ErrInvalidQuery)
+	}
+}
+
+// parseWord parses a term that may be prefixed by a field scope. If the scope
+// is not followed by a value, it applies to the phrase or group that follows.
+func (p *queryParser) parseWord(field QueryField, word string) (QueryNode, error) {
+	sep := strings.IndexByte(word, ':')
+	if sep == -1 {
+		return &MatchNode{Field: field, Text: normalizeFieldText(field, word)}, nil
+	}
+
+	scope, value := word[:sep], word[sep+1:]
+	if scope == "date" {
+		return parseDateRange(value)
+	}
+	scopedField, known := queryFields[scope]
+	if !known {
+		return &MatchNode{Field: field, Text: normalizeFieldText(field, word)}, nil
+	}
+
+	if value == "" {
+		switch p.peek().kind {
+		case tokPhrase, tokLParen:
+			return p.parsePrimary(scopedField)
+		default:
+			return nil, xerrors.Errorf("missing value for %s: field: %w", scope, ErrInvalidQuery)
+		}
+	}
+	return &MatchNode{Field: scopedField, Text: normalizeFieldText(scopedField, value)}, nil
+}
+
+// normalizeFieldText converts the value of a term to the form that is stored
+// in the index for the specified field.
+func normalizeFieldText(field QueryField, text string) string {
+	if field == FieldSite {
+		return strings.ToLower(text)
+	}
+	return text
+}
+
+// parseDateRange parses the value of a date: field scope.
+func parseDateRange(value string) (QueryNode, error) {
+	fromStr, toStr := value, value
+	if sep := strings.Index(value, ".."); sep != -1 {
+		fromStr, toStr = value[:sep], value[sep+2:]
+	}
+	if fromStr == "" && toStr == "" {
+		return nil, xerrors.Errorf("missing value for date: field: %w", ErrInvalidQuery)
+	}
+
+	var (
+		node = new(DateRangeNode)
+		err  error
+	)
+	if fromStr != "" {
+		if node.From, err = time.Parse(dateLayout, fromStr); err != nil {
+			return nil, xerrors.Errorf("invalid date %q: %w", fromStr, ErrInvalidQuery)
+		}
+	}
+	if toStr != "" {
+		if node.To, err = time.Parse(dateLayout, toStr); err != nil {
+			return nil, xerrors.Errorf("invalid date %q: %w", toStr, ErrInvalidQuery)
+		}
+		// The end date is inclusive.
+		node.To = node.To.AddDate(0, 0, 1)
+	}
+	if !node.From.IsZero() && !node.To.IsZero() && !node.From.Before(node.To) {
+		return nil, xerrors.Errorf("date range %q ends before it starts: %w", value, ErrInvalidQuery)
+	}
+	return node, nil
+}
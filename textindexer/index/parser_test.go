@@ -0,0 +1,135 @@
+package index
+
+import (
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"testing"
+	"time"
+)
+
+var _ = gc.Suite(new(ParserTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type ParserTestSuite struct{}
+
+func (s *ParserTestSuite) TestParseQuery(c *gc.C) {
+	specs := []struct {
+		expr string
+		exp  QueryNode
+	}{
+		{
+			expr: "gopher",
+			exp:  &MatchNode{Text: "gopher"},
+		},
+		{
+			expr: `"go modules"`,
+			exp:  &PhraseNode{Text: "go modules"},
+		},
+		{
+			expr: "go modules",
+			exp:  &AndNode{Children: []QueryNode{&MatchNode{Text: "go"}, &MatchNode{Text: "modules"}}},
+		},
+		{
+			expr: "go AND modules OR rust",
+			exp: &OrNode{Children: []QueryNode{
+				&AndNode{Children: []QueryNode{&MatchNode{Text: "go"}, &MatchNode{Text: "modules"}}},
+				&MatchNode{Text: "rust"},
+			}},
+		},
+		{
+			expr: "go (modules OR packages) -vendor NOT dep",
+			exp: &AndNode{Children: []QueryNode{
+				&MatchNode{Text: "go"},
+				&OrNode{Children: []QueryNode{&MatchNode{Text: "modules"}, &MatchNode{Text: "packages"}}},
+				&NotNode{Child: &MatchNode{Text: "vendor"}},
+				&NotNode{Child: &MatchNode{Text: "dep"}},
+			}},
+		},
+		{
+			expr: `title:"go modules" url:blog site:Example.COM`,
+			exp: &AndNode{Children: []QueryNode{
+				&PhraseNode{Field: FieldTitle, Text: "go modules"},
+				&MatchNode{Field: FieldURL, Text: "blog"},
+				&MatchNode{Field: FieldSite, Text: "example.com"},
+			}},
+		},
+		{
+			expr: "content:(gopher OR title:mascot) -site:example.com",
+			exp: &AndNode{Children: []QueryNode{
+				&OrNode{Children: []QueryNode{
+					&MatchNode{Field: FieldContent, Text: "gopher"},
+					&MatchNode{Field: FieldTitle, Text: "mascot"},
+				}},
+				&NotNode{Child: &MatchNode{Field: FieldSite, Text: "example.com"}},
+			}},
+		},
+		{
+			expr: "https://example.com e-mail and or",
+			exp: &AndNode{Children: []QueryNode{
+				&MatchNode{Text: "https://example.com"},
+				&MatchNode{Text: "e-mail"},
+				&MatchNode{Text: "and"},
+				&MatchNode{Text: "or"},
+			}},
+		},
+		{
+			expr: "date:2024-01-01..2024-01-31",
+			exp: &DateRangeNode{
+				From: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+				To:   time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			expr: "date:2024-01-01.. date:..2024-01-31 date:2024-01-15",
+			exp: &AndNode{Children: []QueryNode{
+				&DateRangeNode{From: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+				&DateRangeNode{To: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+				&DateRangeNode{
+					From: time.Date(2024, time.January, 15, 0, 0, 0, 0, time.UTC),
+					To:   time.Date(2024, time.January, 16, 0, 0, 0, 0, time.UTC),
+				},
+			}},
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.expr)
+		got, err := ParseQuery(spec.expr)
+		c.Assert(err, gc.IsNil)
+		c.Assert(got, gc.DeepEquals, spec.exp)
+	}
+}
+
+func (s *ParserTestSuite) TestParseQueryErrors(c *gc.C) {
+	specs := []struct {
+		expr   string
+		errMsg string
+	}{
+		{"", ".*empty query.*"},
+		{"   ", ".*empty query.*"},
+		{`"go modules`, ".*unterminated phrase.*"},
+		{"(go OR rust", ".*missing closing parenthesis.*"},
+		{"go)", `.*unexpected "\)".*`},
+		{"go OR", ".*unexpected end of query.*"},
+		{"AND go", ".*unexpected AND operator.*"},
+		{"NOT", ".*unexpected end of query.*"},
+		{"title: go", ".*missing value for title: field.*"},
+		{"date:", ".*missing value for date: field.*"},
+		{"date:yesterday", `.*invalid date "yesterday".*`},
+		{"date:2024-02-01..2024-01-01", ".*ends before it starts.*"},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.expr)
+		_, err := ParseQuery(spec.expr)
+		c.Assert(err, gc.ErrorMatches, spec.errMsg)
+		c.Assert(xerrors.Is(err, ErrInvalidQuery), gc.Equals, true)
+	}
+}
+
+func (s *ParserTestSuite) TestQueryTerms(c *gc.C) {
+	root, err := ParseQuery(`go "module proxy" -vendor site:example.com (title:gopher OR url:blog)`)
+	c.Assert(err, gc.IsNil)
+	c.Assert(QueryTerms(root), gc.DeepEquals, []string{"go", "module proxy", "gopher"})
+}
@@ -0,0 +1,100 @@
+package index
+
+import (
+	"time"
+)
+
+// QueryField identifies the document field that a query node is matched
+// against.
+type QueryField uint8
+
+const (
+	// FieldAny matches the title, content and anchor text of a document.
+	FieldAny QueryField = iota
+	// FieldTitle matches the document title.
+	FieldTitle
+	// FieldContent matches the document content.
+	FieldContent
+	// FieldURL matches documents whose URL contains the node text. URL
+	// matches are case-sensitive.
+	FieldURL
+	// FieldSite matches documents whose URL host is equal to the node text
+	// or is a subdomain of it.
+	FieldSite
+)
+
+// QueryNode is implemented by the nodes of a structured query.
+type QueryNode interface {
+	queryNode()
+}
+
+// MatchNode matches documents that contain any of the terms in Text.
+type MatchNode struct {
+	Field QueryField
+	Text  string
+}
+
+// PhraseNode matches documents that contain the exact phrase in Text.
+type PhraseNode struct {
+	Field QueryField
+	Text  string
+}
+
+// AndNode matches documents that match all of its children.
+type AndNode struct {
+	Children []QueryNode
+}
+
+// OrNode matches documents that match any of its children.
+type OrNode struct {
+	Children []QueryNode
+}
+
+// NotNode matches documents that do not match its child.
+type NotNode struct {
+	Child QueryNode
+}
+
+// DateRangeNode matches documents that were indexed within the [From, To)
+// range. A zero value for either end leaves the range open on that side.
+type DateRangeNode struct {
+	From time.Time
+	To   time.Time
+}
+
+func (*MatchNode) queryNode()     {}
+func (*PhraseNode) queryNode()    {}
+func (*AndNode) queryNode()       {}
+func (*OrNode) queryNode()        {}
+func (*NotNode) queryNode()       {}
+func (*DateRangeNode) queryNode() {}
+
+// QueryTerms returns the text of the match and phrase nodes of a query that
+// are not negated. It can be used for highlighting the terms that caused a
+// document to match the query.
+func QueryTerms(root QueryNode) []string {
+	var terms []string
+	var visit func(QueryNode)
+	visit = func(n QueryNode) {
+		switch n := n.(type) {
+		case *MatchNode:
+			if n.Field != FieldURL && n.Field != FieldSite {
+				terms = append(terms, n.Text)
+			}
+		case *PhraseNode:
+			if n.Field != FieldURL && n.Field != FieldSite {
+				terms = append(terms, n.Text)
+			}
+		case *AndNode:
+			for _, child := range n.Children {
+				visit(child)
+			}
+		case *OrNode:
+			for _, child := range n.Children {
+				visit(child)
+			}
+		}
+	}
+	visit(root)
+	return terms
+}
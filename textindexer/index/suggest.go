@@ -0,0 +1,116 @@
+package index
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MaxSuggestionEdits returns the maximum number of single-character edits
+// between a query term and the indexed terms that may be suggested in its
+// place. Suggestions are not offered for terms that are shorter than three
+// characters or contain anything other than letters, in which case 0 is
+// returned.
+func MaxSuggestionEdits(term string) int {
+	var length int
+	for _, r := range term {
+		if !unicode.IsLetter(r) {
+			return 0
+		}
+		length++
+	}
+
+	switch {
+	case length < 3:
+		return 0
+	case length <= 5:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// ReplaceQueryTerms returns a copy of the search expression expr in which
+// each term that is matched against the title or content of the documents is
+// replaced by the value returned by replace. Operators, phrases, terms that
+// are scoped to other fields and the whitespace between terms are left
+// untouched. Expressions that cannot be tokenized are returned as-is.
+func ReplaceQueryTerms(expr string, replace func(term string) string) string {
+	toks, err := lexQuery(expr)
+	if err != nil {
+		return expr
+	}
+
+	var (
+		runes = []rune(expr)
+		out   strings.Builder
+		last  int
+
+		// textGroups tracks whether the terms in each of the currently
+		// open groups are matched against the document text.
+		textGroups = []bool{true}
+	)
+	for i, tok := range toks {
+		inText := textGroups[len(textGroups)-1]
+		switch tok.kind {
+		case tokLParen:
+			if i > 0 {
+				if scope, value, scoped := splitFieldScope(toks[i-1]); scoped && value == "" {
+					inText = isTextScope(scope)
+				}
+			}
+			textGroups = append(textGroups, inText)
+		case tokRParen:
+			if len(textGroups) > 1 {
+				textGroups = textGroups[:len(textGroups)-1]
+			}
+		case tokWord:
+			if isOperator(tok, "AND") || isOperator(tok, "OR") || isOperator(tok, "NOT") {
+				continue
+			}
+
+			start, term := tok.pos, tok.text
+			if scope, value, scoped := splitFieldScope(tok); scoped {
+				if !isTextScope(scope) || value == "" {
+					continue
+				}
+				start += len([]rune(tok.text)) - len([]rune(value))
+				term = value
+			} else if !inText {
+				continue
+			}
+
+			if replacement := replace(term); replacement != term {
+				out.WriteString(string(runes[last:start]))
+				out.WriteString(replacement)
+				last = start + len([]rune(term))
+			}
+		}
+	}
+	out.WriteString(string(runes[last:]))
+	return out.String()
+}
+
+// splitFieldScope splits a word token into the field scope recognized by
+// ParseQuery and its value. The scoped result is false if the token does not
+// start with a recognized scope.
+func splitFieldScope(tok token) (scope, value string, scoped bool) {
+	if tok.kind != tokWord {
+		return "", "", false
+	}
+	sep := strings.IndexByte(tok.text, ':')
+	if sep == -1 {
+		return "", "", false
+	}
+	scope, value = tok.text[:sep], tok.text[sep+1:]
+	if _, known := queryFields[scope]; !known && scope != "date" {
+		return "", "", false
+	}
+	return scope, value, true
+}
+
+// isTextScope returns true if the terms restricted to the specified field
+// scope are matched against the title or content of the documents.
+func isTextScope(scope string) bool {
+	field, known := queryFields[scope]
+	return known && (field == FieldTitle || field == FieldContent)
+}
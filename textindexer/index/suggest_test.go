@@ -0,0 +1,50 @@
+package index
+
+import (
+	gc "gopkg.in/check.v1"
+	"strings"
+)
+
+var _ = gc.Suite(new(SuggestTestSuite))
+
+type SuggestTestSuite struct{}
+
+func (s *SuggestTestSuite) TestMaxSuggestionEdits(c *gc.C) {
+	specs := []struct {
+		term string
+		exp  int
+	}{
+		{"go", 0},
+		{"e-mail", 0},
+		{"web2", 0},
+		{"rust", 1},
+		{"gophr", 1},
+		{"gopherz", 2},
+		{"ποιητής", 2},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.term)
+		c.Assert(MaxSuggestionEdits(spec.term), gc.Equals, spec.exp)
+	}
+}
+
+func (s *SuggestTestSuite) TestReplaceQueryTerms(c *gc.C) {
+	specs := []struct {
+		expr string
+		exp  string
+	}{
+		{"gopher", "GOPHER"},
+		{"go  AND modules OR NOT rust", "GO  AND MODULES OR NOT RUST"},
+		{`go "module proxy" -vendor`, `GO "module proxy" -VENDOR`},
+		{"title:gopher content:mascot url:blog site:example.com date:2024-01-01", "title:GOPHER content:MASCOT url:blog site:example.com date:2024-01-01"},
+		{"url:(blog OR title:news) (go rust)", "url:(blog OR title:NEWS) (GO RUST)"},
+		{"title:(go -rust) foo:bar", "title:(GO -RUST) FOO:BAR"},
+		{`"unterminated phrase`, `"unterminated phrase`},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.expr)
+		c.Assert(ReplaceQueryTerms(spec.expr, strings.ToUpper), gc.Equals, spec.exp)
+	}
+}
@@ -0,0 +1,736 @@
+package index
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"sync"
+	"time"
+)
+
+// SuiteBase defines a re-usable set of indexer-related tests that can be
+// executed against any type that implements index.Indexer.
+type SuiteBase struct {
+	idx Indexer
+}
+
+// SetIndexer configures the test-suite to run all tests against idx.
+func (s *SuiteBase) SetIndexer(idx Indexer) {
+	s.idx = idx
+}
+
+// TestIndexDocument verifies the indexing logic for new and existing
+// documents.
+func (s *SuiteBase) TestIndexDocument(c *gc.C) {
+	// Insert new document
+	doc := &Document{
+		LinkID:    uuid.New(),
+		URL:       "http://example.com",
+		Title:     "Illustrious examples",
+		Content:   "Lorem ipsum dolor",
+		IndexedAt: time.Now().Add(-12 * time.Hour).UTC(),
+	}
+	c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+
+	// Update existing document
+	updatedDoc := &Document{
+		LinkID:    doc.LinkID,
+		URL:       "http://example.com",
+		Title:     "A more exciting title",
+		Content:   "Ovidius poeta in terra pontica",
+		IndexedAt: time.Now().UTC(),
+	}
+	c.Assert(s.idx.Index(context.Background(), updatedDoc), gc.IsNil)
+
+	// Insert document without an ID
+	incompleteDoc := &Document{URL: "http://example.com"}
+	err := s.idx.Index(context.Background(), incompleteDoc)
+	c.Assert(xerrors.Is(err, ErrMissingLinkID), gc.Equals, true)
+
+	got, err := s.idx.FindByID(context.Background(), doc.LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.Title, gc.Equals, updatedDoc.Title)
+	c.Assert(got.Content, gc.Equals, updatedDoc.Content)
+}
+
+// TestIndexPreservesPageRank verifies that re-indexing a document does not
+// reset its PageRank score.
+func (s *SuiteBase) TestIndexPreservesPageRank(c *gc.C) {
+	linkID := uuid.New()
+	c.Assert(s.idx.Index(context.Background(), &Document{LinkID: linkID, Title: "first"}), gc.IsNil)
+	c.Assert(s.idx.UpdateScore(context.Background(), linkID, 0.5), gc.IsNil)
+	c.Assert(s.idx.Index(context.Background(), &Document{LinkID: linkID, Title: "second"}), gc.IsNil)
+
+	got, err := s.idx.FindByID(context.Background(), linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.Title, gc.Equals, "second")
+	c.Assert(got.PageRank, gc.Equals, 0.5)
+}
+
+// TestIndexBatch verifies the indexing logic for batches of documents.
+func (s *SuiteBase) TestIndexBatch(c *gc.C) {
+	existing := &Document{LinkID: uuid.New(), Title: "first", IndexedAt: time.Now()}
+	c.Assert(s.idx.Index(context.Background(), existing), gc.IsNil)
+	c.Assert(s.idx.UpdateScore(context.Background(), existing.LinkID, 0.5), gc.IsNil)
+
+	batch := []*Document{
+		{LinkID: existing.LinkID, Title: "Ovidius poeta", IndexedAt: time.Now()},
+		{URL: "http://example.com"},
+		{LinkID: uuid.New(), Title: "Ovidius poeta", IndexedAt: time.Now()},
+	}
+	err := s.idx.IndexBatch(context.Background(), batch)
+	var batchErr *BatchError
+	c.Assert(xerrors.As(err, &batchErr), gc.Equals, true)
+	c.Assert(batchErr.Errors, gc.HasLen, 1)
+	c.Assert(xerrors.Is(batchErr.Errors[1], ErrMissingLinkID), gc.Equals, true)
+
+	// The valid documents are indexed and updates preserve the PageRank
+	// score.
+	got, err := s.idx.FindByID(context.Background(), existing.LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.Title, gc.Equals, "Ovidius poeta")
+	c.Assert(got.PageRank, gc.Equals, 0.5)
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "poeta",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, []uuid.UUID{batch[0].LinkID, batch[2].LinkID})
+
+	// Empty batches are a no-op.
+	c.Assert(s.idx.IndexBatch(context.Background(), nil), gc.IsNil)
+}
+
+// TestFindByID verifies the document lookup logic.
+func (s *SuiteBase) TestFindByID(c *gc.C) {
+	doc := &Document{
+		LinkID:     uuid.New(),
+		URL:        "http://example.com",
+		Title:      "Illustrious examples",
+		Content:    "Lorem ipsum dolor",
+		AnchorText: "examples",
+	}
+	c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+
+	// Look up doc
+	got, err := s.idx.FindByID(context.Background(), doc.LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.LinkID, gc.Equals, doc.LinkID)
+	c.Assert(got.URL, gc.Equals, doc.URL)
+	c.Assert(got.Title, gc.Equals, doc.Title)
+	c.Assert(got.Content, gc.Equals, doc.Content)
+	c.Assert(got.AnchorText, gc.Equals, doc.AnchorText)
+	c.Assert(got.IndexedAt.Equal(doc.IndexedAt), gc.Equals, true)
+
+	// Look up unknown
+	_, err = s.idx.FindByID(context.Background(), uuid.New())
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+}
+
+// TestFindByIDs verifies the batched document lookup logic.
+func (s *SuiteBase) TestFindByIDs(c *gc.C) {
+	var linkIDs []uuid.UUID
+	for i := 0; i < 3; i++ {
+		doc := &Document{
+			LinkID:  uuid.New(),
+			URL:     fmt.Sprintf("http://example.com/%d", i),
+			Title:   "Illustrious examples",
+			Content: "Lorem ipsum dolor",
+		}
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+		linkIDs = append(linkIDs, doc.LinkID)
+	}
+
+	// Unknown link IDs are skipped.
+	got, err := s.idx.FindByIDs(context.Background(), []uuid.UUID{linkIDs[0], uuid.New(), linkIDs[2]})
+	c.Assert(err, gc.IsNil)
+	gotIDs := make(map[uuid.UUID]bool)
+	for _, doc := range got {
+		gotIDs[doc.LinkID] = true
+	}
+	c.Assert(gotIDs, gc.DeepEquals, map[uuid.UUID]bool{linkIDs[0]: true, linkIDs[2]: true})
+
+	got, err = s.idx.FindByIDs(context.Background(), nil)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got, gc.HasLen, 0)
+}
+
+// TestPhraseSearch verifies the document search logic when searching for
+// exact phrases.
+func (s *SuiteBase) TestPhraseSearch(c *gc.C) {
+	var (
+		numDocs = 50
+		expIDs  []uuid.UUID
+	)
+	for i := 0; i < numDocs; i++ {
+		id := uuid.New()
+		doc := &Document{
+			LinkID:  id,
+			Title:   fmt.Sprintf("doc with ID %s", id.String()),
+			Content: "Lorem Ipsum Dolor",
+		}
+
+		if i%5 == 0 {
+			doc.Content = "Lorem Dolor Ipsum"
+			expIDs = append(expIDs, id)
+		}
+
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+		c.Assert(s.idx.UpdateScore(context.Background(), id, float64(numDocs-i)), gc.IsNil)
+	}
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypePhrase,
+		Expression: "lorem dolor ipsum",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, expIDs)
+}
+
+// TestMatchSearch verifies the document search logic when searching for
+// keyword matches.
+func (s *SuiteBase) TestMatchSearch(c *gc.C) {
+	var (
+		numDocs = 50
+		expIDs  []uuid.UUID
+	)
+	for i := 0; i < numDocs; i++ {
+		id := uuid.New()
+		expIDs = append(expIDs, id)
+		doc := &Document{
+			LinkID:  id,
+			Title:   fmt.Sprintf("doc with ID %s", id.String()),
+			Content: "Ovidius poeta in terra pontica",
+		}
+
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+		c.Assert(s.idx.UpdateScore(context.Background(), id, float64(numDocs-i)), gc.IsNil)
+	}
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "poeta",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, expIDs)
+}
+
+// TestMatchSearchWithOffset verifies the document search logic when searching
+// for keyword matches and skipping some results.
+func (s *SuiteBase) TestMatchSearchWithOffset(c *gc.C) {
+	var (
+		numDocs = 50
+		expIDs  []uuid.UUID
+	)
+	for i := 0; i < numDocs; i++ {
+		id := uuid.New()
+		expIDs = append(expIDs, id)
+		doc := &Document{
+			LinkID:  id,
+			Title:   fmt.Sprintf("doc with ID %s", id.String()),
+			Content: "Ovidius poeta in terra pontica",
+		}
+
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+		c.Assert(s.idx.UpdateScore(context.Background(), id, float64(numDocs-i)), gc.IsNil)
+	}
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "poeta",
+		Offset:     20,
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, expIDs[20:])
+
+	// Search with offset beyond the total number of results
+	it, err = s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "poeta",
+		Offset:     200,
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.HasLen, 0)
+}
+
+// TestAnchorTextSearch verifies that documents can be found by the text of
+// the anchors that link to them.
+func (s *SuiteBase) TestAnchorTextSearch(c *gc.C) {
+	doc := &Document{
+		LinkID:     uuid.New(),
+		Title:      "Home page",
+		Content:    "Welcome",
+		AnchorText: "gopher conference",
+	}
+	c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "gopher",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, []uuid.UUID{doc.LinkID})
+}
+
+// TestUpdateAnchorText verifies that the anchor text of indexed documents can
+// be replaced and is preserved when documents are re-indexed without one.
+func (s *SuiteBase) TestUpdateAnchorText(c *gc.C) {
+	doc := &Document{
+		LinkID:     uuid.New(),
+		Title:      "Home page",
+		Content:    "Welcome",
+		AnchorText: "gopher conference",
+	}
+	c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+	c.Assert(s.idx.UpdateAnchorText(context.Background(), doc.LinkID, "gopher meetup"), gc.IsNil)
+
+	// Re-indexing the document without anchor text keeps the updated one.
+	c.Assert(s.idx.Index(context.Background(), &Document{
+		LinkID:  doc.LinkID,
+		Title:   "Home page",
+		Content: "Welcome back",
+	}), gc.IsNil)
+	got, err := s.idx.FindByID(context.Background(), doc.LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.AnchorText, gc.Equals, "gopher meetup")
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "meetup",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, []uuid.UUID{doc.LinkID})
+
+	// Unknown documents and score placeholders are not updated.
+	err = s.idx.UpdateAnchorText(context.Background(), uuid.New(), "gopher")
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+
+	placeholderID := uuid.New()
+	c.Assert(s.idx.UpdateScore(context.Background(), placeholderID, 0.5), gc.IsNil)
+	err = s.idx.UpdateAnchorText(context.Background(), placeholderID, "gopher")
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+}
+
+// TestStructuredSearch verifies the document search logic for structured
+// queries.
+func (s *SuiteBase) TestStructuredSearch(c *gc.C) {
+	docs := []*Document{
+		{
+			LinkID:  uuid.New(),
+			URL:     "https://blog.example.com/go",
+			Title:   "Go modules",
+			Content: "dependency management",
+		},
+		{
+			LinkID:  uuid.New(),
+			URL:     "https://example.com/rust",
+			Title:   "Rust crates",
+			Content: "dependency management",
+		},
+		{
+			LinkID:  uuid.New(),
+			URL:     "https://other.org/go-vendor",
+			Title:   "Vendoring in Go",
+			Content: "copy dependencies",
+		},
+	}
+	for i, doc := range docs {
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+		c.Assert(s.idx.UpdateScore(context.Background(), doc.LinkID, float64(len(docs)-i)), gc.IsNil)
+	}
+
+	specs := []struct {
+		expr   string
+		expDoc []int
+	}{
+		{expr: "dependency management", expDoc: []int{0, 1}},
+		{expr: "dependency -rust", expDoc: []int{0}},
+		{expr: "title:rust OR content:copy", expDoc: []int{1, 2}},
+		{expr: `title:"go modules"`, expDoc: []int{0}},
+		{expr: `title:"modules go"`},
+		{expr: "site:example.com", expDoc: []int{0, 1}},
+		{expr: "site:blog.example.com", expDoc: []int{0}},
+		{expr: "site:ample.com"},
+		{expr: "url:go", expDoc: []int{0, 2}},
+		{expr: "NOT site:example.com", expDoc: []int{2}},
+		{expr: "(go OR rust) -url:blog", expDoc: []int{1, 2}},
+	}
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.expr)
+		root, err := ParseQuery(spec.expr)
+		c.Assert(err, gc.IsNil)
+
+		it, err := s.idx.Search(context.Background(), Query{Root: root})
+		c.Assert(err, gc.IsNil)
+		c.Assert(iterateDocs(c, it), gc.DeepEquals, docIDs(docs, spec.expDoc))
+	}
+
+	// Date ranges are matched against the time the documents were indexed.
+	hourAgo := time.Now().Add(-time.Hour)
+	it, err := s.idx.Search(context.Background(), Query{Root: &AndNode{Children: []QueryNode{
+		&MatchNode{Text: "dependency"},
+		&DateRangeNode{From: hourAgo},
+	}}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, docIDs(docs, []int{0, 1}))
+
+	it, err = s.idx.Search(context.Background(), Query{Root: &DateRangeNode{To: hourAgo}})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.HasLen, 0)
+}
+
+// TestSearchFilters verifies that search results can be filtered by host,
+// indexing date and PageRank score.
+func (s *SuiteBase) TestSearchFilters(c *gc.C) {
+	docs := s.indexFacetDocs(c)
+
+	hourAgo := time.Now().Add(-time.Hour)
+	specs := []struct {
+		descr  string
+		filter Filter
+		expDoc []int
+	}{
+		{descr: "no filter", expDoc: []int{0, 1, 2, 3}},
+		{descr: "site", filter: Filter{Sites: []string{"example.com"}}, expDoc: []int{0, 1}},
+		{descr: "multiple sites", filter: Filter{Sites: []string{"OTHER.org", "blog.example.com"}}, expDoc: []int{1, 2, 3}},
+		{descr: "min PageRank", filter: Filter{MinPageRank: 3}, expDoc: []int{0, 1}},
+		{descr: "indexed from", filter: Filter{IndexedFrom: hourAgo}, expDoc: []int{0, 1, 2, 3}},
+		{descr: "indexed to", filter: Filter{IndexedTo: hourAgo}},
+		{descr: "combined", filter: Filter{Sites: []string{"other.org"}, MinPageRank: 2}, expDoc: []int{2}},
+	}
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		it, err := s.idx.Search(context.Background(), Query{Expression: "gopher", Filter: spec.filter})
+		c.Assert(err, gc.IsNil)
+		c.Assert(iterateDocs(c, it), gc.DeepEquals, docIDs(docs, spec.expDoc))
+	}
+}
+
+// TestSearchFacets verifies that facet counts are computed for the search
+// results when requested.
+func (s *SuiteBase) TestSearchFacets(c *gc.C) {
+	_ = s.indexFacetDocs(c)
+
+	// Facets are only computed when requested.
+	it, err := s.idx.Search(context.Background(), Query{Expression: "gopher"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Facets(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+
+	it, err = s.idx.Search(context.Background(), Query{Expression: "gopher", Facets: true})
+	c.Assert(err, gc.IsNil)
+	facets := it.Facets()
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(facets, gc.NotNil)
+	c.Assert(facets.Hosts, gc.HasLen, 3)
+	c.Assert(facets.Hosts[0], gc.Equals, HostFacet{Host: "other.org", Count: 2})
+	c.Assert(hostCounts(facets.Hosts), gc.DeepEquals, map[string]uint64{
+		"other.org":        2,
+		"example.com":      1,
+		"blog.example.com": 1,
+	})
+	c.Assert(facets.IndexedAt, gc.HasLen, len(DateFacetRanges(time.Now())))
+	for _, df := range facets.IndexedAt {
+		c.Assert(df.Count, gc.Equals, uint64(4), gc.Commentf("date facet %q", df.Name))
+	}
+
+	// Facets are computed for the filtered results.
+	it, err = s.idx.Search(context.Background(), Query{
+		Expression: "gopher",
+		Filter:     Filter{Sites: []string{"example.com"}},
+		Facets:     true,
+	})
+	c.Assert(err, gc.IsNil)
+	facets = it.Facets()
+	c.Assert(it.Close(), gc.IsNil)
+	c.Assert(hostCounts(facets.Hosts), gc.DeepEquals, map[string]uint64{
+		"example.com":      1,
+		"blog.example.com": 1,
+	})
+	for _, df := range facets.IndexedAt {
+		c.Assert(df.Count, gc.Equals, uint64(2), gc.Commentf("date facet %q", df.Name))
+	}
+}
+
+// indexFacetDocs indexes a set of documents hosted on different sites with
+// descending PageRank scores.
+func (s *SuiteBase) indexFacetDocs(c *gc.C) []*Document {
+	docs := []*Document{
+		{LinkID: uuid.New(), URL: "https://example.com/a", Content: "gopher"},
+		{LinkID: uuid.New(), URL: "https://blog.example.com/b", Content: "gopher"},
+		{LinkID: uuid.New(), URL: "https://other.org/c", Content: "gopher"},
+		{LinkID: uuid.New(), URL: "https://other.org/d", Content: "gopher"},
+	}
+	for i, doc := range docs {
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+		c.Assert(s.idx.UpdateScore(context.Background(), doc.LinkID, float64(len(docs)-i)), gc.IsNil)
+	}
+	return docs
+}
+
+// TestSuggest verifies that spelling suggestions are built from the terms of
+// the indexed documents.
+func (s *SuiteBase) TestSuggest(c *gc.C) {
+	docs := []*Document{
+		{LinkID: uuid.New(), Title: "Gopher", Content: "The gopher is the Go mascot"},
+		{LinkID: uuid.New(), Content: "Gophers everywhere; one gopher per package"},
+		{LinkID: uuid.New(), Content: "Mascots are everywhere"},
+	}
+	for _, doc := range docs {
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+	}
+	c.Assert(s.idx.Delete(context.Background(), docs[2].LinkID), gc.IsNil)
+
+	specs := []struct {
+		term string
+		n    int
+		exp  []string
+	}{
+		// Same edit distance; ordered by document frequency.
+		{term: "gopherz", n: 5, exp: []string{"gopher", "gophers"}},
+		{term: "Gopherz", n: 1, exp: []string{"gopher"}},
+		// Short terms only allow a single edit.
+		{term: "gophr", n: 5, exp: []string{"gopher"}},
+		// The first letter may be misspelled too.
+		{term: "kopher", n: 1, exp: []string{"gopher"}},
+		// Terms of deleted documents are not suggested.
+		{term: "mascotz", n: 5, exp: []string{"mascot"}},
+		// Indexed terms, short terms and terms without any similar indexed
+		// terms yield no suggestions.
+		{term: "mascot", n: 5},
+		{term: "Gopher", n: 5},
+		{term: "og", n: 5},
+		{term: "zebra", n: 5},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.term)
+		got, err := s.idx.Suggest(context.Background(), spec.term, spec.n)
+		c.Assert(err, gc.IsNil)
+		if len(spec.exp) == 0 {
+			c.Assert(got, gc.HasLen, 0)
+			continue
+		}
+		c.Assert(got, gc.DeepEquals, spec.exp)
+	}
+}
+
+// TestComplete verifies that completions are built from the titles of the
+// indexed documents.
+func (s *SuiteBase) TestComplete(c *gc.C) {
+	docs := []*Document{
+		{LinkID: uuid.New(), Title: "Go modules"},
+		{LinkID: uuid.New(), Title: "Go modules"},
+		{LinkID: uuid.New(), Title: "Gophers unite"},
+		{LinkID: uuid.New(), Title: "Rust book"},
+		{LinkID: uuid.New(), Content: "Go modules"},
+	}
+	for _, doc := range docs {
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+	}
+	c.Assert(s.idx.UpdateScore(context.Background(), docs[2].LinkID, 0.9), gc.IsNil)
+
+	complete := func(prefix string, n int) []string {
+		got, err := s.idx.Complete(context.Background(), prefix, n)
+		c.Assert(err, gc.IsNil)
+		return got
+	}
+
+	// Titles are matched by prefix, ignoring case, and returned once.
+	c.Assert(complete("go", 5), gc.DeepEquals, []string{"Gophers unite", "Go modules"})
+	c.Assert(complete("GO M", 5), gc.DeepEquals, []string{"Go modules"})
+	c.Assert(complete("go", 1), gc.DeepEquals, []string{"Gophers unite"})
+	c.Assert(complete("modules", 5), gc.HasLen, 0)
+	c.Assert(complete("", 5), gc.HasLen, 0)
+
+	// Completions reflect updated and deleted documents.
+	docs[3].Title = "Rustacean handbook"
+	c.Assert(s.idx.Index(context.Background(), docs[3]), gc.IsNil)
+	c.Assert(complete("rust", 5), gc.DeepEquals, []string{"Rustacean handbook"})
+	c.Assert(s.idx.Delete(context.Background(), docs[2].LinkID), gc.IsNil)
+	c.Assert(complete("goph", 5), gc.HasLen, 0)
+}
+
+// TestUpdateScore checks that PageRank score updates work as expected.
+func (s *SuiteBase) TestUpdateScore(c *gc.C) {
+	var (
+		numDocs = 100
+		expIDs  []uuid.UUID
+	)
+	for i := 0; i < numDocs; i++ {
+		id := uuid.New()
+		expIDs = append(expIDs, id)
+		doc := &Document{
+			LinkID:  id,
+			Title:   fmt.Sprintf("doc with ID %s", id.String()),
+			Content: "Ovidius poeta in terra pontica",
+		}
+
+		c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+	}
+
+	// Update the PageRank scores so each document will have a score
+	// equal to its position in the expected results.
+	for i, id := range expIDs {
+		c.Assert(s.idx.UpdateScore(context.Background(), id, float64(numDocs-i)), gc.IsNil)
+	}
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "poeta",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, expIDs)
+
+	// Update the scores in reverse order.
+	for i, id := range expIDs {
+		c.Assert(s.idx.UpdateScore(context.Background(), id, float64(i)), gc.IsNil)
+	}
+
+	it, err = s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "poeta",
+	})
+	c.Assert(err, gc.IsNil)
+	got := iterateDocs(c, it)
+	for i, id := range got {
+		c.Assert(id, gc.Equals, expIDs[len(expIDs)-1-i])
+	}
+}
+
+// TestUpdateScoreForUnknownDocument verifies that a placeholder document is
+// created when setting the score for an unknown document.
+func (s *SuiteBase) TestUpdateScoreForUnknownDocument(c *gc.C) {
+	linkID := uuid.New()
+	c.Assert(s.idx.UpdateScore(context.Background(), linkID, 0.5), gc.IsNil)
+
+	doc, err := s.idx.FindByID(context.Background(), linkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(doc.URL, gc.Equals, "")
+	c.Assert(doc.Title, gc.Equals, "")
+	c.Assert(doc.Content, gc.Equals, "")
+	c.Assert(doc.IndexedAt.IsZero(), gc.Equals, true)
+	c.Assert(doc.PageRank, gc.Equals, 0.5)
+}
+
+// TestDelete verifies that deleted documents can no longer be looked up or
+// found by searches.
+func (s *SuiteBase) TestDelete(c *gc.C) {
+	doc := &Document{LinkID: uuid.New(), Title: "Ovidius poeta"}
+	c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+	c.Assert(s.idx.Delete(context.Background(), doc.LinkID), gc.IsNil)
+
+	_, err := s.idx.FindByID(context.Background(), doc.LinkID)
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "poeta",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.HasLen, 0)
+
+	// Deleting an unknown document
+	err = s.idx.Delete(context.Background(), doc.LinkID)
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+}
+
+// TestDeleteStale verifies that documents that have not been indexed since a
+// particular point in time can be removed in bulk.
+func (s *SuiteBase) TestDeleteStale(c *gc.C) {
+	stale := &Document{LinkID: uuid.New(), Title: "Ovidius poeta", IndexedAt: time.Now()}
+	c.Assert(s.idx.Index(context.Background(), stale), gc.IsNil)
+	time.Sleep(10 * time.Millisecond)
+	indexedBefore := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	fresh := &Document{LinkID: uuid.New(), Title: "Ovidius poeta", IndexedAt: time.Now()}
+	c.Assert(s.idx.Index(context.Background(), fresh), gc.IsNil)
+
+	// Placeholder documents are not affected.
+	placeholderID := uuid.New()
+	c.Assert(s.idx.UpdateScore(context.Background(), placeholderID, 0.5), gc.IsNil)
+
+	deleted, err := s.idx.DeleteStale(context.Background(), indexedBefore)
+	c.Assert(err, gc.IsNil)
+	c.Assert(deleted, gc.Equals, 1)
+
+	_, err = s.idx.FindByID(context.Background(), stale.LinkID)
+	c.Assert(xerrors.Is(err, ErrNotFound), gc.Equals, true)
+	_, err = s.idx.FindByID(context.Background(), placeholderID)
+	c.Assert(err, gc.IsNil)
+
+	it, err := s.idx.Search(context.Background(), Query{
+		Type:       QueryTypeMatch,
+		Expression: "poeta",
+	})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.DeepEquals, []uuid.UUID{fresh.LinkID})
+
+	// Nothing else is stale.
+	deleted, err = s.idx.DeleteStale(context.Background(), indexedBefore)
+	c.Assert(err, gc.IsNil)
+	c.Assert(deleted, gc.Equals, 0)
+}
+
+// TestConcurrentSearches verifies that the index can be searched while
+// documents are being indexed.
+func (s *SuiteBase) TestConcurrentSearches(c *gc.C) {
+	numDocs := 100
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numDocs; i++ {
+			doc := &Document{LinkID: uuid.New(), Content: "Ovidius poeta in terra pontica"}
+			c.Check(s.idx.Index(context.Background(), doc), gc.IsNil)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numDocs; i++ {
+			it, err := s.idx.Search(context.Background(), Query{Expression: "poeta"})
+			if !c.Check(err, gc.IsNil) {
+				return
+			}
+			for it.Next() {
+				c.Check(it.Document().Content, gc.Equals, "Ovidius poeta in terra pontica")
+			}
+			c.Check(it.Error(), gc.IsNil)
+			c.Check(it.Close(), gc.IsNil)
+		}
+	}()
+	wg.Wait()
+
+	it, err := s.idx.Search(context.Background(), Query{Expression: "poeta"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(iterateDocs(c, it), gc.HasLen, numDocs)
+}
+
+func docIDs(docs []*Document, indices []int) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, i := range indices {
+		ids = append(ids, docs[i].LinkID)
+	}
+	return ids
+}
+
+func hostCounts(hosts []HostFacet) map[string]uint64 {
+	counts := make(map[string]uint64)
+	for _, h := range hosts {
+		counts[h.Host] = h.Count
+	}
+	return counts
+}
+
+func iterateDocs(c *gc.C, it Iterator) []uuid.UUID {
+	var seen []uuid.UUID
+	for it.Next() {
+		seen = append(seen, it.Document().LinkID)
+	}
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+	return seen
+}
@@ -0,0 +1,381 @@
+// Package bleveindex provides a text indexer that persists its index to disk
+// using bleve.
+package bleveindex
+
+import (
+	"Search_Engine/textindexer/index"
+	"Search_Engine/textindexer/store/internal/bleveutil"
+	"context"
+	"encoding/json"
+	"github.com/blevesearch/bleve"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+	"sync"
+	"time"
+)
+
+// The size of each page of results that is cached locally by the iterator.
+const BatchSize = 10
+
+// Compile-time check to ensure BleveIndexer implements Indexer.
+var _ index.Indexer = (*BleveIndexer)(nil)
+
+// docKeyPrefix is prepended to the link ID of each document to obtain the key
+// under which the full document is stored as internal index data.
+var docKeyPrefix = []byte("doc/")
+
+// BleveIndexer is an Indexer implementation that stores its index in a bleve
+// index on disk. Besides the indexed fields, the full contents of each
+// document are stored in the index so that they survive restarts.
+//
+// Documents can be looked up and searched while other documents are being
+// indexed; updates to the index are serialized.
+type BleveIndexer struct {
+	// mu serializes updates so that concurrent updates to the same
+	// document cannot overwrite each other's changes.
+	mu  sync.Mutex
+	idx bleve.Index
+}
+
+// NewBleveIndexer opens the bleve index at path, creating it if it does not
+// exist yet.
+func NewBleveIndexer(path string) (*BleveIndexer, error) {
+	idx, err := bleve.Open(path)
+	if err == bleve.ErrorIndexPathDoesNotExist {
+		idx, err = bleveutil.NewIndex(path)
+	}
+	if err != nil {
+		return nil, xerrors.Errorf("open bleve index: %w", err)
+	}
+
+	return &BleveIndexer{idx: idx}, nil
+}
+
+// Close the indexer and release any allocated resources.
+func (i *BleveIndexer) Close() error {
+	return i.idx.Close()
+}
+
+// Index inserts a new document to the index or updates the index entry
+// for and existing document.
+func (i *BleveIndexer) Index(ctx context.Context, doc *index.Document) error {
+	if doc.LinkID == uuid.Nil {
+		return xerrors.Errorf("index: %w", index.ErrMissingLinkID)
+	}
+
+	doc.IndexedAt = time.Now()
+	dcopy := copyDoc(doc)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// If updating, preserve existing PageRank score and anchor text
+	orig, err := i.findByID(dcopy.LinkID)
+	if err == nil {
+		dcopy.PageRank = orig.PageRank
+		if dcopy.AnchorText == "" {
+			dcopy.AnchorText = orig.AnchorText
+		}
+	} else if !xerrors.Is(err, index.ErrNotFound) {
+		return xerrors.Errorf("index: %w", err)
+	}
+
+	if err := i.putDoc(dcopy); err != nil {
+		return xerrors.Errorf("index: %w", err)
+	}
+	return nil
+}
+
+// IndexBatch inserts or updates a batch of documents using a single bleve
+// batch.
+func (i *BleveIndexer) IndexBatch(ctx context.Context, docs []*index.Document) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var (
+		now      = time.Now()
+		b        = i.idx.NewBatch()
+		batchErr = &index.BatchError{Errors: make(map[int]error)}
+	)
+	for pos, doc := range docs {
+		if doc.LinkID == uuid.Nil {
+			batchErr.Errors[pos] = index.ErrMissingLinkID
+			continue
+		}
+
+		doc.IndexedAt = now
+		dcopy := copyDoc(doc)
+
+		// If updating, preserve existing PageRank score and anchor text
+		orig, err := i.findByID(dcopy.LinkID)
+		if err == nil {
+			dcopy.PageRank = orig.PageRank
+			if dcopy.AnchorText == "" {
+				dcopy.AnchorText = orig.AnchorText
+			}
+		} else if !xerrors.Is(err, index.ErrNotFound) {
+			batchErr.Errors[pos] = err
+			continue
+		}
+
+		if err := addDoc(b, dcopy); err != nil {
+			batchErr.Errors[pos] = err
+		}
+	}
+
+	if err := i.idx.Batch(b); err != nil {
+		return xerrors.Errorf("index batch: %w", err)
+	}
+	if len(batchErr.Errors) != 0 {
+		return xerrors.Errorf("index batch: %w", batchErr)
+	}
+	return nil
+}
+
+// FindByID looks up a document by its link ID.
+func (i *BleveIndexer) FindByID(ctx context.Context, linkID uuid.UUID) (*index.Document, error) {
+	doc, err := i.findByID(linkID)
+	if err != nil {
+		return nil, xerrors.Errorf("find by ID: %w", err)
+	}
+	return doc, nil
+}
+
+// FindByIDs looks up the documents with the specified link IDs.
+func (i *BleveIndexer) FindByIDs(_ context.Context, linkIDs []uuid.UUID) ([]*index.Document, error) {
+	docs := make([]*index.Document, 0, len(linkIDs))
+	for _, linkID := range linkIDs {
+		doc, err := i.findByID(linkID)
+		if xerrors.Is(err, index.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return nil, xerrors.Errorf("find by IDs: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// findByID loads the stored copy of the document with the specified link ID.
+func (i *BleveIndexer) findByID(linkID uuid.UUID) (*index.Document, error) {
+	v, err := i.idx.GetInternal(docKey(linkID))
+	if err != nil {
+		return nil, err
+	} else if v == nil {
+		return nil, index.ErrNotFound
+	}
+
+	doc := new(index.Document)
+	if err := json.Unmarshal(v, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// Search the index for a particular query and return back a result
+// iterator.
+func (i *BleveIndexer) Search(ctx context.Context, q index.Query) (index.Iterator, error) {
+	searchReq := bleve.NewSearchRequest(bleveutil.Query(q))
+	searchReq.SortBy([]string{"-PageRank", "-_score"})
+	searchReq.Size = BatchSize
+	searchReq.From = int(q.Offset)
+	var dateRanges []index.DateFacet
+	if q.Facets {
+		dateRanges = bleveutil.AddFacetRequests(searchReq, time.Now())
+	}
+	rs, err := i.idx.SearchInContext(ctx, searchReq)
+	if err != nil {
+		return nil, xerrors.Errorf("search: %w", err)
+	}
+
+	it := &bleveIterator{ctx: ctx, idx: i, searchReq: searchReq, rs: rs, cumIdx: q.Offset}
+	if q.Facets {
+		// The facets only need to be computed once.
+		it.facets = bleveutil.Facets(rs, dateRanges)
+		searchReq.Facets = nil
+	}
+	return it, nil
+}
+
+// Suggest returns up to n indexed terms that are spelled similarly to term.
+func (i *BleveIndexer) Suggest(_ context.Context, term string, n int) ([]string, error) {
+	suggestions, err := bleveutil.Suggest(i.idx, term, n)
+	if err != nil {
+		return nil, xerrors.Errorf("suggest: %w", err)
+	}
+	return suggestions, nil
+}
+
+// Complete returns up to n distinct titles of indexed documents that start
+// with prefix.
+func (i *BleveIndexer) Complete(ctx context.Context, prefix string, n int) ([]string, error) {
+	completions, err := bleveutil.Complete(ctx, i.idx, prefix, n)
+	if err != nil {
+		return nil, xerrors.Errorf("complete: %w", err)
+	}
+	return completions, nil
+}
+
+// UpdateScore updates the PageRank score for a document with the specified
+// link ID. If no such document exists, a placeholder document with the
+// provided score will be created.
+func (i *BleveIndexer) UpdateScore(ctx context.Context, linkID uuid.UUID, score float64) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	doc, err := i.findByID(linkID)
+	if xerrors.Is(err, index.ErrNotFound) {
+		doc = &index.Document{LinkID: linkID}
+	} else if err != nil {
+		return xerrors.Errorf("update score: %w", err)
+	}
+
+	doc.PageRank = score
+	if err := i.putDoc(doc); err != nil {
+		return xerrors.Errorf("update score: %w", err)
+	}
+	return nil
+}
+
+// UpdateAnchorText replaces the anchor text of the document with the
+// specified link ID.
+func (i *BleveIndexer) UpdateAnchorText(ctx context.Context, linkID uuid.UUID, anchorText string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// Placeholder documents created by UpdateScore are not updated as they
+	// would otherwise show up in search results.
+	doc, err := i.findByID(linkID)
+	if err == nil && doc.IndexedAt.IsZero() {
+		err = index.ErrNotFound
+	}
+	if err != nil {
+		return xerrors.Errorf("update anchor text: %w", err)
+	}
+
+	doc.AnchorText = anchorText
+	if err := i.putDoc(doc); err != nil {
+		return xerrors.Errorf("update anchor text: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the document with the specified link ID from the index.
+func (i *BleveIndexer) Delete(ctx context.Context, linkID uuid.UUID) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, err := i.findByID(linkID); err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	}
+
+	b := i.idx.NewBatch()
+	b.Delete(linkID.String())
+	b.DeleteInternal(docKey(linkID))
+	if err := i.idx.Batch(b); err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
+// DeleteStale removes the documents that were last indexed before the
+// specified time.
+func (i *BleveIndexer) DeleteStale(ctx context.Context, indexedBefore time.Time) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	stale, err := i.staleDocs(ctx, indexedBefore)
+	if err != nil {
+		return 0, xerrors.Errorf("delete stale: %w", err)
+	}
+
+	for start := 0; start < len(stale); start += BatchSize {
+		end := start + BatchSize
+		if end > len(stale) {
+			end = len(stale)
+		}
+
+		b := i.idx.NewBatch()
+		for _, linkID := range stale[start:end] {
+			b.Delete(linkID.String())
+			b.DeleteInternal(docKey(linkID))
+		}
+		if err := i.idx.Batch(b); err != nil {
+			return start, xerrors.Errorf("delete stale: %w", err)
+		}
+	}
+	return len(stale), nil
+}
+
+// staleDocs returns the IDs of the documents that were last indexed before
+// the specified time. The IndexedAt values of the candidates found by the
+// index are checked against the stored documents as the placeholder documents
+// created by UpdateScore have a zero IndexedAt value that cannot be
+// represented in the index.
+func (i *BleveIndexer) staleDocs(ctx context.Context, indexedBefore time.Time) ([]uuid.UUID, error) {
+	exclusive := false
+	dq := bleve.NewDateRangeInclusiveQuery(time.Time{}, indexedBefore, nil, &exclusive)
+	dq.SetField("IndexedAt")
+
+	var stale []uuid.UUID
+	searchReq := bleve.NewSearchRequestOptions(dq, BatchSize, 0, false)
+	for {
+		rs, err := i.idx.SearchInContext(ctx, searchReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hit := range rs.Hits {
+			linkID, err := uuid.Parse(hit.ID)
+			if err != nil {
+				return nil, err
+			}
+			doc, err := i.findByID(linkID)
+			if err != nil {
+				return nil, err
+			}
+			if !doc.IndexedAt.IsZero() && doc.IndexedAt.Before(indexedBefore) {
+				stale = append(stale, linkID)
+			}
+		}
+
+		searchReq.From += len(rs.Hits)
+		if len(rs.Hits) == 0 || uint64(searchReq.From) >= rs.Total {
+			return stale, nil
+		}
+	}
+}
+
+// putDoc indexes doc and stores a copy of it in a single batch so that the
+// index and the stored documents cannot get out of sync.
+func (i *BleveIndexer) putDoc(doc *index.Document) error {
+	b := i.idx.NewBatch()
+	if err := addDoc(b, doc); err != nil {
+		return err
+	}
+	return i.idx.Batch(b)
+}
+
+// addDoc adds the operations for indexing doc and storing a copy of it to b.
+func addDoc(b *bleve.Batch, doc *index.Document) error {
+	v, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Index(doc.LinkID.String(), bleveutil.MakeDoc(doc)); err != nil {
+		return err
+	}
+	b.SetInternal(docKey(doc.LinkID), v)
+	return nil
+}
+
+func docKey(linkID uuid.UUID) []byte {
+	return append(append([]byte(nil), docKeyPrefix...), linkID[:]...)
+}
+
+func copyDoc(d *index.Document) *index.Document {
+	dcopy := new(index.Document)
+	*dcopy = *d
+	return dcopy
+}
@@ -0,0 +1,73 @@
+package bleveindex
+
+import (
+	"Search_Engine/textindexer/index"
+	"context"
+	"github.com/google/uuid"
+	gc "gopkg.in/check.v1"
+	"path/filepath"
+	"testing"
+)
+
+var _ = gc.Suite(new(BleveIndexerTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type BleveIndexerTestSuite struct {
+	index.SuiteBase
+	path string
+	idx  *BleveIndexer
+}
+
+func (s *BleveIndexerTestSuite) SetUpTest(c *gc.C) {
+	s.path = filepath.Join(c.MkDir(), "index")
+	idx, err := NewBleveIndexer(s.path)
+	c.Assert(err, gc.IsNil)
+	s.SetIndexer(idx)
+	s.idx = idx
+}
+
+func (s *BleveIndexerTestSuite) TearDownTest(c *gc.C) {
+	c.Assert(s.idx.Close(), gc.IsNil)
+}
+
+func (s *BleveIndexerTestSuite) TestReopen(c *gc.C) {
+	doc := &index.Document{
+		LinkID:     uuid.New(),
+		URL:        "http://example.com",
+		Title:      "Illustrious examples",
+		Content:    "Ovidius poeta in terra pontica",
+		AnchorText: "examples",
+	}
+	c.Assert(s.idx.Index(context.Background(), doc), gc.IsNil)
+	c.Assert(s.idx.UpdateScore(context.Background(), doc.LinkID, 0.5), gc.IsNil)
+	deleted := &index.Document{LinkID: uuid.New(), Content: "Ovidius poeta"}
+	c.Assert(s.idx.Index(context.Background(), deleted), gc.IsNil)
+	c.Assert(s.idx.Delete(context.Background(), deleted.LinkID), gc.IsNil)
+
+	c.Assert(s.idx.Close(), gc.IsNil)
+	idx, err := NewBleveIndexer(s.path)
+	c.Assert(err, gc.IsNil)
+	s.idx = idx
+
+	got, err := idx.FindByID(context.Background(), doc.LinkID)
+	c.Assert(err, gc.IsNil)
+	c.Assert(got.URL, gc.Equals, doc.URL)
+	c.Assert(got.Title, gc.Equals, doc.Title)
+	c.Assert(got.Content, gc.Equals, doc.Content)
+	c.Assert(got.AnchorText, gc.Equals, doc.AnchorText)
+	c.Assert(got.IndexedAt.Equal(doc.IndexedAt), gc.Equals, true)
+	c.Assert(got.PageRank, gc.Equals, 0.5)
+
+	completions, err := idx.Complete(context.Background(), "illus", 5)
+	c.Assert(err, gc.IsNil)
+	c.Assert(completions, gc.DeepEquals, []string{doc.Title})
+
+	it, err := idx.Search(context.Background(), index.Query{Expression: "poeta"})
+	c.Assert(err, gc.IsNil)
+	c.Assert(it.Next(), gc.Equals, true)
+	c.Assert(it.Document().LinkID, gc.Equals, doc.LinkID)
+	c.Assert(it.Next(), gc.Equals, false)
+	c.Assert(it.Error(), gc.IsNil)
+	c.Assert(it.Close(), gc.IsNil)
+}
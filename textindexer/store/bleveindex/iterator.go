@@ -0,0 +1,97 @@
+package bleveindex
+
+import (
+	"Search_Engine/textindexer/index"
+	"context"
+	"github.com/blevesearch/bleve"
+	"github.com/google/uuid"
+	"golang.org/x/xerrors"
+)
+
+// bleveIterator implements index.Iterator.
+type bleveIterator struct {
+	ctx       context.Context
+	idx       *BleveIndexer
+	searchReq *bleve.SearchRequest
+
+	cumIdx uint64
+	rsIdx  int
+	rs     *bleve.SearchResult
+	facets *index.Facets
+
+	latchedDoc *index.Document
+	lastErr    error
+}
+
+// Close the iterator and release any allocated resources.
+func (it *bleveIterator) Close() error {
+	it.idx = nil
+	it.searchReq = nil
+	if it.rs != nil {
+		it.cumIdx = it.rs.Total
+	}
+	return nil
+}
+
+// Next loads the next document matching the search query.
+// It returns false if no more documents are available.
+func (it *bleveIterator) Next() bool {
+	for {
+		if it.lastErr != nil || it.rs == nil || it.cumIdx >= it.rs.Total {
+			return false
+		}
+
+		// Do we need to fetch the next batch?
+		if it.rsIdx >= it.rs.Hits.Len() {
+			it.searchReq.From += it.searchReq.Size
+			if it.rs, it.lastErr = it.idx.idx.SearchInContext(it.ctx, it.searchReq); it.lastErr != nil {
+				return false
+			} else if it.rs.Hits.Len() == 0 {
+				return false
+			}
+
+			it.rsIdx = 0
+		}
+
+		nextID, err := uuid.Parse(it.rs.Hits[it.rsIdx].ID)
+		if err != nil {
+			it.lastErr = err
+			return false
+		}
+		it.cumIdx++
+		it.rsIdx++
+
+		// Skip documents that were deleted after the search was executed.
+		it.latchedDoc, err = it.idx.findByID(nextID)
+		if xerrors.Is(err, index.ErrNotFound) {
+			continue
+		} else if err != nil {
+			it.lastErr = err
+			return false
+		}
+		return true
+	}
+}
+
+// Error returns the last error encountered by the iterator.
+func (it *bleveIterator) Error() error {
+	return it.lastErr
+}
+
+// Document returns the current document from the result set.
+func (it *bleveIterator) Document() *index.Document {
+	return it.latchedDoc
+}
+
+// TotalCount returns the approximate number of search results.
+func (it *bleveIterator) TotalCount() uint64 {
+	if it.rs == nil {
+		return 0
+	}
+	return it.rs.Total
+}
+
+// Facets returns the facet counts for the search results.
+func (it *bleveIterator) Facets() *index.Facets {
+	return it.facets
+}
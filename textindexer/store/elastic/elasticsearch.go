@@ -9,7 +9,10 @@ import (
 	"github.com/elastic/go-elasticsearch"
 	"github.com/elastic/go-elasticsearch/esapi"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/xerrors"
+	"io/ioutil"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -20,14 +23,20 @@ const indexName = "textindexer"
 // The size of each page of results that is cached locally by the iterator.
 const batchSize = 10
 
+// The maximum number of link IDs to look up with a single terms query.
+const maxTermsPerQuery = 1000
+
 var esMappings = `
 {
   "mappings" : {
     "properties": {
       "LinkID": {"type": "keyword"},
       "URL": {"type": "keyword"},
+      "Site": {"type": "keyword"},
       "Content": {"type": "text"},
       "Title": {"type": "text"},
+      "TitleSuggest": {"type": "completion"},
+      "AnchorText": {"type": "text"},
       "IndexedAt": {"type": "date"},
       "PageRank": {"type": "double"}
     }
@@ -35,7 +44,33 @@ var esMappings = `
 }`
 
 type esSearchRes struct {
-	Hits esSearchResHits `json:"hits"`
+	Hits         esSearchResHits           `json:"hits"`
+	Aggregations esAggregations            `json:"aggregations"`
+	Suggest      map[string][]esSuggestion `json:"suggest"`
+}
+
+type esSuggestion struct {
+	Options []esSuggestOption `json:"options"`
+}
+
+type esSuggestOption struct {
+	Text  string  `json:"text"`
+	Score float64 `json:"score"`
+	Freq  uint64  `json:"freq"`
+}
+
+type esAggregations struct {
+	Hosts     esBucketAgg `json:"hosts"`
+	IndexedAt esBucketAgg `json:"indexed_at"`
+}
+
+type esBucketAgg struct {
+	Buckets []esBucket `json:"buckets"`
+}
+
+type esBucket struct {
+	Key      string `json:"key"`
+	DocCount uint64 `json:"doc_count"`
 }
 
 type esSearchResHits struct {
@@ -52,18 +87,41 @@ type esHitWrapper struct {
 }
 
 type esDoc struct {
-	LinkID    string    `json:"LinkID"`
-	URL       string    `json:"URL"`
-	Title     string    `json:"Title"`
-	Content   string    `json:"Content"`
-	IndexedAt time.Time `json:"IndexedAt"`
-	PageRank  float64   `json:"PageRank,omitempty"`
+	LinkID     string    `json:"LinkID"`
+	URL        string    `json:"URL"`
+	Site       string    `json:"Site"`
+	Title      string    `json:"Title"`
+	Content    string    `json:"Content"`
+	AnchorText string    `json:"AnchorText,omitempty"`
+	IndexedAt  time.Time `json:"IndexedAt"`
+	PageRank   float64   `json:"PageRank,omitempty"`
+
+	// TitleSuggest is a copy of Title that feeds the title completion
+	// suggester. It is nil for documents without a title as completion
+	// fields cannot be empty. Documents indexed before the field was
+	// introduced lack it until they are reindexed.
+	TitleSuggest *string `json:"TitleSuggest"`
 }
 
 type esUpdateRes struct {
 	Result string `json:"result"`
 }
 
+type esBulkRes struct {
+	Errors bool                    `json:"errors"`
+	Items  []map[string]esBulkItem `json:"items"`
+}
+
+type esBulkItem struct {
+	Status int      `json:"status"`
+	Error  *esError `json:"error"`
+}
+
+type esDeleteByQueryRes struct {
+	Deleted  int               `json:"deleted"`
+	Failures []json.RawMessage `json:"failures"`
+}
+
 type esErrorRes struct {
 	Error esError `json:"error"`
 }
@@ -71,25 +129,65 @@ type esErrorRes struct {
 type esError struct {
 	Type   string `json:"type"`
 	Reason string `json:"reason"`
+
+	// RootCause and CausedBy hold the underlying errors of errors that
+	// wrap them, e.g. search_phase_execution_exception.
+	RootCause []esError `json:"root_cause"`
+	CausedBy  *esError  `json:"caused_by"`
 }
 
 func (e esError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Type, e.Reason)
 }
 
+// hasCause returns true if match returns true for e or any of its underlying
+// errors.
+func (e esError) hasCause(match func(esError) bool) bool {
+	if match(e) {
+		return true
+	}
+	for _, cause := range e.RootCause {
+		if cause.hasCause(match) {
+			return true
+		}
+	}
+	return e.CausedBy != nil && e.CausedBy.hasCause(match)
+}
+
+// isFacetMappingError returns true if err reports that the facet
+// aggregations cannot be computed because the Site or IndexedAt fields are
+// not mapped as expected, which is the case for indices that were created
+// before the fields were added to esMappings.
+func isFacetMappingError(err error) bool {
+	var esErr esError
+	if !xerrors.As(err, &esErr) {
+		return false
+	}
+	return esErr.hasCause(func(e esError) bool {
+		return e.Type == "illegal_argument_exception" &&
+			(strings.Contains(e.Reason, "[Site]") || strings.Contains(e.Reason, "[IndexedAt]"))
+	})
+}
+
 // Compile-time check to ensure ElasticSearchIndexer implements Indexer.
 var _ index.Indexer = (*ElasticSearchIndexer)(nil)
 
 // ElasticSearchIndexer is an Indexer implementation that uses an elastic search
 // instance to catalogue and search documents.
 type ElasticSearchIndexer struct {
-	es         *elasticsearch.Client
-	refreshOpt func(*esapi.UpdateRequest)
+	es                      *elasticsearch.Client
+	refreshOpt              func(*esapi.UpdateRequest)
+	bulkRefreshOpt          func(*esapi.BulkRequest)
+	deleteRefreshOpt        func(*esapi.DeleteRequest)
+	deleteByQueryRefreshOpt func(*esapi.DeleteByQueryRequest)
+	logger                  *logrus.Entry
 }
 
 // NewElasticSearchIndexer creates a text indexer that uses an in-memindex
-// bleve instance for indexing documents.
-func NewElasticSearchIndexer(esNodes []string, syncUpdates bool) (*ElasticSearchIndexer, error) {
+// bleve instance for indexing documents. Problems that do not cause requests
+// to fail are reported to logger; if it is nil, an output-discarding logger
+// will be used instead.
+func NewElasticSearchIndexer(esNodes []string, syncUpdates bool, logger *logrus.Entry) (*ElasticSearchIndexer, error) {
 	cfg := elasticsearch.Config{
 		Addresses: esNodes,
 	}
@@ -102,20 +200,28 @@ func NewElasticSearchIndexer(esNodes []string, syncUpdates bool) (*ElasticSearch
 		return nil, err
 	}
 
-	refreshOpt := es.Update.WithRefresh("false")
+	if logger == nil {
+		logger = logrus.NewEntry(&logrus.Logger{Out: ioutil.Discard})
+	}
+
+	refresh := "false"
 	if syncUpdates {
-		refreshOpt = es.Update.WithRefresh("true")
+		refresh = "true"
 	}
 
 	return &ElasticSearchIndexer{
-		es:         es,
-		refreshOpt: refreshOpt,
+		es:                      es,
+		refreshOpt:              es.Update.WithRefresh(refresh),
+		bulkRefreshOpt:          es.Bulk.WithRefresh(refresh),
+		deleteRefreshOpt:        es.Delete.WithRefresh(refresh),
+		deleteByQueryRefreshOpt: es.DeleteByQuery.WithRefresh(syncUpdates),
+		logger:                  logger,
 	}, nil
 }
 
 // Index inserts a new document to the index or updates the index entry
 // for and existing document.
-func (i *ElasticSearchIndexer) Index(doc *index.Document) error {
+func (i *ElasticSearchIndexer) Index(ctx context.Context, doc *index.Document) error {
 	if doc.LinkID == uuid.Nil {
 		return xerrors.Errorf("index: %w", index.ErrMissingLinkID)
 	}
@@ -131,7 +237,7 @@ func (i *ElasticSearchIndexer) Index(doc *index.Document) error {
 		return xerrors.Errorf("index: %w", err)
 	}
 
-	res, err := i.es.Update(indexName, esDoc.LinkID, &buf, i.refreshOpt)
+	res, err := i.es.Update(indexName, esDoc.LinkID, &buf, i.refreshOpt, i.es.Update.WithContext(ctx))
 	if err != nil {
 		return xerrors.Errorf("index: %w", err)
 	}
@@ -144,8 +250,67 @@ func (i *ElasticSearchIndexer) Index(doc *index.Document) error {
 	return nil
 }
 
+// IndexBatch inserts or updates a batch of documents using a single bulk
+// request.
+func (i *ElasticSearchIndexer) IndexBatch(ctx context.Context, docs []*index.Document) error {
+	var (
+		buf      bytes.Buffer
+		enc      = json.NewEncoder(&buf)
+		batchErr = &index.BatchError{Errors: make(map[int]error)}
+
+		// The positions of the documents included in the request; the
+		// items in the response are listed in the same order.
+		positions = make([]int, 0, len(docs))
+	)
+	for pos, doc := range docs {
+		if doc.LinkID == uuid.Nil {
+			batchErr.Errors[pos] = index.ErrMissingLinkID
+			continue
+		}
+
+		esDoc := makeEsDoc(doc)
+		action := map[string]interface{}{
+			"update": map[string]interface{}{"_index": indexName, "_id": esDoc.LinkID},
+		}
+		update := map[string]interface{}{
+			"doc":           esDoc,
+			"doc_as_upsert": true,
+		}
+		if err := enc.Encode(action); err != nil {
+			return xerrors.Errorf("index batch: %w", err)
+		} else if err := enc.Encode(update); err != nil {
+			return xerrors.Errorf("index batch: %w", err)
+		}
+		positions = append(positions, pos)
+	}
+
+	if len(positions) != 0 {
+		res, err := i.es.Bulk(&buf, i.bulkRefreshOpt, i.es.Bulk.WithContext(ctx))
+		if err != nil {
+			return xerrors.Errorf("index batch: %w", err)
+		}
+
+		var bulkRes esBulkRes
+		if err = unmarshalResponse(res, &bulkRes); err != nil {
+			return xerrors.Errorf("index batch: %w", err)
+		} else if len(bulkRes.Items) != len(positions) {
+			return xerrors.Errorf("index batch: expected %d items in bulk response; got %d", len(positions), len(bulkRes.Items))
+		}
+		for itemIndex, item := range bulkRes.Items {
+			if res := item["update"]; res.Error != nil {
+				batchErr.Errors[positions[itemIndex]] = *res.Error
+			}
+		}
+	}
+
+	if len(batchErr.Errors) != 0 {
+		return xerrors.Errorf("index batch: %w", batchErr)
+	}
+	return nil
+}
+
 // FindByID looks up a document by its link ID.
-func (i *ElasticSearchIndexer) FindByID(linkID uuid.UUID) (*index.Document, error) {
+func (i *ElasticSearchIndexer) FindByID(ctx context.Context, linkID uuid.UUID) (*index.Document, error) {
 	var buf bytes.Buffer
 	query := map[string]interface{}{
 		"query": map[string]interface{}{
@@ -160,7 +325,7 @@ func (i *ElasticSearchIndexer) FindByID(linkID uuid.UUID) (*index.Document, erro
 		return nil, xerrors.Errorf("find by ID: %w", err)
 	}
 
-	searchRes, err := runSearch(i.es, query)
+	searchRes, err := runSearch(ctx, i.es, query)
 	if err != nil {
 		return nil, xerrors.Errorf("find by ID: %w", err)
 	}
@@ -171,27 +336,49 @@ func (i *ElasticSearchIndexer) FindByID(linkID uuid.UUID) (*index.Document, erro
 	return mapEsDoc(&searchRes.Hits.HitList[0].DocSource), nil
 }
 
-// Search the index for a particular query and return back a result
-// iterator.
-func (i *ElasticSearchIndexer) Search(q index.Query) (index.Iterator, error) {
-	var qtype string
-	switch q.Type {
-	case index.QueryTypePhrase:
-		qtype = "phrase"
-	default:
-		qtype = "best_fields"
+// FindByIDs looks up the documents with the specified link IDs using a terms
+// query. Large sets of link IDs are split into multiple queries.
+func (i *ElasticSearchIndexer) FindByIDs(ctx context.Context, linkIDs []uuid.UUID) ([]*index.Document, error) {
+	docs := make([]*index.Document, 0, len(linkIDs))
+	for len(linkIDs) != 0 {
+		chunk := linkIDs
+		if len(chunk) > maxTermsPerQuery {
+			chunk = chunk[:maxTermsPerQuery]
+		}
+		linkIDs = linkIDs[len(chunk):]
+
+		ids := make([]string, len(chunk))
+		for j, linkID := range chunk {
+			ids[j] = linkID.String()
+		}
+		query := map[string]interface{}{
+			"query": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"LinkID": ids,
+				},
+			},
+			"from": 0,
+			"size": len(ids),
+		}
+
+		searchRes, err := runSearch(ctx, i.es, query)
+		if err != nil {
+			return nil, xerrors.Errorf("find by IDs: %w", err)
+		}
+		for j := range searchRes.Hits.HitList {
+			docs = append(docs, mapEsDoc(&searchRes.Hits.HitList[j].DocSource))
+		}
 	}
+	return docs, nil
+}
 
+// Search the index for a particular query and return back a result
+// iterator.
+func (i *ElasticSearchIndexer) Search(ctx context.Context, q index.Query) (index.Iterator, error) {
 	query := map[string]interface{}{
 		"query": map[string]interface{}{
 			"function_score": map[string]interface{}{
-				"query": map[string]interface{}{
-					"multi_match": map[string]interface{}{
-						"type":   qtype,
-						"query":  q.Expression,
-						"fields": []string{"Title", "Content"},
-					},
-				},
+				"query": esQuery(q),
 				"script_score": map[string]interface{}{
 					"script": map[string]interface{}{
 						"source": "_score + doc['PageRank'].value",
@@ -203,18 +390,111 @@ func (i *ElasticSearchIndexer) Search(q index.Query) (index.Iterator, error) {
 		"size": batchSize,
 	}
 
-	searchRes, err := runSearch(i.es, query)
+	var dateRanges []index.DateFacet
+	if q.Facets {
+		dateRanges = index.DateFacetRanges(time.Now())
+		query["aggs"] = facetAggregations(dateRanges)
+	}
+
+	searchRes, err := runSearch(ctx, i.es, query)
+	if err != nil && q.Facets && isFacetMappingError(err) {
+		// Facets are optional; if ES cannot compute them because the
+		// index predates the Site or IndexedAt mappings, run the query
+		// without them so that searches keep working.
+		i.logger.WithField("err", err).Warnf("search: computing facets failed; the %q index must be recreated and reindexed", indexName)
+		delete(query, "aggs")
+		q.Facets = false
+		searchRes, err = runSearch(ctx, i.es, query)
+	}
 	if err != nil {
 		return nil, xerrors.Errorf("search: %w", err)
 	}
 
-	return &esIterator{es: i.es, searchReq: query, rs: searchRes, cumIdx: q.Offset}, nil
+	it := &esIterator{ctx: ctx, es: i.es, searchReq: query, rs: searchRes, cumIdx: q.Offset}
+	if q.Facets {
+		// The facets only need to be computed once.
+		it.facets = mapEsFacets(&searchRes.Aggregations, dateRanges)
+		delete(query, "aggs")
+	}
+	return it, nil
+}
+
+// Suggest returns up to n indexed terms that are spelled similarly to term.
+func (i *ElasticSearchIndexer) Suggest(ctx context.Context, term string, n int) ([]string, error) {
+	maxEdits := index.MaxSuggestionEdits(term)
+	if maxEdits == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	// The query checks whether the term is already indexed, in which case
+	// the suggestions are discarded.
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  term,
+				"fields": suggestFields,
+			},
+		},
+		"size":    0,
+		"suggest": suggestRequest(term, n, maxEdits),
+	}
+
+	searchRes, err := runSearch(ctx, i.es, query)
+	if err != nil {
+		return nil, xerrors.Errorf("suggest: %w", err)
+	} else if searchRes.Hits.Total.Count != 0 {
+		return nil, nil
+	}
+	return mapEsSuggestions(searchRes.Suggest, n), nil
+}
+
+// Complete returns up to n distinct titles of indexed documents that start
+// with prefix.
+//
+// Completions are served from the TitleSuggest field, which ensureIndex adds
+// to existing indices. Documents indexed before that only become available
+// once they are reindexed, either when the crawler re-indexes their links or
+// by copying Title into TitleSuggest with an _update_by_query request.
+// Indices in which ES has already mapped TitleSuggest as text must be
+// recreated and reindexed as the mapping of a field cannot be changed.
+func (i *ElasticSearchIndexer) Complete(ctx context.Context, prefix string, n int) ([]string, error) {
+	prefix = strings.TrimLeft(prefix, " \t")
+	if prefix == "" || n <= 0 {
+		return nil, nil
+	}
+
+	query := map[string]interface{}{
+		"_source": false,
+		"suggest": map[string]interface{}{
+			"titles": map[string]interface{}{
+				"prefix": prefix,
+				"completion": map[string]interface{}{
+					"field":           "TitleSuggest",
+					"size":            n,
+					"skip_duplicates": true,
+				},
+			},
+		},
+	}
+
+	searchRes, err := runSearch(ctx, i.es, query)
+	if err != nil {
+		return nil, xerrors.Errorf("complete: %w", err)
+	}
+
+	var completions []string
+	for _, suggestion := range searchRes.Suggest["titles"] {
+		for _, opt := range suggestion.Options {
+			completions = append(completions, opt.Text)
+		}
+	}
+	return completions, nil
 }
 
 // UpdateScore updates the PageRank score for a document with the
 // specified link ID. If no such document exists, a placeholder
 // document with the provided score will be created.
-func (i *ElasticSearchIndexer) UpdateScore(linkID uuid.UUID, score float64) error {
+func (i *ElasticSearchIndexer) UpdateScore(ctx context.Context, linkID uuid.UUID, score float64) error {
 	var buf bytes.Buffer
 	update := map[string]interface{}{
 		"doc": map[string]interface{}{
@@ -227,7 +507,7 @@ func (i *ElasticSearchIndexer) UpdateScore(linkID uuid.UUID, score float64) erro
 		return xerrors.Errorf("update score: %w", err)
 	}
 
-	res, err := i.es.Update(indexName, linkID.String(), &buf, i.refreshOpt)
+	res, err := i.es.Update(indexName, linkID.String(), &buf, i.refreshOpt, i.es.Update.WithContext(ctx))
 	if err != nil {
 		return xerrors.Errorf("update score: %w", err)
 	}
@@ -240,6 +520,104 @@ func (i *ElasticSearchIndexer) UpdateScore(linkID uuid.UUID, score float64) erro
 	return nil
 }
 
+// UpdateAnchorText replaces the anchor text of the document with the
+// specified link ID. Placeholder documents created by UpdateScore are left
+// untouched as they would otherwise show up in search results.
+func (i *ElasticSearchIndexer) UpdateAnchorText(ctx context.Context, linkID uuid.UUID, anchorText string) error {
+	var buf bytes.Buffer
+	update := map[string]interface{}{
+		"script": map[string]interface{}{
+			"source": "if (ctx._source.IndexedAt == null) { ctx.op = 'none' } else { ctx._source.AnchorText = params.anchorText }",
+			"params": map[string]interface{}{
+				"anchorText": anchorText,
+			},
+		},
+	}
+	if err := json.NewEncoder(&buf).Encode(update); err != nil {
+		return xerrors.Errorf("update anchor text: %w", err)
+	}
+
+	res, err := i.es.Update(indexName, linkID.String(), &buf, i.refreshOpt, i.es.Update.WithContext(ctx))
+	if err != nil {
+		return xerrors.Errorf("update anchor text: %w", err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		_ = res.Body.Close()
+		return xerrors.Errorf("update anchor text: %w", index.ErrNotFound)
+	}
+
+	var updateRes esUpdateRes
+	if err = unmarshalResponse(res, &updateRes); err != nil {
+		return xerrors.Errorf("update anchor text: %w", err)
+	} else if updateRes.Result == "noop" {
+		return xerrors.Errorf("update anchor text: %w", index.ErrNotFound)
+	}
+	return nil
+}
+
+// Delete removes the document with the specified link ID from the index.
+func (i *ElasticSearchIndexer) Delete(ctx context.Context, linkID uuid.UUID) error {
+	res, err := i.es.Delete(indexName, linkID.String(), i.deleteRefreshOpt, i.es.Delete.WithContext(ctx))
+	if err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		_ = res.Body.Close()
+		return xerrors.Errorf("delete: %w", index.ErrNotFound)
+	}
+
+	var deleteRes esUpdateRes
+	if err = unmarshalResponse(res, &deleteRes); err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStale removes the documents that were last indexed before the
+// specified time.
+func (i *ElasticSearchIndexer) DeleteStale(ctx context.Context, indexedBefore time.Time) (int, error) {
+	// The placeholder documents created by UpdateScore do not have an
+	// IndexedAt field and are therefore never matched by the query.
+	var buf bytes.Buffer
+	query := map[string]interface{}{
+		"query": map[string]interface{}{
+			"range": map[string]interface{}{
+				"IndexedAt": map[string]interface{}{"lt": indexedBefore.UTC()},
+			},
+		},
+	}
+	if err := json.NewEncoder(&buf).Encode(query); err != nil {
+		return 0, xerrors.Errorf("delete stale: %w", err)
+	}
+
+	res, err := i.es.DeleteByQuery(
+		[]string{indexName}, &buf,
+		i.deleteByQueryRefreshOpt,
+		// Documents that are updated while the request is running are
+		// skipped instead of aborting the request.
+		i.es.DeleteByQuery.WithConflicts("proceed"),
+		i.es.DeleteByQuery.WithContext(ctx),
+	)
+	if err != nil {
+		return 0, xerrors.Errorf("delete stale: %w", err)
+	}
+
+	var deleteRes esDeleteByQueryRes
+	if err = unmarshalResponse(res, &deleteRes); err != nil {
+		return 0, xerrors.Errorf("delete stale: %w", err)
+	} else if len(deleteRes.Failures) != 0 {
+		return deleteRes.Deleted, xerrors.Errorf("delete stale: %d documents could not be deleted", len(deleteRes.Failures))
+	}
+	return deleteRes.Deleted, nil
+}
+
+// ensureIndex creates the index if it does not exist. The mappings of
+// existing indices are updated so that fields added to esMappings after the
+// index was created get their expected types. Updating the mapping of a field
+// that ES has already mapped dynamically with a different type is not
+// possible; such indices have to be recreated and their documents reindexed.
 func ensureIndex(es *elasticsearch.Client) error {
 	mappingsReader := strings.NewReader(esMappings)
 	res, err := es.Indices.Create(indexName, es.Indices.Create.WithBody(mappingsReader))
@@ -248,7 +626,7 @@ func ensureIndex(es *elasticsearch.Client) error {
 	} else if res.IsError() {
 		err := unmarshalError(res)
 		if esErr, valid := err.(esError); valid && esErr.Type == "resource_already_exists_exception" {
-			return nil
+			return updateMappings(es)
 		}
 		return xerrors.Errorf("cannot create ES index: %w", err)
 	}
@@ -256,7 +634,33 @@ func ensureIndex(es *elasticsearch.Client) error {
 	return nil
 }
 
-func runSearch(es *elasticsearch.Client, searchQuery map[string]interface{}) (*esSearchRes, error) {
+// updateMappings applies the field mappings in esMappings to the existing
+// index.
+func updateMappings(es *elasticsearch.Client) error {
+	var mappings struct {
+		Mappings json.RawMessage `json:"mappings"`
+	}
+	if err := json.Unmarshal([]byte(esMappings), &mappings); err != nil {
+		return xerrors.Errorf("cannot update ES index mappings: %w", err)
+	}
+
+	res, err := es.Indices.PutMapping(
+		bytes.NewReader(mappings.Mappings),
+		es.Indices.PutMapping.WithIndex(indexName),
+	)
+	if err != nil {
+		return xerrors.Errorf("cannot update ES index mappings: %w", err)
+	} else if res.IsError() {
+		err := unmarshalError(res)
+		if esErr, valid := err.(esError); valid && esErr.Type == "illegal_argument_exception" {
+			return xerrors.Errorf("cannot update ES index mappings; the %q index must be recreated and reindexed: %w", indexName, err)
+		}
+		return xerrors.Errorf("cannot update ES index mappings: %w", err)
+	}
+	return res.Body.Close()
+}
+
+func runSearch(ctx context.Context, es *elasticsearch.Client, searchQuery map[string]interface{}) (*esSearchRes, error) {
 	var buf bytes.Buffer
 	if err := json.NewEncoder(&buf).Encode(searchQuery); err != nil {
 		return nil, xerrors.Errorf("find by ID: %w", err)
@@ -264,7 +668,7 @@ func runSearch(es *elasticsearch.Client, searchQuery map[string]interface{}) (*e
 
 	// Perform the search request.
 	res, err := es.Search(
-		es.Search.WithContext(context.Background()),
+		es.Search.WithContext(ctx),
 		es.Search.WithIndex(indexName),
 		es.Search.WithBody(&buf),
 	)
@@ -301,23 +705,33 @@ func unmarshalResponse(res *esapi.Response, to interface{}) error {
 
 func mapEsDoc(d *esDoc) *index.Document {
 	return &index.Document{
-		LinkID:    uuid.MustParse(d.LinkID),
-		URL:       d.URL,
-		Title:     d.Title,
-		Content:   d.Content,
-		IndexedAt: d.IndexedAt.UTC(),
-		PageRank:  d.PageRank,
+		LinkID:     uuid.MustParse(d.LinkID),
+		URL:        d.URL,
+		Title:      d.Title,
+		Content:    d.Content,
+		AnchorText: d.AnchorText,
+		IndexedAt:  d.IndexedAt.UTC(),
+		PageRank:   d.PageRank,
 	}
 }
 
 func makeEsDoc(d *index.Document) esDoc {
+	var titleSuggest *string
+	if title := d.Title; title != "" {
+		titleSuggest = &title
+	}
+
 	// Note: we intentionally skip PageRank as we don't want updates to
 	// overwrite existing PageRank values.
 	return esDoc{
-		LinkID:    d.LinkID.String(),
-		URL:       d.URL,
-		Title:     d.Title,
-		Content:   d.Content,
-		IndexedAt: d.IndexedAt.UTC(),
+		LinkID:     d.LinkID.String(),
+		URL:        d.URL,
+		Site:       siteForURL(d.URL),
+		Title:      d.Title,
+		Content:    d.Content,
+		AnchorText: d.AnchorText,
+		IndexedAt:  d.IndexedAt.UTC(),
+
+		TitleSuggest: titleSuggest,
 	}
 }
@@ -0,0 +1,52 @@
+package elastic
+
+import (
+	"encoding/json"
+	"golang.org/x/xerrors"
+	gc "gopkg.in/check.v1"
+	"testing"
+)
+
+var _ = gc.Suite(new(ElasticSearchTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type ElasticSearchTestSuite struct{}
+
+func (s *ElasticSearchTestSuite) TestIsFacetMappingError(c *gc.C) {
+	specs := []struct {
+		descr string
+		res   string
+		exp   bool
+	}{
+		{
+			descr: "site field mapped as text",
+			res: `{"error":{"type":"search_phase_execution_exception","reason":"all shards failed",
+"root_cause":[{"type":"illegal_argument_exception","reason":"Text fields are not optimised for operations that require per-document field data like aggregations and sorting, so these operations are disabled by default. Please use a keyword field instead. Alternatively, set fielddata=true on [Site] in order to load field data by uninverting the inverted index. Note that this can use significant memory."}]}}`,
+			exp: true,
+		},
+		{
+			descr: "indexed at field in a nested cause",
+			res: `{"error":{"type":"search_phase_execution_exception","reason":"all shards failed",
+"caused_by":{"type":"illegal_argument_exception","reason":"Field [IndexedAt] of type [text] is not supported for aggregation [date_range]"}}}`,
+			exp: true,
+		},
+		{
+			descr: "illegal argument about another field",
+			res: `{"error":{"type":"search_phase_execution_exception","reason":"all shards failed",
+"root_cause":[{"type":"illegal_argument_exception","reason":"No mapping found for [PageRank] in order to sort on"}]}}`,
+		},
+		{
+			descr: "unrelated error",
+			res:   `{"error":{"type":"index_not_found_exception","reason":"no such index [textindexer]"}}`,
+		},
+	}
+
+	for specIndex, spec := range specs {
+		c.Logf("[spec %d] %s", specIndex, spec.descr)
+		var res esErrorRes
+		c.Assert(json.Unmarshal([]byte(spec.res), &res), gc.IsNil)
+		c.Assert(isFacetMappingError(xerrors.Errorf("search: %w", res.Error)), gc.Equals, spec.exp)
+	}
+	c.Assert(isFacetMappingError(xerrors.New("connection refused")), gc.Equals, false)
+}
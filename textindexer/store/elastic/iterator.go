@@ -3,17 +3,20 @@ package elastic
 import (
 	"Search_Engine/textindexer/index"
 	"Search_Engine/textindexer/store/memindex"
+	"context"
 	"github.com/elastic/go-elasticsearch"
 )
 
 // esIterator implements index.Iterator.
 type esIterator struct {
+	ctx       context.Context
 	es        *elasticsearch.Client
 	searchReq map[string]interface{}
 
 	cumIdx uint64
 	rsIdx  int
 	rs     *esSearchRes
+	facets *index.Facets
 
 	latchedDoc *index.Document
 	lastErr    error
@@ -37,7 +40,7 @@ func (it *esIterator) Next() bool {
 	// Do we need to fetch the next batch?
 	if it.rsIdx >= len(it.rs.Hits.HitList) {
 		it.searchReq["from"] = it.searchReq["from"].(uint64) + memindex.BatchSize
-		if it.rs, it.lastErr = runSearch(it.es, it.searchReq); it.lastErr != nil {
+		if it.rs, it.lastErr = runSearch(it.ctx, it.es, it.searchReq); it.lastErr != nil {
 			return false
 		}
 
@@ -64,3 +67,8 @@ func (it *esIterator) Document() *index.Document {
 func (it *esIterator) TotalCount() uint64 {
 	return it.rs.Hits.Total.Count
 }
+
+// Facets returns the facet counts for the search results.
+func (it *esIterator) Facets() *index.Facets {
+	return it.facets
+}
@@ -0,0 +1,302 @@
+package elastic
+
+import (
+	"Search_Engine/textindexer/index"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// suggestFields lists the fields whose terms are offered as spelling
+// suggestions.
+var suggestFields = []string{"Title", "Content"}
+
+// wildcardEscaper escapes the characters that have a special meaning in
+// wildcard queries.
+var wildcardEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+
+// esQuery translates q, including its filter, into an elasticsearch query.
+func esQuery(q index.Query) map[string]interface{} {
+	var root map[string]interface{}
+	switch {
+	case q.Root != nil:
+		root = translate(q.Root)
+	case q.Type == index.QueryTypePhrase:
+		root = translate(&index.PhraseNode{Text: q.Expression})
+	default:
+		root = translate(&index.MatchNode{Text: q.Expression})
+	}
+
+	filters := filterQueries(q.Filter)
+	if len(filters) == 0 {
+		return root
+	}
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   []interface{}{root},
+			"filter": filters,
+		},
+	}
+}
+
+// filterQueries returns the queries that documents must match in order to
+// satisfy f.
+func filterQueries(f index.Filter) []interface{} {
+	var filters []interface{}
+	if len(f.Sites) != 0 {
+		sites := make([]interface{}, len(f.Sites))
+		for i, site := range f.Sites {
+			sites[i] = fieldQuery(index.FieldSite, strings.ToLower(site), false)
+		}
+		filters = append(filters, map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               sites,
+				"minimum_should_match": 1,
+			},
+		})
+	}
+	if !f.IndexedFrom.IsZero() || !f.IndexedTo.IsZero() {
+		filters = append(filters, translate(&index.DateRangeNode{From: f.IndexedFrom, To: f.IndexedTo}))
+	}
+	if f.MinPageRank != 0 {
+		filters = append(filters, map[string]interface{}{
+			"range": map[string]interface{}{
+				"PageRank": map[string]interface{}{"gte": f.MinPageRank},
+			},
+		})
+	}
+	return filters
+}
+
+// facetAggregations returns the aggregations for computing the host and
+// IndexedAt facets of the matching documents.
+func facetAggregations(dateRanges []index.DateFacet) map[string]interface{} {
+	ranges := make([]interface{}, len(dateRanges))
+	for i, r := range dateRanges {
+		bounds := map[string]interface{}{"key": r.Name}
+		if !r.From.IsZero() {
+			bounds["from"] = r.From.UTC()
+		}
+		if !r.To.IsZero() {
+			bounds["to"] = r.To.UTC()
+		}
+		ranges[i] = bounds
+	}
+
+	return map[string]interface{}{
+		"hosts": map[string]interface{}{
+			"terms": map[string]interface{}{"field": "Site", "size": index.MaxHostFacets},
+		},
+		"indexed_at": map[string]interface{}{
+			"date_range": map[string]interface{}{"field": "IndexedAt", "ranges": ranges},
+		},
+	}
+}
+
+// mapEsFacets returns the facet counts computed by the aggregations returned
+// by facetAggregations.
+func mapEsFacets(aggs *esAggregations, dateRanges []index.DateFacet) *index.Facets {
+	facets := &index.Facets{IndexedAt: dateRanges}
+	for _, b := range aggs.Hosts.Buckets {
+		// Skip the documents that have no URL.
+		if b.Key == "" {
+			continue
+		}
+		facets.Hosts = append(facets.Hosts, index.HostFacet{Host: b.Key, Count: b.DocCount})
+	}
+	for _, b := range aggs.IndexedAt.Buckets {
+		for i := range facets.IndexedAt {
+			if facets.IndexedAt[i].Name == b.Key {
+				facets.IndexedAt[i].Count = b.DocCount
+			}
+		}
+	}
+	return facets
+}
+
+// suggestRequest returns a term suggester request for each one of the
+// suggestFields.
+func suggestRequest(term string, n, maxEdits int) map[string]interface{} {
+	req := map[string]interface{}{"text": term}
+	for _, field := range suggestFields {
+		req[field] = map[string]interface{}{
+			"term": map[string]interface{}{
+				"field":           field,
+				"size":            n,
+				"suggest_mode":    "always",
+				"max_edits":       maxEdits,
+				"prefix_length":   0,
+				"min_word_length": 1,
+			},
+		}
+	}
+	return req
+}
+
+// mapEsSuggestions merges the options returned by the suggesters created by
+// suggestRequest and returns the n best ones ordered by decreasing score and
+// document frequency.
+func mapEsSuggestions(res map[string][]esSuggestion, n int) []string {
+	merged := make(map[string]*esSuggestOption)
+	for _, suggestions := range res {
+		for _, suggestion := range suggestions {
+			for _, opt := range suggestion.Options {
+				existing, found := merged[opt.Text]
+				if !found {
+					opt := opt
+					merged[opt.Text] = &opt
+					continue
+				}
+				if opt.Score > existing.Score {
+					existing.Score = opt.Score
+				}
+				existing.Freq += opt.Freq
+			}
+		}
+	}
+
+	opts := make([]*esSuggestOption, 0, len(merged))
+	for _, opt := range merged {
+		opts = append(opts, opt)
+	}
+	sort.Slice(opts, func(i, j int) bool {
+		if opts[i].Score != opts[j].Score {
+			return opts[i].Score > opts[j].Score
+		} else if opts[i].Freq != opts[j].Freq {
+			return opts[i].Freq > opts[j].Freq
+		}
+		return opts[i].Text < opts[j].Text
+	})
+	if len(opts) > n {
+		opts = opts[:n]
+	}
+
+	suggestions := make([]string, len(opts))
+	for i, opt := range opts {
+		suggestions[i] = opt.Text
+	}
+	return suggestions
+}
+
+// translate returns the elasticsearch query for a query node.
+func translate(n index.QueryNode) map[string]interface{} {
+	switch n := n.(type) {
+	case *index.MatchNode:
+		return fieldQuery(n.Field, n.Text, false)
+	case *index.PhraseNode:
+		return fieldQuery(n.Field, n.Text, true)
+	case *index.AndNode:
+		// Negated children are collected into the must_not clause of the
+		// same bool query.
+		var must, mustNot []interface{}
+		for _, child := range n.Children {
+			if not, isNot := child.(*index.NotNode); isNot {
+				mustNot = append(mustNot, translate(not.Child))
+			} else {
+				must = append(must, translate(child))
+			}
+		}
+		boolQuery := make(map[string]interface{})
+		if len(must) != 0 {
+			boolQuery["must"] = must
+		}
+		if len(mustNot) != 0 {
+			boolQuery["must_not"] = mustNot
+		}
+		return map[string]interface{}{"bool": boolQuery}
+	case *index.OrNode:
+		should := make([]interface{}, len(n.Children))
+		for i, child := range n.Children {
+			should[i] = translate(child)
+		}
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should":               should,
+				"minimum_should_match": 1,
+			},
+		}
+	case *index.NotNode:
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must_not": []interface{}{translate(n.Child)},
+			},
+		}
+	case *index.DateRangeNode:
+		bounds := make(map[string]interface{})
+		if !n.From.IsZero() {
+			bounds["gte"] = n.From.UTC()
+		}
+		if !n.To.IsZero() {
+			bounds["lt"] = n.To.UTC()
+		}
+		return map[string]interface{}{
+			"range": map[string]interface{}{"IndexedAt": bounds},
+		}
+	default:
+		return map[string]interface{}{"match_none": map[string]interface{}{}}
+	}
+}
+
+// fieldQuery returns a query that matches text against the document fields
+// selected by field.
+func fieldQuery(field index.QueryField, text string, phrase bool) map[string]interface{} {
+	switch field {
+	case index.FieldTitle, index.FieldContent:
+		fieldName := "Title"
+		if field == index.FieldContent {
+			fieldName = "Content"
+		}
+		qtype := "match"
+		if phrase {
+			qtype = "match_phrase"
+		}
+		return map[string]interface{}{
+			qtype: map[string]interface{}{fieldName: text},
+		}
+	case index.FieldURL:
+		return map[string]interface{}{
+			"wildcard": map[string]interface{}{
+				"URL": map[string]interface{}{"value": "*" + wildcardEscaper.Replace(text) + "*"},
+			},
+		}
+	case index.FieldSite:
+		return map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []interface{}{
+					map[string]interface{}{
+						"term": map[string]interface{}{"Site": text},
+					},
+					map[string]interface{}{
+						"wildcard": map[string]interface{}{
+							"Site": map[string]interface{}{"value": "*." + wildcardEscaper.Replace(text)},
+						},
+					},
+				},
+				"minimum_should_match": 1,
+			},
+		}
+	default:
+		qtype := "best_fields"
+		if phrase {
+			qtype = "phrase"
+		}
+		return map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"type":   qtype,
+				"query":  text,
+				"fields": []string{"Title", "Content", fmt.Sprintf("AnchorText^%g", index.AnchorTextBoost)},
+			},
+		}
+	}
+}
+
+// siteForURL returns the lowercased host name of a document URL or an empty
+// string if the URL cannot be parsed.
+func siteForURL(docURL string) string {
+	u, err := url.Parse(docURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
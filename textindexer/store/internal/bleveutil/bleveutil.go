@@ -0,0 +1,273 @@
+// Package bleveutil contains the document mapping and query translation logic
+// that is shared by the bleve-based text indexers.
+package bleveutil
+
+import (
+	"Search_Engine/textindexer/index"
+	"github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/analysis/tokenizer/single"
+	"github.com/blevesearch/bleve/index/scorch"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/search/query"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Doc is the representation of an index.Document that is indexed by bleve.
+type Doc struct {
+	Title      string
+	Content    string
+	AnchorText string
+	PageRank   float64
+
+	// URL and Site hold the document URL and its lowercased host; both
+	// are indexed verbatim.
+	URL       string
+	Site      string
+	IndexedAt time.Time
+}
+
+// MakeDoc returns the bleve representation of d.
+func MakeDoc(d *index.Document) Doc {
+	return Doc{
+		Title:      d.Title,
+		Content:    d.Content,
+		AnchorText: d.AnchorText,
+		PageRank:   d.PageRank,
+		URL:        d.URL,
+		Site:       SiteForURL(d.URL),
+		IndexedAt:  d.IndexedAt,
+	}
+}
+
+// SiteForURL returns the lowercased host name of a document URL or an empty
+// string if the URL cannot be parsed.
+func SiteForURL(docURL string) string {
+	u, err := url.Parse(docURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// lowercaseKeyword is the name of the analyzer that indexes the lowercased
+// value of a field as a single term.
+const lowercaseKeyword = "lowercase_keyword"
+
+// NewIndex creates a bleve index for Doc values at path or an in-memory index
+// if path is empty. The index uses the scorch index type whose term
+// dictionaries support the fuzzy lookups performed by Suggest.
+func NewIndex(path string) (bleve.Index, error) {
+	return bleve.NewUsing(path, NewIndexMapping(), scorch.Name, scorch.Name, nil)
+}
+
+// NewIndexMapping returns the index mapping for indexing Doc values.
+func NewIndexMapping() mapping.IndexMapping {
+	keywordField := bleve.NewTextFieldMapping()
+	keywordField.Analyzer = keyword.Name
+
+	// Besides being indexed as regular text, titles are indexed verbatim
+	// (but lowercased) as TitleKey so they can be looked up by prefix.
+	titleKeyField := bleve.NewTextFieldMapping()
+	titleKeyField.Name = "TitleKey"
+	titleKeyField.Analyzer = lowercaseKeyword
+	titleKeyField.Store = false
+	titleKeyField.IncludeInAll = false
+	titleKeyField.IncludeTermVectors = false
+
+	docMapping := bleve.NewDocumentMapping()
+	docMapping.AddFieldMappingsAt("URL", keywordField)
+	docMapping.AddFieldMappingsAt("Site", keywordField)
+	docMapping.AddFieldMappingsAt("Title", bleve.NewTextFieldMapping(), titleKeyField)
+
+	m := bleve.NewIndexMapping()
+	// The analyzer is always valid so registering it cannot fail.
+	_ = m.AddCustomAnalyzer(lowercaseKeyword, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     single.Name,
+		"token_filters": []string{lowercase.Name},
+	})
+	m.DefaultMapping = docMapping
+	return m
+}
+
+// Query translates q, including its filter, into a bleve query.
+func Query(q index.Query) query.Query {
+	var root query.Query
+	switch {
+	case q.Root != nil:
+		root = translate(q.Root)
+	case q.Type == index.QueryTypePhrase:
+		root = translate(&index.PhraseNode{Text: q.Expression})
+	default:
+		root = translate(&index.MatchNode{Text: q.Expression})
+	}
+
+	filters := filterQueries(q.Filter)
+	if len(filters) == 0 {
+		return root
+	}
+	return bleve.NewConjunctionQuery(append([]query.Query{root}, filters...)...)
+}
+
+// filterQueries returns the queries that documents must match in order to
+// satisfy f.
+func filterQueries(f index.Filter) []query.Query {
+	var filters []query.Query
+	if len(f.Sites) != 0 {
+		sites := make([]query.Query, len(f.Sites))
+		for i, site := range f.Sites {
+			sites[i] = fieldQuery(index.FieldSite, strings.ToLower(site), false)
+		}
+		filters = append(filters, bleve.NewDisjunctionQuery(sites...))
+	}
+	if !f.IndexedFrom.IsZero() || !f.IndexedTo.IsZero() {
+		filters = append(filters, translate(&index.DateRangeNode{From: f.IndexedFrom, To: f.IndexedTo}))
+	}
+	if f.MinPageRank != 0 {
+		minPageRank, inclusive := f.MinPageRank, true
+		nq := bleve.NewNumericRangeInclusiveQuery(&minPageRank, nil, &inclusive, nil)
+		nq.SetField("PageRank")
+		filters = append(filters, nq)
+	}
+	return filters
+}
+
+// AddFacetRequests configures req to compute the host and IndexedAt facets of
+// the matching documents. It returns the IndexedAt ranges that are passed to
+// Facets for collecting the results.
+func AddFacetRequests(req *bleve.SearchRequest, now time.Time) []index.DateFacet {
+	dateRanges := index.DateFacetRanges(now)
+	dateReq := bleve.NewFacetRequest("IndexedAt", len(dateRanges))
+	for _, r := range dateRanges {
+		dateReq.AddDateTimeRange(r.Name, r.From, r.To)
+	}
+
+	req.AddFacet("hosts", bleve.NewFacetRequest("Site", index.MaxHostFacets))
+	req.AddFacet("indexed_at", dateReq)
+	return dateRanges
+}
+
+// Facets returns the facet counts computed for the facet requests added by
+// AddFacetRequests.
+func Facets(rs *bleve.SearchResult, dateRanges []index.DateFacet) *index.Facets {
+	facets := &index.Facets{IndexedAt: dateRanges}
+	if res := rs.Facets["hosts"]; res != nil {
+		for _, tf := range res.Terms {
+			// Skip the placeholder documents that have no URL.
+			if tf.Term == "" {
+				continue
+			}
+			facets.Hosts = append(facets.Hosts, index.HostFacet{Host: tf.Term, Count: uint64(tf.Count)})
+		}
+	}
+	if res := rs.Facets["indexed_at"]; res != nil {
+		// Ranges without any matching documents are omitted from the
+		// results.
+		for _, df := range res.DateRanges {
+			for i := range facets.IndexedAt {
+				if facets.IndexedAt[i].Name == df.Name {
+					facets.IndexedAt[i].Count = uint64(df.Count)
+				}
+			}
+		}
+	}
+	return facets
+}
+
+// translate returns the bleve query for a query node.
+func translate(n index.QueryNode) query.Query {
+	switch n := n.(type) {
+	case *index.MatchNode:
+		return fieldQuery(n.Field, n.Text, false)
+	case *index.PhraseNode:
+		return fieldQuery(n.Field, n.Text, true)
+	case *index.AndNode:
+		// Negated children are collected into a single must-not clause so
+		// that they do not require a match-all query each.
+		var must, mustNot []query.Query
+		for _, child := range n.Children {
+			if not, isNot := child.(*index.NotNode); isNot {
+				mustNot = append(mustNot, translate(not.Child))
+			} else {
+				must = append(must, translate(child))
+			}
+		}
+		if len(mustNot) == 0 {
+			return bleve.NewConjunctionQuery(must...)
+		}
+		if len(must) == 0 {
+			must = append(must, bleve.NewMatchAllQuery())
+		}
+		bq := bleve.NewBooleanQuery()
+		bq.AddMust(must...)
+		bq.AddMustNot(mustNot...)
+		return bq
+	case *index.OrNode:
+		disjuncts := make([]query.Query, len(n.Children))
+		for i, child := range n.Children {
+			disjuncts[i] = translate(child)
+		}
+		return bleve.NewDisjunctionQuery(disjuncts...)
+	case *index.NotNode:
+		bq := bleve.NewBooleanQuery()
+		bq.AddMust(bleve.NewMatchAllQuery())
+		bq.AddMustNot(translate(n.Child))
+		return bq
+	case *index.DateRangeNode:
+		inclusive, exclusive := true, false
+		dq := bleve.NewDateRangeInclusiveQuery(n.From, n.To, &inclusive, &exclusive)
+		dq.SetField("IndexedAt")
+		return dq
+	default:
+		return bleve.NewMatchNoneQuery()
+	}
+}
+
+// fieldQuery returns a query that matches text against the document fields
+// selected by field.
+func fieldQuery(field index.QueryField, text string, phrase bool) query.Query {
+	switch field {
+	case index.FieldTitle:
+		return textQuery("Title", text, phrase, 1)
+	case index.FieldContent:
+		return textQuery("Content", text, phrase, 1)
+	case index.FieldURL:
+		rq := bleve.NewRegexpQuery(".*" + regexp.QuoteMeta(text) + ".*")
+		rq.SetField("URL")
+		return rq
+	case index.FieldSite:
+		tq := bleve.NewTermQuery(text)
+		tq.SetField("Site")
+		sq := bleve.NewRegexpQuery(".*\\." + regexp.QuoteMeta(text))
+		sq.SetField("Site")
+		return bleve.NewDisjunctionQuery(tq, sq)
+	default:
+		return bleve.NewDisjunctionQuery(
+			textQuery("Title", text, phrase, 1),
+			textQuery("Content", text, phrase, 1),
+			textQuery("AnchorText", text, phrase, index.AnchorTextBoost),
+		)
+	}
+}
+
+// textQuery returns a query that matches text against a single analyzed
+// document field and applies the specified boost to its score.
+func textQuery(field, text string, phrase bool, boost float64) query.Query {
+	if phrase {
+		pq := bleve.NewMatchPhraseQuery(text)
+		pq.SetField(field)
+		pq.SetBoost(boost)
+		return pq
+	}
+
+	mq := bleve.NewMatchQuery(text)
+	mq.SetField(field)
+	mq.SetBoost(boost)
+	return mq
+}
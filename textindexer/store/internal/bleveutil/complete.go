@@ -0,0 +1,55 @@
+package bleveutil
+
+import (
+	"context"
+	"github.com/blevesearch/bleve"
+	"strings"
+)
+
+// completeBatchSize is the number of matching documents that are fetched at
+// a time while collecting title completions.
+const completeBatchSize = 100
+
+// Complete returns up to n distinct titles of the documents in idx that start
+// with prefix, ignoring case. Titles of documents with a higher PageRank score
+// are returned first.
+func Complete(ctx context.Context, idx bleve.Index, prefix string, n int) ([]string, error) {
+	prefix = strings.ToLower(strings.TrimLeft(prefix, " \t"))
+	if prefix == "" || n <= 0 {
+		return nil, nil
+	}
+
+	pq := bleve.NewPrefixQuery(prefix)
+	pq.SetField("TitleKey")
+	searchReq := bleve.NewSearchRequestOptions(pq, completeBatchSize, 0, false)
+	searchReq.SortBy([]string{"-PageRank", "_id"})
+	searchReq.Fields = []string{"Title"}
+
+	var (
+		completions []string
+		seen        = make(map[string]bool)
+	)
+	for {
+		rs, err := idx.SearchInContext(ctx, searchReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, hit := range rs.Hits {
+			title, _ := hit.Fields["Title"].(string)
+			key := strings.ToLower(title)
+			if title == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			if completions = append(completions, title); len(completions) == n {
+				return completions, nil
+			}
+		}
+
+		searchReq.From += len(rs.Hits)
+		if len(rs.Hits) == 0 || uint64(searchReq.From) >= rs.Total {
+			return completions, nil
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package bleveutil
+
+import (
+	"Search_Engine/textindexer/index"
+	"github.com/blevesearch/bleve"
+	bleveidx "github.com/blevesearch/bleve/index"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// suggestFields lists the fields whose terms are offered as spelling
+// suggestions.
+var suggestFields = []string{"Title", "Content"}
+
+// Suggest returns up to n terms from the Title and Content dictionaries of
+// idx that are within index.MaxSuggestionEdits(term) edits of term. The
+// suggestions are ordered by increasing edit distance and decreasing document
+// frequency. No suggestions are returned if term is itself indexed.
+func Suggest(idx bleve.Index, term string, n int) ([]string, error) {
+	term = strings.ToLower(term)
+	maxEdits := index.MaxSuggestionEdits(term)
+	if maxEdits == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	advIdx, _, err := idx.Advanced()
+	if err != nil {
+		return nil, err
+	}
+	reader, err := advIdx.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	type candidate struct {
+		term  string
+		edits int
+		count uint64
+	}
+	candidates := make(map[string]*candidate)
+	for _, field := range suggestFields {
+		dict, err := similarTerms(reader, field, term, maxEdits)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			entry, err := dict.Next()
+			if err != nil {
+				_ = dict.Close()
+				return nil, err
+			} else if entry == nil {
+				break
+			}
+
+			edits := editDistance(term, entry.Term, maxEdits)
+			if edits > maxEdits {
+				continue
+			}
+			// Terms of deleted documents may linger in the dictionary so
+			// the number of live documents is looked up separately.
+			count, err := docCount(reader, field, entry.Term)
+			if err != nil {
+				_ = dict.Close()
+				return nil, err
+			} else if count == 0 {
+				continue
+			} else if entry.Term == term {
+				return nil, dict.Close()
+			}
+
+			if cand, exists := candidates[entry.Term]; exists {
+				cand.count += count
+			} else {
+				candidates[entry.Term] = &candidate{term: entry.Term, edits: edits, count: count}
+			}
+		}
+		if err = dict.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := make([]*candidate, 0, len(candidates))
+	for _, cand := range candidates {
+		sorted = append(sorted, cand)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].edits != sorted[j].edits {
+			return sorted[i].edits < sorted[j].edits
+		} else if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].term < sorted[j].term
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+
+	suggestions := make([]string, len(sorted))
+	for i, cand := range sorted {
+		suggestions[i] = cand.term
+	}
+	return suggestions, nil
+}
+
+// similarTerms returns a dictionary iterator over the terms of field that
+// may be within maxEdits edits of term. Indices created by NewIndex use a
+// Levenshtein automaton to only visit the matching terms. Indices of other
+// types cannot do that; to avoid walking their entire dictionary only the
+// terms that share the first letter of term are visited.
+func similarTerms(reader bleveidx.IndexReader, field, term string, maxEdits int) (bleveidx.FieldDict, error) {
+	if fuzzyReader, ok := reader.(bleveidx.IndexReaderFuzzy); ok {
+		return fuzzyReader.FieldDictFuzzy(field, term, maxEdits, "")
+	}
+	_, size := utf8.DecodeRuneInString(term)
+	return reader.FieldDictPrefix(field, []byte(term[:size]))
+}
+
+// docCount returns the number of live documents whose field contains term.
+func docCount(reader bleveidx.IndexReader, field, term string) (uint64, error) {
+	tfr, err := reader.TermFieldReader([]byte(term), field, false, false, false)
+	if err != nil {
+		return 0, err
+	}
+	count := tfr.Count()
+	return count, tfr.Close()
+}
+
+// editDistance returns the Levenshtein distance between a and b or
+// maxEdits+1 if the distance exceeds maxEdits.
+func editDistance(a, b string, maxEdits int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > maxEdits || -diff > maxEdits {
+		return maxEdits + 1
+	}
+
+	prev, cur := make([]int, len(rb)+1), make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > maxEdits {
+			return maxEdits + 1
+		}
+		prev, cur = cur, prev
+	}
+	if prev[len(rb)] > maxEdits {
+		return maxEdits + 1
+	}
+	return prev[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
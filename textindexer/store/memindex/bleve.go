@@ -2,8 +2,9 @@ package memindex
 
 import (
 	"Search_Engine/textindexer/index"
+	"Search_Engine/textindexer/store/internal/bleveutil"
+	"context"
 	"github.com/blevesearch/bleve"
-	"github.com/blevesearch/bleve/search/query"
 	"github.com/google/uuid"
 	"golang.org/x/xerrors"
 	"sync"
@@ -16,12 +17,6 @@ const BatchSize = 10
 // Compile-time check to ensure InMemoryBleveIndexer implements Indexer.
 var _ index.Indexer = (*InMemoryBleveIndexer)(nil)
 
-type bleveDoc struct {
-	Title    string
-	Content  string
-	PageRank float64
-}
-
 // InMemoryBleveIndexer is an Indexer implementation that uses an in-memindex
 // bleve instance to catalogue and search documents.
 type InMemoryBleveIndexer struct {
@@ -34,8 +29,7 @@ type InMemoryBleveIndexer struct {
 // NewInMemoryBleveIndexer creates a text indexer that uses an in-memindex
 // bleve instance for indexing documents.
 func NewInMemoryBleveIndexer() (*InMemoryBleveIndexer, error) {
-	mapping := bleve.NewIndexMapping()
-	idx, err := bleve.NewMemOnly(mapping)
+	idx, err := bleveutil.NewIndex("")
 	if err != nil {
 		return nil, err
 	}
@@ -53,7 +47,7 @@ func (i *InMemoryBleveIndexer) Close() error {
 
 // Index inserts a new document to the index or updates the index entry
 // for and existing document.
-func (i *InMemoryBleveIndexer) Index(doc *index.Document) error {
+func (i *InMemoryBleveIndexer) Index(ctx context.Context, doc *index.Document) error {
 	if doc.LinkID == uuid.Nil {
 		return xerrors.Errorf("index: %w", index.ErrMissingLinkID)
 	}
@@ -63,12 +57,15 @@ func (i *InMemoryBleveIndexer) Index(doc *index.Document) error {
 	key := dcopy.LinkID.String()
 
 	i.mu.Lock()
-	// If updating, preserve existing PageRank score
+	// If updating, preserve existing PageRank score and anchor text
 	if orig, exists := i.docs[key]; exists {
 		dcopy.PageRank = orig.PageRank
+		if dcopy.AnchorText == "" {
+			dcopy.AnchorText = orig.AnchorText
+		}
 	}
 
-	if err := i.idx.Index(key, makeBleveDoc(dcopy)); err != nil {
+	if err := i.idx.Index(key, bleveutil.MakeDoc(dcopy)); err != nil {
 		return xerrors.Errorf("index: %w", err)
 	}
 
@@ -77,11 +74,74 @@ func (i *InMemoryBleveIndexer) Index(doc *index.Document) error {
 	return nil
 }
 
+// IndexBatch inserts or updates a batch of documents using a single bleve
+// batch.
+func (i *InMemoryBleveIndexer) IndexBatch(ctx context.Context, docs []*index.Document) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var (
+		now      = time.Now()
+		b        = i.idx.NewBatch()
+		indexed  = make([]*index.Document, 0, len(docs))
+		batchErr = &index.BatchError{Errors: make(map[int]error)}
+	)
+	for pos, doc := range docs {
+		if doc.LinkID == uuid.Nil {
+			batchErr.Errors[pos] = index.ErrMissingLinkID
+			continue
+		}
+
+		doc.IndexedAt = now
+		dcopy := copyDoc(doc)
+		key := dcopy.LinkID.String()
+
+		// If updating, preserve existing PageRank score and anchor text
+		if orig, exists := i.docs[key]; exists {
+			dcopy.PageRank = orig.PageRank
+			if dcopy.AnchorText == "" {
+				dcopy.AnchorText = orig.AnchorText
+			}
+		}
+		if err := b.Index(key, bleveutil.MakeDoc(dcopy)); err != nil {
+			batchErr.Errors[pos] = err
+			continue
+		}
+		indexed = append(indexed, dcopy)
+	}
+
+	if err := i.idx.Batch(b); err != nil {
+		return xerrors.Errorf("index batch: %w", err)
+	}
+	for _, dcopy := range indexed {
+		i.docs[dcopy.LinkID.String()] = dcopy
+	}
+
+	if len(batchErr.Errors) != 0 {
+		return xerrors.Errorf("index batch: %w", batchErr)
+	}
+	return nil
+}
+
 // FindByID looks up a document by its link ID.
-func (i *InMemoryBleveIndexer) FindByID(linkID uuid.UUID) (*index.Document, error) {
+func (i *InMemoryBleveIndexer) FindByID(ctx context.Context, linkID uuid.UUID) (*index.Document, error) {
 	return i.findByID(linkID.String())
 }
 
+// FindByIDs looks up the documents with the specified link IDs.
+func (i *InMemoryBleveIndexer) FindByIDs(_ context.Context, linkIDs []uuid.UUID) ([]*index.Document, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	docs := make([]*index.Document, 0, len(linkIDs))
+	for _, linkID := range linkIDs {
+		if d, found := i.docs[linkID.String()]; found {
+			docs = append(docs, copyDoc(d))
+		}
+	}
+	return docs, nil
+}
+
 // findByID looks up a document by its link UUID expressed as a string.
 func (i *InMemoryBleveIndexer) findByID(linkID string) (*index.Document, error) {
 	i.mu.RLock()
@@ -96,31 +156,52 @@ func (i *InMemoryBleveIndexer) findByID(linkID string) (*index.Document, error)
 
 // Search the index for a particular query and return back a result
 // iterator.
-func (i *InMemoryBleveIndexer) Search(q index.Query) (index.Iterator, error) {
-	var bq query.Query
-	switch q.Type {
-	case index.QueryTypePhrase:
-		bq = bleve.NewMatchPhraseQuery(q.Expression)
-	default:
-		bq = bleve.NewMatchQuery(q.Expression)
-	}
-
-	searchReq := bleve.NewSearchRequest(bq)
+func (i *InMemoryBleveIndexer) Search(ctx context.Context, q index.Query) (index.Iterator, error) {
+	searchReq := bleve.NewSearchRequest(bleveutil.Query(q))
 	searchReq.SortBy([]string{"-PageRank", "-_score"})
 	searchReq.Size = BatchSize
 	searchReq.From = int(q.Offset)
-	rs, err := i.idx.Search(searchReq)
+	var dateRanges []index.DateFacet
+	if q.Facets {
+		dateRanges = bleveutil.AddFacetRequests(searchReq, time.Now())
+	}
+	rs, err := i.idx.SearchInContext(ctx, searchReq)
 	if err != nil {
 		return nil, xerrors.Errorf("search: %w", err)
 	}
 
-	return &bleveIterator{idx: i, searchReq: searchReq, rs: rs, cumIdx: q.Offset}, nil
+	it := &bleveIterator{ctx: ctx, idx: i, searchReq: searchReq, rs: rs, cumIdx: q.Offset}
+	if q.Facets {
+		// The facets only need to be computed once.
+		it.facets = bleveutil.Facets(rs, dateRanges)
+		searchReq.Facets = nil
+	}
+	return it, nil
+}
+
+// Suggest returns up to n indexed terms that are spelled similarly to term.
+func (i *InMemoryBleveIndexer) Suggest(_ context.Context, term string, n int) ([]string, error) {
+	suggestions, err := bleveutil.Suggest(i.idx, term, n)
+	if err != nil {
+		return nil, xerrors.Errorf("suggest: %w", err)
+	}
+	return suggestions, nil
+}
+
+// Complete returns up to n distinct titles of indexed documents that start
+// with prefix.
+func (i *InMemoryBleveIndexer) Complete(ctx context.Context, prefix string, n int) ([]string, error) {
+	completions, err := bleveutil.Complete(ctx, i.idx, prefix, n)
+	if err != nil {
+		return nil, xerrors.Errorf("complete: %w", err)
+	}
+	return completions, nil
 }
 
 // UpdateScore updates the PageRank score for a document with the specified
 // link ID. If no such document exists, a placeholder document with the
 // provided score will be created.
-func (i *InMemoryBleveIndexer) UpdateScore(linkID uuid.UUID, score float64) error {
+func (i *InMemoryBleveIndexer) UpdateScore(ctx context.Context, linkID uuid.UUID, score float64) error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
 
@@ -132,23 +213,80 @@ func (i *InMemoryBleveIndexer) UpdateScore(linkID uuid.UUID, score float64) erro
 	}
 
 	doc.PageRank = score
-	if err := i.idx.Index(key, makeBleveDoc(doc)); err != nil {
+	if err := i.idx.Index(key, bleveutil.MakeDoc(doc)); err != nil {
 		return xerrors.Errorf("update score: %w", err)
 	}
 
 	return nil
 }
 
+// UpdateAnchorText replaces the anchor text of the document with the
+// specified link ID.
+func (i *InMemoryBleveIndexer) UpdateAnchorText(ctx context.Context, linkID uuid.UUID, anchorText string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	// Placeholder documents created by UpdateScore are not updated as they
+	// would otherwise show up in search results.
+	key := linkID.String()
+	doc, found := i.docs[key]
+	if !found || doc.IndexedAt.IsZero() {
+		return xerrors.Errorf("update anchor text: %w", index.ErrNotFound)
+	}
+
+	dcopy := copyDoc(doc)
+	dcopy.AnchorText = anchorText
+	if err := i.idx.Index(key, bleveutil.MakeDoc(dcopy)); err != nil {
+		return xerrors.Errorf("update anchor text: %w", err)
+	}
+	i.docs[key] = dcopy
+	return nil
+}
+
+// Delete removes the document with the specified link ID from the index.
+func (i *InMemoryBleveIndexer) Delete(ctx context.Context, linkID uuid.UUID) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	key := linkID.String()
+	if _, found := i.docs[key]; !found {
+		return xerrors.Errorf("delete: %w", index.ErrNotFound)
+	}
+
+	if err := i.idx.Delete(key); err != nil {
+		return xerrors.Errorf("delete: %w", err)
+	}
+
+	delete(i.docs, key)
+	return nil
+}
+
+// DeleteStale removes the documents that were last indexed before the
+// specified time.
+func (i *InMemoryBleveIndexer) DeleteStale(ctx context.Context, indexedBefore time.Time) (int, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var stale []string
+	b := i.idx.NewBatch()
+	for key, doc := range i.docs {
+		if !doc.IndexedAt.IsZero() && doc.IndexedAt.Before(indexedBefore) {
+			stale = append(stale, key)
+			b.Delete(key)
+		}
+	}
+	if err := i.idx.Batch(b); err != nil {
+		return 0, xerrors.Errorf("delete stale: %w", err)
+	}
+
+	for _, key := range stale {
+		delete(i.docs, key)
+	}
+	return len(stale), nil
+}
+
 func copyDoc(d *index.Document) *index.Document {
 	dcopy := new(index.Document)
 	*dcopy = *d
 	return dcopy
 }
-
-func makeBleveDoc(d *index.Document) bleveDoc {
-	return bleveDoc{
-		Title:    d.Title,
-		Content:  d.Content,
-		PageRank: d.PageRank,
-	}
-}
@@ -0,0 +1,27 @@
+package memindex
+
+import (
+	"Search_Engine/textindexer/index"
+	gc "gopkg.in/check.v1"
+	"testing"
+)
+
+var _ = gc.Suite(new(InMemoryBleveTestSuite))
+
+func Test(t *testing.T) { gc.TestingT(t) }
+
+type InMemoryBleveTestSuite struct {
+	index.SuiteBase
+	idx *InMemoryBleveIndexer
+}
+
+func (s *InMemoryBleveTestSuite) SetUpTest(c *gc.C) {
+	idx, err := NewInMemoryBleveIndexer()
+	c.Assert(err, gc.IsNil)
+	s.SetIndexer(idx)
+	s.idx = idx
+}
+
+func (s *InMemoryBleveTestSuite) TearDownTest(c *gc.C) {
+	c.Assert(s.idx.Close(), gc.IsNil)
+}
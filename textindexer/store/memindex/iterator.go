@@ -2,17 +2,20 @@ package memindex
 
 import (
 	"Search_Engine/textindexer/index"
+	"context"
 	"github.com/blevesearch/bleve"
 )
 
 // bleveIterator implements index.Iterator.
 type bleveIterator struct {
+	ctx       context.Context
 	idx       *InMemoryBleveIndexer
 	searchReq *bleve.SearchRequest
 
 	cumIdx uint64
 	rsIdx  int
 	rs     *bleve.SearchResult
+	facets *index.Facets
 
 	latchedDoc *index.Document
 	lastErr    error
@@ -38,7 +41,7 @@ func (it *bleveIterator) Next() bool {
 	// Do we need to fetch the next batch?
 	if it.rsIdx >= it.rs.Hits.Len() {
 		it.searchReq.From += it.searchReq.Size
-		if it.rs, it.lastErr = it.idx.idx.Search(it.searchReq); it.lastErr != nil {
+		if it.rs, it.lastErr = it.idx.idx.SearchInContext(it.ctx, it.searchReq); it.lastErr != nil {
 			return false
 		}
 
@@ -72,3 +75,8 @@ func (it *bleveIterator) TotalCount() uint64 {
 	}
 	return it.rs.Total
 }
+
+// Facets returns the facet counts for the search results.
+func (it *bleveIterator) Facets() *index.Facets {
+	return it.facets
+}